@@ -10,8 +10,14 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
+	"github.com/spdx/spdx-sbom-generator/pkg/cache"
+	"github.com/spdx/spdx-sbom-generator/pkg/curation"
+	"github.com/spdx/spdx-sbom-generator/pkg/format"
 	"github.com/spdx/spdx-sbom-generator/pkg/handler"
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
 	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/external"
 )
 
 const jsonLogFormat = "json"
@@ -42,14 +48,43 @@ func main() {
 }
 func init() {
 	rootCmd.Flags().StringP("path", "p", ".", "the path to package file or the path to a directory which will be recursively analyzed for the package files (default '.')")
-	rootCmd.Flags().BoolP("include-license-text", "i", false, " Include full license text (default: false)")
+	rootCmd.Flags().BoolP("include-license-text", "i", false, " Include the full text of the SPDX licenses concluded, once per document; the texts of other licenses are always included (default: false)")
 	rootCmd.Flags().StringP("schema", "s", "2.2", "<version> Target schema version (default: '2.2')")
 	rootCmd.Flags().StringP("output-dir", "o", ".", "<output> directory to Write SPDX to file (default: current directory)")
-	rootCmd.Flags().StringP("format", "f", "spdx", "output file format (default: spdx)")
+	rootCmd.Flags().StringP("format", "f", "spdx", "output file format: spdx, json, yaml, rdf (RDF/XML), or csv and tsv for a package list (default: spdx)")
+	rootCmd.Flags().StringSlice("columns", nil, "Columns of the csv and tsv package lists: ecosystem, name, version, purl, supplier, license_declared, license_concluded, checksum, download_location, dependency, parent, spdxid, homepage (default: all but spdxid and homepage)")
 	rootCmd.Flags().StringP("global-settings", "g", "", "Alternate path for the global settings file for Java Maven (default 'mvn settings.xml')")
+	rootCmd.Flags().StringSlice("gradle-configurations", nil, "Java Gradle configurations to list the dependencies of: runtimeClasspath, compileClasspath, testRuntimeClasspath, buildEnvironment (default: runtimeClasspath)")
+	rootCmd.Flags().StringSlice("exclude-scope", nil, "Leave out the dependencies of these scopes: dev, test, optional, build, provided (default: none)")
+	rootCmd.Flags().StringSlice("go-packages", nil, "Go main packages of the build to list the compiled modules of, e.g. ./cmd/app (default: every package, ./...)")
+	rootCmd.Flags().String("goos", "", "GOOS the Go build is compiled for, selects the build-accurate listing (default: the go env value)")
+	rootCmd.Flags().String("goarch", "", "GOARCH the Go build is compiled for, selects the build-accurate listing (default: the go env value)")
+	rootCmd.Flags().StringSlice("go-tags", nil, "Go build tags of the build, selects the build-accurate listing (default: none)")
+	rootCmd.Flags().Bool("analyze-files", false, "Analyze the files of the source tree of the root packages: checksums, file types, verification code and license headers (default: false)")
+	rootCmd.Flags().Float32("license-threshold", helper.DefaultLicenseThreshold, "Confidence, from 0 to 1, a detected license needs to be concluded, the matches below it being only reported in the license comments")
+	rootCmd.Flags().Int("depth", 0, "Levels of dependencies to list from the root packages, 1 lists the direct dependencies only (default: 0, all of them)")
+	rootCmd.Flags().StringSlice("report", nil, "Also write a human-readable report of the documents: html (bom-report.html), markdown (bom-report.md) (default: none)")
+	rootCmd.Flags().Bool("split-modules", false, "Also write one SPDX doc per deployable module of multi-module projects, e.g. Maven jar/war modules; sbomgen has no such option (default: false)")
+	rootCmd.Flags().StringArray("creator", nil, "Creator of the documents besides the tool, as \"Organization: Acme Inc. (sbom@acme.com)\" or \"Person: Jane Doe\", repeatable (default: none)")
+	rootCmd.Flags().String("namespace", "", "Base URI of the document namespaces, under a domain of the creator (default: http://spdx.org/spdxpackages, with a warning)")
+	rootCmd.Flags().String("document-name", "", "Name of the documents (default: the name and version of the root module)")
+	rootCmd.Flags().String("document-comment", "", "Comment of the documents (default: none)")
+	rootCmd.Flags().StringSlice("curations", nil, "Curation files overriding the metadata of the packages they match, by purl or by ecosystem, name and version range (default: none)")
+	rootCmd.Flags().StringSlice("plugins", nil, "Run only these plugins, by slug, if they match the project, as npm,go-mod (default: all of them)")
+	rootCmd.Flags().StringSlice("skip-plugins", nil, "Don't run these plugins, by slug, as yarn (default: none)")
+	rootCmd.Flags().String("cache-dir", cache.DefaultDirectory(), "Directory caching the remote lookups of the plugins, as the PyPI metadata and the NuGet and Gradle checksums, nothing being cached if empty")
+	rootCmd.Flags().Duration("cache-ttl", 0, "Time the remote lookups are cached, as 12h (default: a day for the package metadata, 30 days for the checksums and specs of the released versions)")
+	rootCmd.Flags().Int64("cache-max-size", cache.DefaultMaxSize>>20, "Size, in MB, beyond which the oldest cached lookups are removed, 0 for no limit")
+	rootCmd.Flags().Bool("refresh-cache", false, "Make the remote lookups again rather than use the cached ones, caching them anew (default: false)")
 
 	//rootCmd.MarkFlagRequired("path")
-	cobra.OnInitialize(setupLogger)
+	cobra.OnInitialize(setupLogger, discoverPlugins)
+}
+
+// discoverPlugins adds the external plugins of the PATH, the sbomgen-plugin-* executables,
+// to the built-in ones
+func discoverPlugins() {
+	modules.RegisterPlugins(external.Discover()...)
 }
 
 func parseOutputFormat(formatOption string) models.OutputFormat {
@@ -58,6 +93,14 @@ func parseOutputFormat(formatOption string) models.OutputFormat {
 		return models.OutputFormatSpdx
 	case "json":
 		return models.OutputFormatJson
+	case "csv":
+		return models.OutputFormatCsv
+	case "tsv":
+		return models.OutputFormatTsv
+	case "yaml", "yml":
+		return models.OutputFormatYaml
+	case "rdf", "xml":
+		return models.OutputFormatRdf
 	default:
 		return models.OutputFormatSpdx
 	}
@@ -104,15 +147,106 @@ func generate(cmd *cobra.Command, args []string) {
 		log.Fatalf("Failed to read command option: %v", err)
 	}
 	globalSettingFile := checkOpt("global-settings")
+	analyzeFiles, err := cmd.Flags().GetBool("analyze-files")
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	depth, err := cmd.Flags().GetInt("depth")
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	if depth < 0 {
+		log.Fatalf("Invalid depth %d, it must be 0 or more", depth)
+	}
+	licenseThreshold, err := cmd.Flags().GetFloat32("license-threshold")
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	if licenseThreshold < 0 || licenseThreshold > 1 {
+		log.Fatalf("Invalid license threshold %v, it must be from 0 to 1", licenseThreshold)
+	}
+	splitModules, err := cmd.Flags().GetBool("split-modules")
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	gradleConfigurations, err := cmd.Flags().GetStringSlice("gradle-configurations")
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	goPackages, err := cmd.Flags().GetStringSlice("go-packages")
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	goTags, err := cmd.Flags().GetStringSlice("go-tags")
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	excludeScopes, err := parseScopes(cmd)
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	reports, err := parseReports(cmd)
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	columns, err := parseColumns(cmd)
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+
+	creators, err := parseCreators(cmd)
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	curations, err := parseCurations(cmd)
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	enablePlugins, disablePlugins, err := parsePlugins(cmd)
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	cacheOptions, err := parseCache(cmd)
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	cache.Default = cache.New(cacheOptions)
+	namespace := checkOpt("namespace")
+	if namespace != "" {
+		if err := helper.CheckNamespace(namespace); err != nil {
+			log.Fatalf("Failed to read command option: %v", err)
+		}
+	} else {
+		log.Warnf("No namespace set, the documents are named under http://spdx.org/spdxpackages, which isn't a domain of their creator. Set --namespace to a URI of your own")
+	}
 
 	handler, err := handler.NewSPDX(handler.SPDXSettings{
-		Version:           version,
-		Path:              path,
-		License:           license,
-		OutputDir:         outputDir,
-		Schema:            schema,
-		Format:            format,
-		GlobalSettingFile: globalSettingFile,
+		Version:              version,
+		Path:                 path,
+		License:              license,
+		Depth:                depth,
+		OutputDir:            outputDir,
+		Schema:               schema,
+		Format:               format,
+		GlobalSettingFile:    globalSettingFile,
+		SplitModules:         splitModules,
+		GradleConfigurations: gradleConfigurations,
+		ExcludeScopes:        excludeScopes,
+		GoPackages:           goPackages,
+		GOOS:                 checkOpt("goos"),
+		GOARCH:               checkOpt("goarch"),
+		GoTags:               goTags,
+		AnalyzeFiles:         analyzeFiles,
+		LicenseThreshold:     licenseThreshold,
+		Reports:              reports,
+		Columns:              columns,
+		Creators:             creators,
+		Namespace:            namespace,
+		DocumentName:         checkOpt("document-name"),
+		DocumentComment:      checkOpt("document-comment"),
+		Curations:            curations,
+		EnablePlugins:        enablePlugins,
+		DisablePlugins:       disablePlugins,
 	})
 	if err != nil {
 		log.Fatalf("Failed to initialize command: %v", err)
@@ -124,3 +258,109 @@ func generate(cmd *cobra.Command, args []string) {
 
 	handler.Complete()
 }
+
+func parseScopes(cmd *cobra.Command) ([]models.Scope, error) {
+	names, err := cmd.Flags().GetStringSlice("exclude-scope")
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := make([]models.Scope, 0, len(names))
+	for _, name := range names {
+		scope, err := models.ParseScope(name)
+		if err != nil {
+			return nil, err
+		}
+		scopes = append(scopes, scope)
+	}
+	return scopes, nil
+}
+
+func parseReports(cmd *cobra.Command) ([]format.ReportFormat, error) {
+	names, err := cmd.Flags().GetStringSlice("report")
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]format.ReportFormat, 0, len(names))
+	for _, name := range names {
+		report, err := format.ParseReportFormat(name)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+func parseColumns(cmd *cobra.Command) ([]string, error) {
+	names, err := cmd.Flags().GetStringSlice("columns")
+	if err != nil {
+		return nil, err
+	}
+
+	return format.ParseCSVColumns(names)
+}
+
+func parseCreators(cmd *cobra.Command) ([]string, error) {
+	creators, err := cmd.Flags().GetStringArray("creator")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, creator := range creators {
+		if _, _, _, err := helper.ParseActor(creator, "Person", "Organization", "Tool"); err != nil {
+			return nil, err
+		}
+	}
+	return creators, nil
+}
+
+func parseCurations(cmd *cobra.Command) (curation.Curations, error) {
+	paths, err := cmd.Flags().GetStringSlice("curations")
+	if err != nil {
+		return nil, err
+	}
+
+	return curation.Load(paths...)
+}
+
+func parsePlugins(cmd *cobra.Command) ([]string, []string, error) {
+	enable, err := cmd.Flags().GetStringSlice("plugins")
+	if err != nil {
+		return nil, nil, err
+	}
+	disable, err := cmd.Flags().GetStringSlice("skip-plugins")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := modules.CheckPluginSlugs(append(enable, disable...)); err != nil {
+		return nil, nil, err
+	}
+	return enable, disable, nil
+}
+
+func parseCache(cmd *cobra.Command) (cache.Options, error) {
+	directory, err := cmd.Flags().GetString("cache-dir")
+	if err != nil {
+		return cache.Options{}, err
+	}
+	ttl, err := cmd.Flags().GetDuration("cache-ttl")
+	if err != nil {
+		return cache.Options{}, err
+	}
+	maxSize, err := cmd.Flags().GetInt64("cache-max-size")
+	if err != nil {
+		return cache.Options{}, err
+	}
+	refresh, err := cmd.Flags().GetBool("refresh-cache")
+	if err != nil {
+		return cache.Options{}, err
+	}
+
+	if ttl < 0 || maxSize < 0 {
+		return cache.Options{}, errors.New("the cache TTL and maximum size can't be negative")
+	}
+	return cache.Options{Directory: directory, TTL: ttl, MaxSize: maxSize << 20, Refresh: refresh}, nil
+}
@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/config"
+	"github.com/spdx/spdx-sbom-generator/pkg/runner/options"
+)
+
+// loadConfig reads the configuration file given with --config, or found in the root of the project,
+// and sets the flags of the command it sets and the command line doesn't, but the flags of skip.
+// It returns an empty configuration if there is no file
+func loadConfig(cmd *cobra.Command, skip ...string) (*config.Config, error) {
+	path, err := cmd.Flags().GetString("config")
+	if err != nil {
+		return nil, err
+	}
+	if path == "" {
+		projectPath, err := cmd.Flags().GetString("path")
+		if err != nil {
+			return nil, err
+		}
+		if path = config.Find(projectPath); path == "" {
+			return &config.Config{}, nil
+		}
+	}
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("Reading the options from %s", path)
+
+	// the global settings file of the command line is the one of all the plugins
+	if cmd.Flags().Changed("global-settings") {
+		cfg.PluginSettings = nil
+	}
+
+	for name, value := range cfg.Flags() {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil || flag.Changed || containsString(skip, name) {
+			continue
+		}
+		if err := cmd.Flags().Set(name, value); err != nil {
+			return nil, fmt.Errorf("invalid %s %q in %s: %w", name, value, path, err)
+		}
+	}
+	return cfg, nil
+}
+
+// applyConfig sets the options the command line has no flags for from the configuration, the plugins
+// being set through the plugins and skip-plugins flags
+func applyConfig(cfg *config.Config, opts *options.Options) error {
+	opts.PluginSettings = map[string]options.PluginSettings{}
+	for slug, globalSettingFile := range cfg.GlobalSettingFiles() {
+		if err := options.CheckPluginSlugs([]string{slug}); err != nil {
+			return fmt.Errorf("plugin-settings: %w", err)
+		}
+		opts.PluginSettings[slug] = options.PluginSettings{GlobalSettingFile: globalSettingFile}
+	}
+
+	opts.ExcludePackages = cfg.Exclude.Packages
+	opts.ExcludePaths = cfg.Exclude.Paths
+	// the creators of the command line replace the ones of the configuration
+	if len(opts.Creators) == 0 {
+		opts.Creators = cfg.Creators
+	}
+	opts.RootSupplier = cfg.Supplier.Root
+	opts.DefaultSupplier = cfg.Supplier.Default
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
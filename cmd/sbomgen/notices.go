@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/notices"
+	"github.com/spdx/spdx-sbom-generator/pkg/runner"
+	"github.com/spdx/spdx-sbom-generator/pkg/runner/options"
+)
+
+var noticesCmd = &cobra.Command{
+	Use:   "notices",
+	Short: "Output the third-party notices of the dependencies",
+	Long:  "Output the THIRD_PARTY_NOTICES file of the dependencies, grouped by license with their copyrights and license texts, as text, Markdown or HTML",
+	Run:   generateNotices,
+}
+
+func init() {
+	noticesCmd.Flags().StringP("path", "p", ".", "the path to package file or the path to a directory which will be recursively analyzed for the package files (default '.')")
+	noticesCmd.Flags().StringP("output-dir", "o", "", "<output> directory to write the notices to (default: if not specified, they are written to stdout)")
+	noticesCmd.Flags().StringP("format", "f", "text", "notices format: text, markdown or html (default: text)")
+	noticesCmd.Flags().StringP("template", "t", "", "Go template file to render the notices with instead of the default template of the format")
+	noticesCmd.Flags().StringP("global-settings", "g", "", "Alternate path for the global settings file for Java Maven (default 'mvn settings.xml')")
+	noticesCmd.Flags().Float32("license-threshold", helper.DefaultLicenseThreshold, "Confidence, from 0 to 1, a detected license needs to be concluded, the matches below it being only reported in the license comments")
+	noticesCmd.Flags().StringSlice("exclude-scope", nil, "Leave out the dependencies of these scopes: dev, test, optional, build, provided (default: none)")
+	noticesCmd.Flags().StringSlice("plugins", nil, "Run only these plugins, by slug, if they match the project, as npm,go-mod (default: all of them)")
+	noticesCmd.Flags().StringSlice("skip-plugins", nil, "Don't run these plugins, by slug, as yarn (default: none)")
+
+	rootCmd.AddCommand(noticesCmd)
+}
+
+func generateNotices(cmd *cobra.Command, args []string) {
+	log.Info("Starting to generate the third-party notices ...")
+	// the format of the configuration is the one of the SBOM
+	cfg, err := loadConfig(cmd, "format")
+	if err != nil {
+		log.Fatalf("Failed to read the configuration: %v", err)
+	}
+	checkOpt := func(opt string) string {
+		cmdOpt, err := cmd.Flags().GetString(opt)
+		if err != nil {
+			log.Fatalf("Failed to read command option %v", err)
+		}
+
+		return cmdOpt
+	}
+	format, err := notices.ParseFormat(checkOpt("format"))
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	licenseThreshold, err := cmd.Flags().GetFloat32("license-threshold")
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	if licenseThreshold < 0 || licenseThreshold > 1 {
+		log.Fatalf("Invalid license threshold %v, it must be from 0 to 1", licenseThreshold)
+	}
+	enablePlugins, disablePlugins, err := parsePlugins(cmd)
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	excludeScopes, err := parseScopes(cmd)
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+
+	opts := options.Options{
+		Version:           version,
+		OutputDir:         checkOpt("output-dir"),
+		GlobalSettingFile: checkOpt("global-settings"),
+		Path:              checkOpt("path"),
+		LicenseThreshold:  licenseThreshold,
+		Plugins:           options.DefaultPlugins,
+		EnablePlugins:     enablePlugins,
+		DisablePlugins:    disablePlugins,
+		ExcludeScopes:     excludeScopes,
+	}
+	if err := applyConfig(cfg, &opts); err != nil {
+		log.Fatalf("Failed to read the configuration: %v", err)
+	}
+
+	if err := runner.NewWithOptions(opts).CreateNotices(format, checkOpt("template")); err != nil {
+		log.Fatalf("error creating notices, err: %s", err.Error())
+	}
+}
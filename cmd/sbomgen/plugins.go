@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/opensbom-generator/parsers/plugin"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/runner/options"
+)
+
+var pluginsCmd = &cobra.Command{
+	Use:   "plugins",
+	Short: "Inspect the plugins parsing the package managers",
+}
+
+var pluginsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the plugins and whether they match the project",
+	Long:  "List the plugins, built-in and external, with their slug and manifests, and whether they match the project at the path and run, and why",
+	Run:   listPlugins,
+}
+
+func init() {
+	pluginsListCmd.Flags().StringP("path", "p", ".", "the path to package file or the path to a directory which will be recursively analyzed for the package files (default '.')")
+	pluginsListCmd.Flags().StringSlice("plugins", nil, "Run only these plugins, by slug, if they match the project, as npm,go-mod (default: all of them)")
+	pluginsListCmd.Flags().StringSlice("skip-plugins", nil, "Don't run these plugins, by slug, as yarn (default: none)")
+
+	pluginsCmd.AddCommand(pluginsListCmd)
+	rootCmd.AddCommand(pluginsCmd)
+}
+
+func listPlugins(cmd *cobra.Command, args []string) {
+	if _, err := loadConfig(cmd); err != nil {
+		log.Fatalf("Failed to read the configuration: %v", err)
+	}
+	path, err := cmd.Flags().GetString("path")
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	enablePlugins, disablePlugins, err := parsePlugins(cmd)
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	opts := options.Options{Path: path, EnablePlugins: enablePlugins, DisablePlugins: disablePlugins}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSLUG\tMANIFESTS\tMATCHES\tREASON")
+	for _, p := range options.ListedPlugins() {
+		metadata := p.GetMetadata()
+		matches, reason := pluginMatch(&opts, p)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", metadata.Name, metadata.Slug, strings.Join(metadata.Manifest, ", "), yesNo(matches), reason)
+	}
+	if err := w.Flush(); err != nil {
+		log.Fatalf("Failed to list the plugins: %v", err)
+	}
+}
+
+// pluginMatch tells whether the plugin matches the project of the options, and why, along
+// with whether the options skip it
+func pluginMatch(opts *options.Options, p plugin.Plugin) (bool, string) {
+	metadata := p.GetMetadata()
+	found := []string{}
+	for _, manifest := range metadata.Manifest {
+		// the manifests starting with a dot are extensions, as .csproj
+		pattern := manifest
+		if strings.HasPrefix(manifest, ".") {
+			pattern = "*" + manifest
+		}
+		if matches, _ := filepath.Glob(filepath.Join(opts.Path, pattern)); len(matches) > 0 {
+			found = append(found, manifest)
+		}
+	}
+
+	var reason string
+	matches := p.IsValid(opts.Path)
+	switch {
+	case matches && len(found) > 0:
+		reason = fmt.Sprintf("found %s", strings.Join(found, ", "))
+	case matches:
+		reason = "the plugin accepts the project"
+	case len(found) > 0:
+		reason = fmt.Sprintf("found %s, but the plugin doesn't accept the project", strings.Join(found, ", "))
+	case len(metadata.Manifest) > 0:
+		reason = fmt.Sprintf("no %s", strings.Join(metadata.Manifest, " or "))
+	default:
+		reason = "the plugin doesn't accept the project"
+	}
+	if !opts.PluginEnabled(metadata.Slug) {
+		reason += "; skipped by the options"
+	}
+	return matches, reason
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
@@ -7,6 +7,13 @@ import (
 	"strings"
 
 	log "github.com/sirupsen/logrus"
+	"github.com/spdx/spdx-sbom-generator/pkg/config"
+	"github.com/spdx/spdx-sbom-generator/pkg/curation"
+	"github.com/spdx/spdx-sbom-generator/pkg/externaldocs"
+	"github.com/spdx/spdx-sbom-generator/pkg/externalplugin"
+	"github.com/spdx/spdx-sbom-generator/pkg/format"
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
 	"github.com/spdx/spdx-sbom-generator/pkg/runner"
 	"github.com/spdx/spdx-sbom-generator/pkg/runner/options"
 	"github.com/spf13/cobra"
@@ -37,15 +44,42 @@ func main() {
 	}
 }
 func init() {
+	rootCmd.PersistentFlags().String("config", "", "Configuration file of the options, the flags overriding it (default: the .sbomgen.yaml of the project, if any)")
 	rootCmd.Flags().StringP("path", "p", ".", "the path to package file or the path to a directory which will be recursively analyzed for the package files (default '.')")
-	rootCmd.Flags().BoolP("include-license-text", "i", false, " Include full license text (default: false)")
+	rootCmd.Flags().BoolP("include-license-text", "i", false, " Include the full text of the SPDX licenses concluded, once per document; the texts of other licenses are always included (default: false)")
 	rootCmd.Flags().StringP("schema", "s", "2.3", "<version> Target schema version (default: '2.3')")
 	rootCmd.Flags().StringP("output-dir", "o", "", "<output> directory to write SPDX doc (default: if not specified, doc is written to stdout)")
-	rootCmd.Flags().StringP("format", "f", "spdx", "output file format (default: spdx)")
+	rootCmd.Flags().StringP("format", "f", "spdx", "output file format: spdx, json, yaml, rdf (RDF/XML), or csv and tsv for a package list (default: spdx)")
+	rootCmd.Flags().StringSlice("columns", nil, "Columns of the csv and tsv package lists: ecosystem, name, version, purl, supplier, license_declared, license_concluded, checksum, download_location, dependency, parent, spdxid, homepage (default: all but spdxid and homepage)")
 	rootCmd.Flags().StringP("global-settings", "g", "", "Alternate path for the global settings file for Java Maven (default 'mvn settings.xml')")
+	rootCmd.Flags().Bool("analyze-files", false, "Analyze the files of the source tree of the root packages: checksums, file types, verification code and license headers (default: false)")
+	rootCmd.Flags().Float32("license-threshold", helper.DefaultLicenseThreshold, "Confidence, from 0 to 1, a detected license needs to be concluded, the matches below it being only reported in the license comments")
+	rootCmd.Flags().StringSlice("report", nil, "Also write a human-readable report of the document to the output directory: html (bom-report.html), markdown (bom-report.md) (default: none)")
+	rootCmd.Flags().Int("depth", 0, "Levels of dependencies to list from the root packages, 1 lists the direct dependencies only (default: 0, all of them)")
+	rootCmd.Flags().StringArray("creator", nil, "Creator of the document besides the tool, as \"Organization: Acme Inc. (sbom@acme.com)\" or \"Person: Jane Doe\", repeatable (default: none)")
+	rootCmd.Flags().String("namespace", "", "Base URI of the document namespace, under a domain of the creator (default: https://spdx.org/spdxdocs, with a warning)")
+	rootCmd.Flags().String("document-name", "", "Name of the document (default: the name and version of the root package)")
+	rootCmd.Flags().String("document-comment", "", "Comment of the document (default: none)")
+	rootCmd.Flags().StringSlice("curations", nil, "Curation files overriding the metadata of the packages they match, by purl or by ecosystem, name and version range (default: none)")
+	rootCmd.Flags().StringSlice("exclude-scope", nil, "Leave out the dependencies of these scopes: dev, test, optional, build, provided (default: none)")
+	rootCmd.Flags().StringSlice("plugins", nil, "Run only these plugins, by slug, if they match the project, as npm,go-mod (default: all of them)")
+	rootCmd.Flags().StringSlice("skip-plugins", nil, "Don't run these plugins, by slug, as yarn (default: none)")
+	rootCmd.Flags().String("external-documents", "", "Directory of the SPDX documents of internal dependencies, the document referring to the packages they describe rather than listing them (default: none)")
 
 	//rootCmd.MarkFlagRequired("path")
-	cobra.OnInitialize(setupLogger)
+	cobra.OnInitialize(setupLogger, discoverPlugins)
+}
+
+// discoverPlugins adds the external plugins of the PATH, the sbomgen-plugin-* executables,
+// to the built-in ones
+func discoverPlugins() {
+	for _, p := range externalplugin.Discover() {
+		if err := options.AddExternalPlugin(p); err != nil {
+			log.Warnf("Skipping the external plugin %s: %v", p.Path(), err)
+			continue
+		}
+		log.Debugf("Found the external plugin %s at %s", p.GetMetadata().Slug, p.Path())
+	}
 }
 
 func parseOutputFormat(formatOption string) options.OutputFormat {
@@ -54,6 +88,14 @@ func parseOutputFormat(formatOption string) options.OutputFormat {
 		return options.OutputFormatSpdx
 	case "json":
 		return options.OutputFormatJson
+	case "csv":
+		return options.OutputFormatCsv
+	case "tsv":
+		return options.OutputFormatTsv
+	case "yaml", "yml":
+		return options.OutputFormatYaml
+	case "rdf", "xml":
+		return options.OutputFormatRdf
 	default:
 		return options.OutputFormatSpdx
 	}
@@ -83,6 +125,10 @@ func setupLogger() {
 
 func generate(cmd *cobra.Command, args []string) {
 	log.Info("Starting to generate SPDX ...")
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		log.Fatalf("Failed to read the configuration: %v", err)
+	}
 	checkOpt := func(opt string) string {
 		cmdOpt, err := cmd.Flags().GetString(opt)
 		if err != nil {
@@ -100,19 +146,92 @@ func generate(cmd *cobra.Command, args []string) {
 		log.Fatalf("Failed to read command option: %v", err)
 	}
 	globalSettingFile := checkOpt("global-settings")
+	analyzeFiles, err := cmd.Flags().GetBool("analyze-files")
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	depth, err := cmd.Flags().GetInt("depth")
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	if depth < 0 {
+		log.Fatalf("Invalid depth %d, it must be 0 or more", depth)
+	}
+	licenseThreshold, err := cmd.Flags().GetFloat32("license-threshold")
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	if licenseThreshold < 0 || licenseThreshold > 1 {
+		log.Fatalf("Invalid license threshold %v, it must be from 0 to 1", licenseThreshold)
+	}
+
+	reports, err := parseReports(cmd)
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	columns, err := parseColumns(cmd)
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	creators, err := parseCreators(cmd)
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	curations, err := parseCurations(cmd)
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	enablePlugins, disablePlugins, err := parsePlugins(cmd)
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	excludeScopes, err := parseScopes(cmd)
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	namespace := checkOpt("namespace")
+	if namespace != "" {
+		if err := helper.CheckNamespace(namespace); err != nil {
+			log.Fatalf("Failed to read command option: %v", err)
+		}
+	} else {
+		log.Warnf("No namespace set, the document is named under https://spdx.org/spdxdocs, which isn't a domain of its creator. Set --namespace, or the namespace of the configuration file, to a URI of your own")
+	}
+	if len(reports) > 0 && outputDir == "" {
+		log.Fatalf("The reports are written to the output directory, --report requires --output-dir")
+	}
 
 	opts := options.Options{
 		SchemaVersion:     schema,
 		Indent:            4,
 		Version:           version,
 		License:           license,
-		Depth:             "",
+		Depth:             depth,
 		Slug:              "",
 		OutputDir:         outputDir,
 		Format:            format,
 		GlobalSettingFile: globalSettingFile,
 		Path:              path,
+		AnalyzeFiles:      analyzeFiles,
+		LicenseThreshold:  licenseThreshold,
 		Plugins:           options.DefaultPlugins,
+		Reports:           reports,
+		Columns:           columns,
+		Creators:          creators,
+		Namespace:         namespace,
+		DocumentName:      checkOpt("document-name"),
+		DocumentComment:   checkOpt("document-comment"),
+		Curations:         curations,
+		EnablePlugins:     enablePlugins,
+		DisablePlugins:    disablePlugins,
+		ExcludeScopes:     excludeScopes,
+	}
+	if err := applyConfig(cfg, &opts); err != nil {
+		log.Fatalf("Failed to read the configuration: %v", err)
+	}
+	opts.ExternalDocuments, err = parseExternalDocuments(cmd, cfg)
+	if err != nil {
+		log.Fatalf("Failed to read the external documents: %v", err)
 	}
 
 	err = runner.NewWithOptions(opts).CreateSBOM()
@@ -122,3 +241,97 @@ func generate(cmd *cobra.Command, args []string) {
 	}
 
 }
+
+func parseReports(cmd *cobra.Command) ([]format.ReportFormat, error) {
+	names, err := cmd.Flags().GetStringSlice("report")
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]format.ReportFormat, 0, len(names))
+	for _, name := range names {
+		report, err := format.ParseReportFormat(name)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+func parseColumns(cmd *cobra.Command) ([]string, error) {
+	names, err := cmd.Flags().GetStringSlice("columns")
+	if err != nil {
+		return nil, err
+	}
+
+	return format.ParseCSVColumns(names)
+}
+
+func parseCreators(cmd *cobra.Command) ([]string, error) {
+	creators, err := cmd.Flags().GetStringArray("creator")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, creator := range creators {
+		if _, _, _, err := helper.ParseActor(creator, "Person", "Organization", "Tool"); err != nil {
+			return nil, err
+		}
+	}
+	return creators, nil
+}
+
+func parseCurations(cmd *cobra.Command) (curation.Curations, error) {
+	paths, err := cmd.Flags().GetStringSlice("curations")
+	if err != nil {
+		return nil, err
+	}
+
+	return curation.Load(paths...)
+}
+
+// parsePlugins reads the slugs of the plugins run only, and of the plugins not run
+func parseScopes(cmd *cobra.Command) ([]models.Scope, error) {
+	names, err := cmd.Flags().GetStringSlice("exclude-scope")
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := make([]models.Scope, 0, len(names))
+	for _, name := range names {
+		scope, err := models.ParseScope(name)
+		if err != nil {
+			return nil, err
+		}
+		scopes = append(scopes, scope)
+	}
+	return scopes, nil
+}
+
+func parsePlugins(cmd *cobra.Command) ([]string, []string, error) {
+	enable, err := cmd.Flags().GetStringSlice("plugins")
+	if err != nil {
+		return nil, nil, err
+	}
+	disable, err := cmd.Flags().GetStringSlice("skip-plugins")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := options.CheckPluginSlugs(append(append([]string{}, enable...), disable...)); err != nil {
+		return nil, nil, err
+	}
+	return enable, disable, nil
+}
+
+// parseExternalDocuments reads the external documents of the directory of the command line,
+// and of the mappings of the configuration
+func parseExternalDocuments(cmd *cobra.Command, cfg *config.Config) (*externaldocs.Index, error) {
+	directory, err := cmd.Flags().GetString("external-documents")
+	if err != nil {
+		return nil, err
+	}
+
+	return externaldocs.Load(directory, cfg.ExternalDocuments.Mappings)
+}
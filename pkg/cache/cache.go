@@ -0,0 +1,201 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package cache keeps the responses of the remote lookups of the plugins on disk, as the
+// metadata of PyPI and the checksums of NuGet, so that the runs after the first one are fast
+// and mostly offline. It serves the plugins of pkg/modules, the parsers sbomgen runs making
+// their lookups on their own
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// MetadataTTL is the time the metadata of the packages are cached, as they change, a
+	// release being yanked or a new version published
+	MetadataTTL = 24 * time.Hour
+	// ArtifactTTL is the time the checksums and the specs of the released versions are
+	// cached, as they don't change
+	ArtifactTTL = 30 * 24 * time.Hour
+	// DefaultMaxSize is the size the cache is pruned to, in bytes
+	DefaultMaxSize = 512 << 20
+)
+
+// Options configure a cache
+type Options struct {
+	// Directory holds the cached responses, nothing being cached if empty
+	Directory string
+	// MaxSize is the size, in bytes, beyond which the oldest responses are removed, none if 0
+	MaxSize int64
+	// TTL replaces the times the responses of the lookups are cached for, if set
+	TTL time.Duration
+	// Refresh ignores the cached responses, the lookups being made and cached again
+	Refresh bool
+}
+
+// Cache caches the responses of the lookups by registry and coordinate
+type Cache struct {
+	options Options
+
+	mu sync.Mutex
+	// size is the size of the directory, -1 until measured
+	size int64
+}
+
+// Default is the cache of the plugins, caching nothing until configured
+var Default = New(Options{})
+
+// DefaultDirectory returns the directory of the cache under the cache directory of the user,
+// empty if the user has none
+func DefaultDirectory() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "spdx-sbom-generator")
+}
+
+// New returns a cache with the options
+func New(options Options) *Cache {
+	return &Cache{options: options, size: -1}
+}
+
+// Fetch returns the response cached for the coordinate of the registry, as a package URL or
+// name and version, unless older than ttl. Otherwise it returns the response of fetch, cached
+// unless fetch fails. The cache failing is logged only, the lookup being made anyway
+func (c *Cache) Fetch(registry, coordinate string, ttl time.Duration, fetch func() ([]byte, error)) ([]byte, error) {
+	if c.options.Directory == "" {
+		return fetch()
+	}
+	if c.options.TTL > 0 {
+		ttl = c.options.TTL
+	}
+
+	path := c.path(registry, coordinate)
+	if !c.options.Refresh {
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) < ttl {
+			if data, err := os.ReadFile(path); err == nil {
+				return data, nil
+			}
+		}
+	}
+
+	data, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	if err := c.store(path, data); err != nil {
+		log.Debugf("Failed to cache the %s lookup of %s: %v", registry, coordinate, err)
+	}
+	return data, nil
+}
+
+// path returns the path of the response of the coordinate of the registry
+func (c *Cache) path(registry, coordinate string) string {
+	sum := sha256.Sum256([]byte(coordinate))
+	return filepath.Join(c.options.Directory, registry, hex.EncodeToString(sum[:]))
+}
+
+// store writes the response at path, through a temporary file so that the concurrent lookups
+// read whole responses, and prunes the cache if it outgrew the maximum size
+func (c *Cache) store(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var previous int64
+	if info, err := os.Stat(path); err == nil {
+		previous = info.Size()
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if c.options.MaxSize <= 0 {
+		return nil
+	}
+	if c.size < 0 {
+		c.size = c.measure()
+	} else {
+		c.size += int64(len(data)) - previous
+	}
+	if c.size > c.options.MaxSize {
+		c.prune()
+	}
+	return nil
+}
+
+type entry struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// entries returns the cached responses
+func (c *Cache) entries() []entry {
+	entries := []entry{}
+	_ = filepath.WalkDir(c.options.Directory, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			entries = append(entries, entry{path: path, size: info.Size(), modTime: info.ModTime()})
+		}
+		return nil
+	})
+	return entries
+}
+
+func (c *Cache) measure() int64 {
+	var size int64
+	for _, e := range c.entries() {
+		size += e.size
+	}
+	return size
+}
+
+// prune removes the oldest responses until the cache is under nine tenths of its maximum
+// size, so that it isn't pruned again on the next lookups
+func (c *Cache) prune() {
+	entries := c.entries()
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].modTime.Before(entries[j].modTime)
+	})
+
+	c.size = 0
+	for _, e := range entries {
+		c.size += e.size
+	}
+	for _, e := range entries {
+		if c.size <= c.options.MaxSize/10*9 {
+			break
+		}
+		if err := os.Remove(e.path); err == nil {
+			c.size -= e.size
+		}
+	}
+}
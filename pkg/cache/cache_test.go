@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cache
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// counter returns a lookup answering the response, and the number of lookups made
+func counter(response string, err error) (func() ([]byte, error), *int) {
+	calls := 0
+	return func() ([]byte, error) {
+		calls++
+		if err != nil {
+			return nil, err
+		}
+		return []byte(response), nil
+	}, &calls
+}
+
+func TestFetch(t *testing.T) {
+	c := New(Options{Directory: t.TempDir()})
+	fetch, calls := counter("flask 2.0.1", nil)
+
+	data, err := c.Fetch("pypi", "pypi.org/pypi/flask/2.0.1/json", MetadataTTL, fetch)
+	require.NoError(t, err)
+	assert.Equal(t, "flask 2.0.1", string(data))
+	data, err = c.Fetch("pypi", "pypi.org/pypi/flask/2.0.1/json", MetadataTTL, fetch)
+	require.NoError(t, err)
+	assert.Equal(t, "flask 2.0.1", string(data))
+	assert.Equal(t, 1, *calls)
+
+	// the coordinates are keyed by registry
+	_, err = c.Fetch("rubygems", "pypi.org/pypi/flask/2.0.1/json", MetadataTTL, fetch)
+	require.NoError(t, err)
+	assert.Equal(t, 2, *calls)
+}
+
+func TestFetchExpired(t *testing.T) {
+	c := New(Options{Directory: t.TempDir()})
+	fetch, calls := counter("1.0.0", nil)
+
+	_, err := c.Fetch("nuget", "newtonsoft.json/13.0.1", time.Hour, fetch)
+	require.NoError(t, err)
+	old := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(c.path("nuget", "newtonsoft.json/13.0.1"), old, old))
+
+	_, err = c.Fetch("nuget", "newtonsoft.json/13.0.1", time.Hour, fetch)
+	require.NoError(t, err)
+	assert.Equal(t, 2, *calls)
+
+	// the TTL of the options replaces the one of the lookup
+	require.NoError(t, os.Chtimes(c.path("nuget", "newtonsoft.json/13.0.1"), old, old))
+	c = New(Options{Directory: c.options.Directory, TTL: 3 * time.Hour})
+	_, err = c.Fetch("nuget", "newtonsoft.json/13.0.1", time.Hour, fetch)
+	require.NoError(t, err)
+	assert.Equal(t, 2, *calls)
+}
+
+func TestFetchRefresh(t *testing.T) {
+	dir := t.TempDir()
+	fetch, calls := counter("old", nil)
+	_, err := New(Options{Directory: dir}).Fetch("maven", "junit/junit/4.13.2", ArtifactTTL, fetch)
+	require.NoError(t, err)
+
+	refresh, refreshCalls := counter("new", nil)
+	data, err := New(Options{Directory: dir, Refresh: true}).Fetch("maven", "junit/junit/4.13.2", ArtifactTTL, refresh)
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(data))
+	assert.Equal(t, 1, *refreshCalls)
+
+	// the refreshed response is cached for the next runs
+	data, err = New(Options{Directory: dir}).Fetch("maven", "junit/junit/4.13.2", ArtifactTTL, fetch)
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(data))
+	assert.Equal(t, 1, *calls)
+}
+
+func TestFetchFailure(t *testing.T) {
+	c := New(Options{Directory: t.TempDir()})
+	failing, failingCalls := counter("", errors.New("timeout"))
+	_, err := c.Fetch("pypi", "pypi.org/pypi/flask/2.0.1/json", MetadataTTL, failing)
+	assert.EqualError(t, err, "timeout")
+
+	// the failures aren't cached
+	fetch, calls := counter("flask", nil)
+	data, err := c.Fetch("pypi", "pypi.org/pypi/flask/2.0.1/json", MetadataTTL, fetch)
+	require.NoError(t, err)
+	assert.Equal(t, "flask", string(data))
+	assert.Equal(t, 1, *failingCalls)
+	assert.Equal(t, 1, *calls)
+}
+
+func TestFetchWithoutDirectory(t *testing.T) {
+	c := New(Options{})
+	fetch, calls := counter("flask", nil)
+	for i := 0; i < 2; i++ {
+		_, err := c.Fetch("pypi", "pypi.org/pypi/flask/2.0.1/json", MetadataTTL, fetch)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, *calls)
+}
+
+func TestPrune(t *testing.T) {
+	dir := t.TempDir()
+	c := New(Options{Directory: dir, MaxSize: 250})
+	response := strings.Repeat("x", 100)
+	for i, coordinate := range []string{"a", "b", "c"} {
+		fetch, _ := counter(response, nil)
+		_, err := c.Fetch("maven", coordinate, ArtifactTTL, fetch)
+		require.NoError(t, err)
+		// the responses are stored in order
+		stored := time.Now().Add(time.Duration(i-10) * time.Minute)
+		require.NoError(t, os.Chtimes(c.path("maven", coordinate), stored, stored))
+	}
+
+	fetch, _ := counter(response, nil)
+	_, err := c.Fetch("maven", "d", ArtifactTTL, fetch)
+	require.NoError(t, err)
+
+	files, err := filepath.Glob(filepath.Join(dir, "maven", "*"))
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+	assert.NoFileExists(t, c.path("maven", "a"))
+	assert.NoFileExists(t, c.path("maven", "b"))
+	assert.FileExists(t, c.path("maven", "c"))
+	assert.FileExists(t, c.path("maven", "d"))
+}
@@ -0,0 +1,228 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/externaldocs"
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// Filename is the name of the configuration file looked up in the root of the project
+const Filename = ".sbomgen.yaml"
+
+// Config is the SBOM policy of a project, committed along its sources. The settings
+// named after the command flags apply unless the flags are set
+type Config struct {
+	Schema             string   `yaml:"schema"`
+	Format             string   `yaml:"format"`
+	OutputDir          string   `yaml:"output-dir"`
+	IncludeLicenseText *bool    `yaml:"include-license-text"`
+	AnalyzeFiles       *bool    `yaml:"analyze-files"`
+	LicenseThreshold   *float32 `yaml:"license-threshold"`
+	Depth              *int     `yaml:"depth"`
+	Reports            []string `yaml:"report"`
+	Columns            []string `yaml:"columns"`
+	GlobalSettings     string   `yaml:"global-settings"`
+	Curations          []string `yaml:"curations"`
+
+	// Plugins selects the plugins run, by slug, as the plugins and skip-plugins flags
+	Plugins Plugins `yaml:"plugins"`
+	// Exclude leaves packages and files out of the documents
+	Exclude Exclude `yaml:"exclude"`
+	// Creators are the creators of the documents besides the tool, as "Person: Jane Doe (jane@example.com)"
+	// or "Organization: Acme Inc."
+	Creators []string `yaml:"creators"`
+	// Namespace is the base URI of the namespaces of the documents
+	Namespace       string   `yaml:"namespace"`
+	DocumentName    string   `yaml:"document-name"`
+	DocumentComment string   `yaml:"document-comment"`
+	Supplier        Supplier `yaml:"supplier"`
+	// ExternalDocuments are the SPDX documents of the packages the documents refer to
+	// rather than list, as the SBOMs of internal dependencies
+	ExternalDocuments ExternalDocuments `yaml:"external-documents"`
+	// PluginSettings are the settings of the plugins, by slug
+	PluginSettings map[string]PluginSettings `yaml:"plugin-settings"`
+}
+
+// Plugins enables the plugins of Enable only, if any, and disables the plugins of Disable
+type Plugins struct {
+	Enable  []string `yaml:"enable"`
+	Disable []string `yaml:"disable"`
+}
+
+// Exclude lists the patterns of the packages and the files left out of the documents
+type Exclude struct {
+	// Packages are matched against the name, or name@version, of the packages, as path.Match does
+	Packages []string `yaml:"packages"`
+	// Paths are gitignore patterns of the files left out of the analysis of the files
+	Paths []string `yaml:"paths"`
+	// Scopes are the scopes of the dependencies left out, as dev, as the exclude-scope flags
+	Scopes []string `yaml:"scopes"`
+}
+
+// Supplier sets the suppliers of the packages, as "Organization: Acme Inc."
+type Supplier struct {
+	// Root is the supplier of the root packages, the project itself
+	Root string `yaml:"root"`
+	// Default is the supplier of the packages the plugins find none for
+	Default string `yaml:"default"`
+}
+
+// ExternalDocuments maps packages to the SPDX documents describing them
+type ExternalDocuments struct {
+	// Directory holds SPDX documents, the packages they describe being mapped by name and version,
+	// and by purl
+	Directory string `yaml:"directory"`
+	// Mappings map the packages they select, by purl or by ecosystem, name and version range,
+	// to a package of a document, before the documents of the directory
+	Mappings []externaldocs.Mapping `yaml:"mappings"`
+}
+
+// PluginSettings are the settings of a plugin
+type PluginSettings struct {
+	// GlobalSettings is the global settings file of the plugin, as the Maven settings.xml
+	GlobalSettings string `yaml:"global-settings"`
+}
+
+// Find returns the path of the configuration file of the project at dir, empty if it has none
+func Find(dir string) string {
+	if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+	path := filepath.Join(dir, Filename)
+	if helper.Exists(path) {
+		return path
+	}
+	return ""
+}
+
+// Load reads and checks the configuration file at path, the unknown settings being errors.
+// The relative paths of the file are resolved from its directory
+func Load(path string) (*Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	config := &Config{}
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(config); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	config.resolvePaths(filepath.Dir(path))
+	return config, nil
+}
+
+func (c *Config) resolvePaths(dir string) {
+	resolve := func(path string) string {
+		if path == "" || filepath.IsAbs(path) {
+			return path
+		}
+		return filepath.Join(dir, path)
+	}
+	c.OutputDir = resolve(c.OutputDir)
+	c.GlobalSettings = resolve(c.GlobalSettings)
+	for i := range c.Curations {
+		c.Curations[i] = resolve(c.Curations[i])
+	}
+	c.ExternalDocuments.Directory = resolve(c.ExternalDocuments.Directory)
+	for i := range c.ExternalDocuments.Mappings {
+		c.ExternalDocuments.Mappings[i].Document = resolve(c.ExternalDocuments.Mappings[i].Document)
+	}
+	for slug, settings := range c.PluginSettings {
+		settings.GlobalSettings = resolve(settings.GlobalSettings)
+		c.PluginSettings[slug] = settings
+	}
+}
+
+func (c *Config) validate() error {
+	for _, creator := range c.Creators {
+		if _, _, _, err := helper.ParseActor(creator, "Person", "Organization", "Tool"); err != nil {
+			return fmt.Errorf("creators: %w", err)
+		}
+	}
+	for _, supplier := range []string{c.Supplier.Root, c.Supplier.Default} {
+		if supplier == "" {
+			continue
+		}
+		if _, _, _, err := helper.ParseActor(supplier, "Person", "Organization"); err != nil {
+			return fmt.Errorf("supplier: %w", err)
+		}
+	}
+	if c.Namespace != "" {
+		if err := helper.CheckNamespace(c.Namespace); err != nil {
+			return err
+		}
+	}
+	for _, scope := range c.Exclude.Scopes {
+		if _, err := models.ParseScope(scope); err != nil {
+			return fmt.Errorf("exclude: %w", err)
+		}
+	}
+	return nil
+}
+
+// Flags returns the values of the settings named after the command flags, by flag name,
+// the lists being comma-separated
+func (c *Config) Flags() map[string]string {
+	flags := map[string]string{}
+	set := func(name, value string) {
+		if value != "" {
+			flags[name] = value
+		}
+	}
+	set("schema", c.Schema)
+	set("format", c.Format)
+	set("output-dir", c.OutputDir)
+	set("global-settings", c.GlobalSettings)
+	set("namespace", c.Namespace)
+	set("document-name", c.DocumentName)
+	set("document-comment", c.DocumentComment)
+	set("report", strings.Join(c.Reports, ","))
+	set("columns", strings.Join(c.Columns, ","))
+	set("curations", strings.Join(c.Curations, ","))
+	set("plugins", strings.Join(c.Plugins.Enable, ","))
+	set("skip-plugins", strings.Join(c.Plugins.Disable, ","))
+	set("exclude-scope", strings.Join(c.Exclude.Scopes, ","))
+	set("external-documents", c.ExternalDocuments.Directory)
+	if c.IncludeLicenseText != nil {
+		set("include-license-text", strconv.FormatBool(*c.IncludeLicenseText))
+	}
+	if c.AnalyzeFiles != nil {
+		set("analyze-files", strconv.FormatBool(*c.AnalyzeFiles))
+	}
+	if c.LicenseThreshold != nil {
+		set("license-threshold", strconv.FormatFloat(float64(*c.LicenseThreshold), 'f', -1, 32))
+	}
+	if c.Depth != nil {
+		set("depth", strconv.Itoa(*c.Depth))
+	}
+	return flags
+}
+
+// GlobalSettingFiles returns the global settings files of the plugins that have one, by slug
+func (c *Config) GlobalSettingFiles() map[string]string {
+	files := map[string]string{}
+	for slug, settings := range c.PluginSettings {
+		if settings.GlobalSettings != "" {
+			files[slug] = settings.GlobalSettings
+		}
+	}
+	return files
+}
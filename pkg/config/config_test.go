@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, dir, contents string) string {
+	t.Helper()
+	path := filepath.Join(dir, Filename)
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0644))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := writeConfig(t, dir, `
+format: json
+include-license-text: true
+license-threshold: 0.8
+depth: 2
+report: [html, markdown]
+output-dir: sbom
+curations: [curations.yaml]
+plugins:
+  disable: [npm]
+exclude:
+  packages: ["github.com/acme/*", "lodash@4.17.20"]
+  paths: [testdata/]
+  scopes: [dev, test]
+creators:
+  - "Organization: Acme Inc. (sbom@acme.com)"
+namespace: https://sbom.acme.com/spdxdocs
+document-comment: Built by the release pipeline
+supplier:
+  root: "Organization: Acme Inc."
+external-documents:
+  directory: sboms
+  mappings:
+    - purl: pkg:golang/github.com/acme/core
+      document: /var/sboms/core.spdx.json
+    - name: widgets
+      document: sboms/widgets.spdx
+plugin-settings:
+  Java-Maven:
+    global-settings: /etc/maven/settings.xml
+  Java-Gradle:
+    global-settings: gradle/settings.xml
+`)
+
+	config, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"npm"}, config.Plugins.Disable)
+	assert.Equal(t, []string{"testdata/"}, config.Exclude.Paths)
+	assert.Equal(t, "https://sbom.acme.com/spdxdocs", config.Namespace)
+	assert.Equal(t, "Organization: Acme Inc.", config.Supplier.Root)
+
+	// the relative paths are the ones of the directory of the file
+	assert.Equal(t, filepath.Join(dir, "sbom"), config.OutputDir)
+	assert.Equal(t, "/var/sboms/core.spdx.json", config.ExternalDocuments.Mappings[0].Document)
+	assert.Equal(t, filepath.Join(dir, "sboms", "widgets.spdx"), config.ExternalDocuments.Mappings[1].Document)
+	assert.Equal(t, map[string]string{
+		"Java-Maven":  "/etc/maven/settings.xml",
+		"Java-Gradle": filepath.Join(dir, "gradle", "settings.xml"),
+	}, config.GlobalSettingFiles())
+
+	assert.Equal(t, map[string]string{
+		"format":               "json",
+		"include-license-text": "true",
+		"license-threshold":    "0.8",
+		"depth":                "2",
+		"report":               "html,markdown",
+		"output-dir":           filepath.Join(dir, "sbom"),
+		"curations":            filepath.Join(dir, "curations.yaml"),
+		"skip-plugins":         "npm",
+		"exclude-scope":        "dev,test",
+		"external-documents":   filepath.Join(dir, "sboms"),
+		"namespace":            "https://sbom.acme.com/spdxdocs",
+		"document-comment":     "Built by the release pipeline",
+	}, config.Flags())
+}
+
+func TestLoadErrors(t *testing.T) {
+	for _, contents := range []string{
+		"formats: json\n",
+		"depth: deep\n",
+		"creators: [\"Jane Doe\"]\n",
+		"supplier:\n  default: \"Tool: sbomgen\"\n",
+		"namespace: sbom.acme.com\n",
+		"namespace: https://sbom.acme.com/docs#\n",
+		"exclude:\n  scopes: [devel]\n",
+	} {
+		_, err := Load(writeConfig(t, t.TempDir(), contents))
+		assert.Error(t, err, contents)
+	}
+
+	// an empty file sets nothing
+	config, err := Load(writeConfig(t, t.TempDir(), ""))
+	require.NoError(t, err)
+	assert.Empty(t, config.Flags())
+}
+
+func TestFind(t *testing.T) {
+	dir := t.TempDir()
+	assert.Equal(t, "", Find(dir))
+
+	path := writeConfig(t, dir, "format: json\n")
+	assert.Equal(t, path, Find(dir))
+
+	manifest := filepath.Join(dir, "go.mod")
+	require.NoError(t, os.WriteFile(manifest, []byte("module acme\n"), 0644))
+	assert.Equal(t, path, Find(manifest))
+}
@@ -0,0 +1,213 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package copyright finds the copyright statements of a package, in its
+// README, LICENSE and NOTICE files and in the headers of its source files
+package copyright
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	// the comment markers a statement of a source header starts with
+	commentPrefix = regexp.MustCompile(`^(?:/\*+|\*+|//+|#+|--+|;+|<!--|%+|'|REM\s|\(\*|\{-|"""|''')\s*`)
+	// the comment markers a statement of a source header ends with
+	commentSuffix = regexp.MustCompile(`\s*(?:\*+/|-->|\*\)|-\}|"""|''')\s*$`)
+	// "Copyright", "Copyright (c)", "(c) Copyright", "©"...
+	statementPrefix = regexp.MustCompile(`(?i)^(?:(?:\(c\)|©|&copy;)\s*)?copyright\b[\s:]*|^(?:©|&copy;)\s*`)
+	symbol          = regexp.MustCompile(`(?i)^(?:\(c\)|©|&copy;)[\s,]*`)
+	// a year or a range of years, "2019", "2019-2021", "2019 - present"
+	yearRange       = regexp.MustCompile(`(?i)^(\d{4})(?:\s*[-–]\s*(\d{4}|\d{2}|present|now))?[\s,]*`)
+	allRights       = regexp.MustCompile(`(?i)[\s.,;]*all rights? reserved[\s.]*$`)
+	spaces          = regexp.MustCompile(`\s+`)
+	placeholderText = regexp.MustCompile(`(?i)[<\[{]\s*(?:yyyy|year|name|owner|holder|copyright|author|fullname)`)
+)
+
+// the first words of the prose mentioning copyrights, found in license texts
+var proseWords = map[string]bool{
+	"notice": true, "notices": true, "holder": true, "holders": true, "owner": true, "owners": true,
+	"license": true, "licenses": true, "law": true, "laws": true, "and": true, "and/or": true, "or": true,
+	"of": true, "to": true, "in": true, "is": true, "statement": true, "statements": true,
+	"protection": true, "infringement": true, "claims": true, "text": true, "information": true,
+	"assignment": true, "interest": true, "file": true, "files": true, "header": true, "headers": true,
+	"for": true, "on": true, "may": true,
+}
+
+// Statement is a copyright statement, "Copyright (c) 2019-2021 Foo Inc."
+type Statement struct {
+	// Years are the years of the statement, sorted and without duplicates
+	Years  []int
+	Holder string
+}
+
+// String formats the statement, the consecutive years as ranges
+func (s Statement) String() string {
+	if len(s.Years) == 0 {
+		return fmt.Sprintf("Copyright (c) %s", s.Holder)
+	}
+	return fmt.Sprintf("Copyright (c) %s %s", formatYears(s.Years), s.Holder)
+}
+
+// key identifies the holder of the statement whatever its case, spacing and punctuation
+func (s Statement) key() string {
+	return strings.ToLower(strings.TrimRight(spaces.ReplaceAllString(s.Holder, " "), " .,;:"))
+}
+
+// Parse returns the copyright statements of text, one statement per line
+func Parse(text string) []Statement {
+	statements := []Statement{}
+	for _, line := range strings.Split(text, "\n") {
+		if statement, _, ok := parseLine(line); ok {
+			statements = append(statements, statement)
+		}
+	}
+	return Merge(statements)
+}
+
+// parseLine parses the copyright statement of a line, reporting whether the line is commented
+func parseLine(line string) (Statement, bool, bool) {
+	line = strings.TrimSpace(line)
+	uncommented := commentPrefix.ReplaceAllString(line, "")
+	commented := uncommented != line
+	line = commentSuffix.ReplaceAllString(uncommented, "")
+	prefix := statementPrefix.FindString(line)
+	if prefix == "" {
+		return Statement{}, commented, false
+	}
+	rest := line[len(prefix):]
+	marked := !strings.EqualFold(strings.TrimSpace(strings.TrimRight(prefix, ":")), "copyright")
+
+	statement := Statement{}
+	for {
+		if match := symbol.FindString(rest); match != "" {
+			rest, marked = rest[len(match):], true
+			continue
+		}
+		match := yearRange.FindStringSubmatch(rest)
+		if match == nil {
+			break
+		}
+		rest = rest[len(match[0]):]
+		statement.Years = append(statement.Years, parseYears(match[1], match[2])...)
+	}
+
+	holder := strings.TrimSpace(rest)
+	if len(holder) > 3 && strings.EqualFold(holder[:3], "by ") {
+		holder = holder[3:]
+	}
+	holder = allRights.ReplaceAllString(holder, "")
+	holder = strings.TrimSpace(strings.TrimRight(spaces.ReplaceAllString(holder, " "), " ,;:"))
+	// the full stop ending the statement, unlike the one of "Foo Inc."
+	if strings.HasSuffix(holder, ">.") || strings.HasSuffix(holder, ").") {
+		holder = strings.TrimSuffix(holder, ".")
+	}
+	if holder == "" || placeholderText.MatchString(holder) {
+		return Statement{}, commented, false
+	}
+
+	// "Copyright notice", "copyright holders and contributors"... are prose,
+	// the statements without years nor symbol being told from them by their
+	// capitalized first word
+	if len(statement.Years) == 0 && !marked {
+		first := strings.ToLower(strings.Trim(strings.Fields(holder)[0], ".,;:()"))
+		if proseWords[first] || !startsWithCapital(holder) {
+			return Statement{}, commented, false
+		}
+	}
+
+	statement.Holder = holder
+	return statement, commented, true
+}
+
+// parseYears returns the years from start to end, end being empty, two digits or "present"
+func parseYears(start, end string) []int {
+	from, _ := strconv.Atoi(start)
+	to := from
+	if n, err := strconv.Atoi(end); err == nil {
+		to = n
+		if len(end) == 2 {
+			to = from/100*100 + n
+		}
+	}
+	// a malformed range only stands for its first year
+	if to < from || to-from > 100 {
+		to = from
+	}
+
+	years := make([]int, 0, to-from+1)
+	for year := from; year <= to; year++ {
+		years = append(years, year)
+	}
+	return years
+}
+
+func startsWithCapital(s string) bool {
+	for _, r := range s {
+		return r >= 'A' && r <= 'Z' || r > 0x7f
+	}
+	return false
+}
+
+// formatYears formats sorted years, the consecutive ones as ranges, "2015, 2019-2021"
+func formatYears(years []int) string {
+	ranges := []string{}
+	for i := 0; i < len(years); {
+		j := i
+		for j+1 < len(years) && years[j+1] == years[j]+1 {
+			j++
+		}
+		if i == j {
+			ranges = append(ranges, strconv.Itoa(years[i]))
+		} else {
+			ranges = append(ranges, fmt.Sprintf("%d-%d", years[i], years[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(ranges, ", ")
+}
+
+// Merge merges the statements of the same holder, in the order their holders
+// are first found, and joins their years
+func Merge(statements []Statement) []Statement {
+	merged := []Statement{}
+	index := map[string]int{}
+	for _, statement := range statements {
+		key := statement.key()
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(merged)
+			merged = append(merged, Statement{Holder: statement.Holder, Years: append([]int{}, statement.Years...)})
+			continue
+		}
+		merged[i].Years = append(merged[i].Years, statement.Years...)
+	}
+
+	for i := range merged {
+		merged[i].Years = uniqueYears(merged[i].Years)
+	}
+	return merged
+}
+
+func uniqueYears(years []int) []int {
+	sort.Ints(years)
+	unique := years[:0]
+	for i, year := range years {
+		if i == 0 || year != years[i-1] {
+			unique = append(unique, year)
+		}
+	}
+	return unique
+}
+
+// Text formats the statements one per line, for the SPDX copyright text
+func Text(statements []Statement) string {
+	lines := make([]string, 0, len(statements))
+	for _, statement := range statements {
+		lines = append(lines, statement.String())
+	}
+	return strings.Join(lines, "\n")
+}
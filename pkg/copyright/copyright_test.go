@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package copyright
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{
+			name: "statement with a range of years",
+			text: "Copyright (c) 2019-2021 Foo Inc.",
+			want: []string{"Copyright (c) 2019-2021 Foo Inc."},
+		},
+		{
+			name: "commented source header",
+			text: "// Copyright 2018 The Go Authors. All rights reserved.\n// Use of this source code is governed by a BSD-style",
+			want: []string{"Copyright (c) 2018 The Go Authors"},
+		},
+		{
+			name: "symbol and list of years",
+			text: " * © 2015, 2017 - 2018, by Jane Doe <jane@example.com> */",
+			want: []string{"Copyright (c) 2015, 2017-2018 Jane Doe <jane@example.com>"},
+		},
+		{
+			name: "holders merged whatever their case and punctuation",
+			text: "Copyright (C) 2020 Foo Inc.\n# copyright 2019, 2021 FOO INC\nCopyright 2010 Bar. All right reserved.",
+			want: []string{"Copyright (c) 2019-2021 Foo Inc.", "Copyright (c) 2010 Bar"},
+		},
+		{
+			name: "license prose and placeholders",
+			text: "The above copyright notice and this permission notice shall be included\n" +
+				"copyright holders and contributors\n" +
+				"Copyright notice\n" +
+				"Copyright (c) <year> <copyright holders>\n" +
+				"Copyright [yyyy] [name of copyright owner]",
+			want: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := []string{}
+			for _, statement := range Parse(test.text) {
+				got = append(got, statement.String())
+			}
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestScan(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	write("LICENSE", "MIT License\n\nCopyright (c) 2019 Foo Inc.\n\nPermission is hereby granted")
+	write("NOTICE.txt", "This product includes software developed at\nCopyright 2016 Bar Ltd.")
+	write("main.go", "// Copyright 2021 Foo Inc.\n\npackage main\n")
+	write("internal/util.py", "# Copyright (c) 2020 Baz\n")
+	write("models.go", "package models\n\ntype Module struct {\n\tCopyright string\n}\n")
+	write("vendor/dep/dep.go", "// Copyright 2000 Vendored Dependency\n")
+	write("node_modules/dep/LICENSE", "Copyright 2000 Installed Dependency\n")
+	write("deep.go", "package main\n"+strings.Repeat("\n", headerLines)+"// Copyright 1999 Too Deep\n")
+
+	statements, err := Scan(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "Copyright (c) 2019, 2021 Foo Inc.\nCopyright (c) 2016 Bar Ltd.\nCopyright (c) 2020 Baz", Text(statements))
+}
+
+func TestCollect(t *testing.T) {
+	statements, err := Collect("Microsoft Corporation", "")
+	require.NoError(t, err)
+	assert.Equal(t, "Copyright (c) Microsoft Corporation", Text(statements))
+
+	statements, err = Collect("NOASSERTION", "")
+	require.NoError(t, err)
+	assert.Empty(t, statements)
+}
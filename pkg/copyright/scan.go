@@ -0,0 +1,154 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package copyright
+
+import (
+	"bufio"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// the header lines of a source file searched for statements
+	headerLines = 30
+	// the bytes of a README, LICENSE or NOTICE file searched for statements
+	noticeLength = 1 << 20
+	// the source files searched in a package, the large trees being only sampled
+	maxSourceFiles = 1000
+)
+
+// the names, whatever their extension and case, of the files searched in full
+var noticeNames = []string{"LICENSE", "LICENCE", "COPYING", "COPYRIGHT", "NOTICE", "README"}
+
+// the extensions of the source files whose header is searched
+var sourceExtensions = map[string]bool{
+	".c": true, ".cc": true, ".cpp": true, ".cs": true, ".cxx": true, ".dart": true, ".ex": true,
+	".exs": true, ".fs": true, ".go": true, ".groovy": true, ".h": true, ".hpp": true, ".java": true,
+	".js": true, ".jsx": true, ".kt": true, ".kts": true, ".lua": true, ".m": true, ".mjs": true,
+	".cjs": true, ".php": true, ".pl": true, ".proto": true, ".py": true, ".rb": true, ".rs": true,
+	".scala": true, ".sh": true, ".swift": true, ".ts": true, ".tsx": true, ".vue": true,
+}
+
+// the directories of a package holding the sources of other packages or none at all
+var skippedDirs = map[string]bool{
+	"vendor": true, "node_modules": true, "bower_components": true, "testdata": true, "third_party": true,
+}
+
+// Scan returns the copyright statements found in the README, LICENSE and
+// NOTICE files of the package at dir and in the headers of its source files,
+// those of the files at the root of the package first
+func Scan(dir string) ([]Statement, error) {
+	notices, sources := []string{}, []string{}
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := entry.Name()
+		if entry.IsDir() {
+			if path != dir && (strings.HasPrefix(name, ".") || skippedDirs[name]) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		switch {
+		case isNotice(name):
+			notices = append(notices, path)
+		case sourceExtensions[strings.ToLower(filepath.Ext(name))] && len(sources) < maxSourceFiles:
+			sources = append(sources, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	statements := []Statement{}
+	for _, path := range notices {
+		found, err := scanFile(path, -1, false)
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, found...)
+	}
+	for _, path := range sources {
+		found, err := scanFile(path, headerLines, true)
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, found...)
+	}
+	return Merge(statements), nil
+}
+
+// Collect aggregates the copyright a plugin declared for a package, read from
+// its metadata, with the statements found scanning the package at dir if any
+func Collect(declared, dir string) ([]Statement, error) {
+	statements := Parse(declared)
+	// the copyright of the metadata may only name the holder
+	if declared = strings.TrimSpace(declared); len(statements) == 0 && isHolder(declared) {
+		statements = append(statements, Statement{Holder: declared})
+	}
+
+	if dir == "" {
+		return statements, nil
+	}
+	scanned, err := Scan(dir)
+	if err != nil {
+		return statements, err
+	}
+	return Merge(append(statements, scanned...)), nil
+}
+
+// isHolder reports whether the copyright declared by the metadata of a package is only a holder
+func isHolder(declared string) bool {
+	lower := strings.ToLower(declared)
+	return declared != "" && !strings.Contains(declared, "\n") && !strings.Contains(lower, "copyright") &&
+		lower != "noassertion" && lower != "none"
+}
+
+// isNotice reports whether the file is a README, LICENSE or NOTICE file
+func isNotice(name string) bool {
+	upper := strings.ToUpper(name)
+	for _, notice := range noticeNames {
+		if upper == notice || strings.HasPrefix(upper, notice+".") || strings.HasPrefix(upper, notice+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+// scanFile returns the statements of the first lines of the file at path, every line if lines
+// is negative, only the commented lines of source files holding statements
+func scanFile(path string, lines int, source bool) ([]Statement, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	statements := []Statement{}
+	scanner := bufio.NewScanner(io.LimitReader(f, noticeLength))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for i := 0; (lines < 0 || i < lines) && scanner.Scan(); i++ {
+		line := scanner.Text()
+		// binary files have no statements
+		if strings.ContainsRune(line, 0) {
+			return nil, nil
+		}
+		if statement, commented, ok := parseLine(line); ok && (commented || !source) {
+			statements = append(statements, statement)
+		}
+	}
+	// a line longer than the buffer isn't a statement
+	if err := scanner.Err(); err != nil && err != bufio.ErrTooLong {
+		return nil, err
+	}
+	return statements, nil
+}
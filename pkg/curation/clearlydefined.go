@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package curation
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// clearlyDefinedTypes are the package URL types of the component types of ClearlyDefined
+var clearlyDefinedTypes = map[string]string{
+	"composer": "composer",
+	"crate":    "cargo",
+	"gem":      "gem",
+	"go":       "golang",
+	"maven":    "maven",
+	"npm":      "npm",
+	"nuget":    "nuget",
+	"pod":      "cocoapods",
+	"pypi":     "pypi",
+}
+
+// clearlyDefinedFile is a curation file of ClearlyDefined, the curations of the revisions
+// of a component, https://docs.clearlydefined.io/docs/curation/curation-guidelines
+type clearlyDefinedFile struct {
+	Coordinates struct {
+		Type      string `yaml:"type"`
+		Provider  string `yaml:"provider"`
+		Namespace string `yaml:"namespace"`
+		Name      string `yaml:"name"`
+	} `yaml:"coordinates"`
+	Revisions map[string]struct {
+		Licensed struct {
+			Declared string `yaml:"declared"`
+		} `yaml:"licensed"`
+		Described struct {
+			ProjectWebsite string `yaml:"projectWebsite"`
+			SourceLocation struct {
+				URL string `yaml:"url"`
+			} `yaml:"sourceLocation"`
+		} `yaml:"described"`
+	} `yaml:"revisions"`
+}
+
+// isClearlyDefined tells whether the YAML document is a curation file of ClearlyDefined
+func isClearlyDefined(node *yaml.Node) bool {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	if node.Kind != yaml.MappingNode {
+		return false
+	}
+	for i := 0; i < len(node.Content); i += 2 {
+		if node.Content[i].Value == "coordinates" {
+			return true
+		}
+	}
+	return false
+}
+
+// clearlyDefinedCurations returns the curations of the revisions of a ClearlyDefined curation file,
+// the declared license, the project website and the source location of each
+func clearlyDefinedCurations(node *yaml.Node) (Curations, error) {
+	var f clearlyDefinedFile
+	if err := node.Decode(&f); err != nil {
+		return nil, err
+	}
+
+	coordinates := f.Coordinates
+	purlType, ok := clearlyDefinedTypes[coordinates.Type]
+	if !ok {
+		return nil, fmt.Errorf("unsupported ClearlyDefined type %q", coordinates.Type)
+	}
+	if coordinates.Name == "" {
+		return nil, errors.New("the ClearlyDefined coordinates have no name")
+	}
+
+	// the names are the ones of the plugins, with the namespace
+	name, err := url.PathUnescape(coordinates.Name)
+	if err != nil {
+		return nil, err
+	}
+	if namespace, err := url.PathUnescape(coordinates.Namespace); err == nil && namespace != "" && namespace != "-" {
+		separator := "/"
+		if purlType == "maven" {
+			separator = ":"
+		}
+		name = namespace + separator + name
+	}
+
+	revisions := make([]string, 0, len(f.Revisions))
+	for revision := range f.Revisions {
+		revisions = append(revisions, revision)
+	}
+	sort.Strings(revisions)
+
+	curations := make(Curations, 0, len(revisions))
+	for _, revision := range revisions {
+		curated := f.Revisions[revision]
+		curations = append(curations, Curation{
+			Selector:         Selector{Ecosystem: purlType, Name: name, Version: revision},
+			LicenseDeclared:  curated.Licensed.Declared,
+			Homepage:         curated.Described.ProjectWebsite,
+			DownloadLocation: curated.Described.SourceLocation.URL,
+			Reason:           fmt.Sprintf("ClearlyDefined curation of %s/%s", coordinates.Type, coordinates.Provider),
+		})
+	}
+	return curations, nil
+}
@@ -0,0 +1,270 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package curation overrides or fills the metadata of the packages the plugins find,
+// as their licenses or supplier, from curation files
+package curation
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"gopkg.in/yaml.v3"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+)
+
+const noAssertion = "NOASSERTION"
+
+// Selector selects packages by package URL, or by ecosystem, name and version range
+type Selector struct {
+	// Purl matches the package URL of the packages, of all their versions if it has none
+	Purl string `yaml:"purl"`
+	// Ecosystem matches the plugin slug, as Java-Maven, or the package URL type, as maven,
+	// of the packages if set
+	Ecosystem string `yaml:"ecosystem"`
+	Name      string `yaml:"name"`
+	// Version is a version, or a range of semantic versions as ">=1.2.0 <2.0.0 || 3.0.0",
+	// all the versions matching if empty or "*"
+	Version string `yaml:"version"`
+
+	versions semver.Range
+}
+
+// Curation overrides the metadata of the packages it selects
+type Curation struct {
+	Selector `yaml:",inline"`
+
+	LicenseConcluded string `yaml:"license-concluded"`
+	LicenseDeclared  string `yaml:"license-declared"`
+	// Supplier and Originator are as "Organization: Acme Inc. (sbom@acme.com)" or NOASSERTION
+	Supplier         string `yaml:"supplier"`
+	Originator       string `yaml:"originator"`
+	Homepage         string `yaml:"homepage"`
+	DownloadLocation string `yaml:"download-location"`
+	Comment          string `yaml:"comment"`
+	LicenseComments  string `yaml:"license-comments"`
+
+	// MissingOnly fills the values the package lacks only, the empty and NOASSERTION ones
+	MissingOnly bool `yaml:"missing-only"`
+	// Reason tells auditors why the package is curated
+	Reason string `yaml:"reason"`
+}
+
+// Curations are the curations of curation files, in their order, the last curation
+// matching a package having the last word
+type Curations []Curation
+
+// Package is the metadata of an SPDX package the curations apply to
+type Package struct {
+	Ecosystem string
+	Name      string
+	Version   string
+	Purl      string
+
+	LicenseConcluded string
+	LicenseDeclared  string
+	Supplier         string
+	Originator       string
+	Homepage         string
+	DownloadLocation string
+	Comment          string
+	LicenseComments  string
+}
+
+type file struct {
+	Curations []Curation `yaml:"curations"`
+}
+
+// Load reads the curation files, of curations or in the curation format of ClearlyDefined
+func Load(paths ...string) (Curations, error) {
+	curations := Curations{}
+	for _, path := range paths {
+		fileCurations, err := loadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading the curations %s: %w", path, err)
+		}
+		curations = append(curations, fileCurations...)
+	}
+	return curations, nil
+}
+
+func loadFile(path string) (Curations, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(content, &node); err != nil {
+		return nil, err
+	}
+	var curations Curations
+	if isClearlyDefined(&node) {
+		if curations, err = clearlyDefinedCurations(&node); err != nil {
+			return nil, err
+		}
+	} else {
+		var f file
+		decoder := yaml.NewDecoder(strings.NewReader(string(content)))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		curations = f.Curations
+	}
+
+	for i := range curations {
+		if err := curations[i].check(); err != nil {
+			return nil, fmt.Errorf("curation %d: %w", i+1, err)
+		}
+	}
+	return curations, nil
+}
+
+// Check checks the selector and parses its version range
+func (s *Selector) Check() error {
+	if s.Purl == "" && s.Name == "" {
+		return errors.New("a purl or a name is required")
+	}
+	if s.Purl != "" && !strings.HasPrefix(s.Purl, "pkg:") {
+		return fmt.Errorf("invalid purl %q", s.Purl)
+	}
+	s.versions = nil
+	if s.Version != "" && s.Version != "*" {
+		// the versions that aren't ranges of semantic versions are matched as they are
+		if versions, err := semver.ParseRange(s.Version); err == nil {
+			s.versions = versions
+		}
+	}
+	return nil
+}
+
+// check checks the curation and parses the version range of its selector
+func (c *Curation) check() error {
+	if err := c.Selector.Check(); err != nil {
+		return err
+	}
+	for _, actor := range []string{c.Supplier, c.Originator} {
+		if actor == "" || actor == noAssertion {
+			continue
+		}
+		if _, _, _, err := helper.ParseActor(actor, "Person", "Organization"); err != nil {
+			return err
+		}
+	}
+	for _, license := range []*string{&c.LicenseConcluded, &c.LicenseDeclared} {
+		if err := checkLicense(license); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkLicense normalizes the license expression of a curation. The LicenseRef- licenses are
+// refused since a curation has no text for them, which the document would have to hold
+func checkLicense(license *string) error {
+	if *license == "" || *license == noAssertion || *license == "NONE" {
+		return nil
+	}
+	normalized, _, ok := helper.NormalizeLicenseExpression(*license)
+	if !ok {
+		return fmt.Errorf("invalid license expression %q", *license)
+	}
+	for _, token := range strings.FieldsFunc(normalized, func(r rune) bool { return r == ' ' || r == '(' || r == ')' }) {
+		if strings.HasPrefix(token, "LicenseRef-") {
+			return fmt.Errorf("license %s of %q has no text, only the licenses of the SPDX license list and of other documents can be curated", token, *license)
+		}
+	}
+	*license = normalized
+	return nil
+}
+
+// Matches tells whether the selector selects the package of the ecosystem, name, version and
+// package URL, once checked
+func (s *Selector) Matches(ecosystem, name, version, purl string) bool {
+	if s.Purl != "" {
+		if !strings.Contains(s.Purl, "@") {
+			purl = unversionedPurl(purl)
+		}
+		return purl != "" && purl == s.Purl
+	}
+
+	if s.Name != name {
+		return false
+	}
+	if s.Ecosystem != "" && !strings.EqualFold(s.Ecosystem, ecosystem) && !strings.EqualFold(s.Ecosystem, purlType(purl)) {
+		return false
+	}
+	switch {
+	case s.Version == "" || s.Version == "*":
+		return true
+	case s.versions != nil:
+		parsed, err := semver.ParseTolerant(version)
+		return err == nil && s.versions(parsed)
+	default:
+		return s.Version == version
+	}
+}
+
+// Apply applies the curations matching the package to it, returning the comment of the
+// annotation recording the values changed, empty if none is
+func (c Curations) Apply(pkg *Package) string {
+	comments := []string{}
+	for i := range c {
+		if !c[i].Matches(pkg.Ecosystem, pkg.Name, pkg.Version, pkg.Purl) {
+			continue
+		}
+		if changes := c[i].apply(pkg); len(changes) > 0 {
+			comment := fmt.Sprintf("Curated %s", strings.Join(changes, ", "))
+			if c[i].Reason != "" {
+				comment += ": " + c[i].Reason
+			}
+			comments = append(comments, comment)
+		}
+	}
+	return strings.Join(comments, "\n")
+}
+
+func (c *Curation) apply(pkg *Package) []string {
+	changes := []string{}
+	set := func(name string, field *string, value string) {
+		if value == "" || value == *field {
+			return
+		}
+		if c.MissingOnly && *field != "" && *field != noAssertion {
+			return
+		}
+		changes = append(changes, fmt.Sprintf("%s from %q to %q", name, *field, value))
+		*field = value
+	}
+	set("the concluded license", &pkg.LicenseConcluded, c.LicenseConcluded)
+	set("the declared license", &pkg.LicenseDeclared, c.LicenseDeclared)
+	set("the supplier", &pkg.Supplier, c.Supplier)
+	set("the originator", &pkg.Originator, c.Originator)
+	set("the homepage", &pkg.Homepage, c.Homepage)
+	set("the download location", &pkg.DownloadLocation, c.DownloadLocation)
+	set("the comment", &pkg.Comment, c.Comment)
+	set("the license comments", &pkg.LicenseComments, c.LicenseComments)
+	return changes
+}
+
+// unversionedPurl returns the package URL without its version, qualifiers and subpath
+func unversionedPurl(purl string) string {
+	if i := strings.IndexAny(purl, "?#"); i >= 0 {
+		purl = purl[:i]
+	}
+	if i := strings.LastIndex(purl, "@"); i > strings.LastIndex(purl, "/") {
+		purl = purl[:i]
+	}
+	return purl
+}
+
+// purlType returns the type of the package URL, as maven
+func purlType(purl string) string {
+	purlType, _, _ := strings.Cut(strings.TrimPrefix(purl, "pkg:"), "/")
+	return purlType
+}
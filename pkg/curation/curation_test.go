@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package curation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeCurations(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "curations.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0644))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	curations, err := Load(writeCurations(t, `
+curations:
+  - purl: pkg:maven/org.acme/widgets
+    license-concluded: apache-2.0 or mit
+    reason: no license in the pom
+  - ecosystem: npm
+    name: left-pad
+    version: ">=1.0.0 <1.3.0"
+    supplier: "Person: Jane Doe (jane@example.com)"
+    missing-only: true
+`))
+	require.NoError(t, err)
+	require.Len(t, curations, 2)
+	assert.Equal(t, "no license in the pom", curations[0].Reason)
+	assert.Equal(t, "Apache-2.0 OR MIT", curations[0].LicenseConcluded)
+	assert.NotNil(t, curations[1].versions)
+
+	for _, contents := range []string{
+		"curations:\n  - license-concluded: MIT\n",
+		"curations:\n  - purl: maven/org.acme/widgets\n",
+		"curations:\n  - name: a\n    supplier: Acme\n",
+		"curations:\n  - name: a\n    licence: MIT\n",
+		"curations:\n  - name: a\n    license-concluded: MIT/Apache-2.0\n",
+		"curations:\n  - name: a\n    license-declared: MIT OR LicenseRef-acme\n",
+	} {
+		_, err := Load(writeCurations(t, contents))
+		assert.Error(t, err, contents)
+	}
+}
+
+func TestLoadClearlyDefined(t *testing.T) {
+	curations, err := Load(writeCurations(t, `
+coordinates:
+  name: core
+  namespace: "@babel"
+  provider: npmjs
+  type: npm
+revisions:
+  7.1.0:
+    licensed:
+      declared: MIT
+  7.0.0:
+    described:
+      projectWebsite: https://babeljs.io
+      sourceLocation:
+        type: git
+        url: https://github.com/babel/babel
+`))
+	require.NoError(t, err)
+	assert.Equal(t, Curations{
+		{Selector: Selector{Ecosystem: "npm", Name: "@babel/core", Version: "7.0.0"}, Homepage: "https://babeljs.io", DownloadLocation: "https://github.com/babel/babel", Reason: "ClearlyDefined curation of npm/npmjs"},
+		{Selector: Selector{Ecosystem: "npm", Name: "@babel/core", Version: "7.1.0"}, LicenseDeclared: "MIT", Reason: "ClearlyDefined curation of npm/npmjs"},
+	}, withoutVersions(curations))
+
+	curations, err = Load(writeCurations(t, "coordinates:\n  type: maven\n  provider: mavencentral\n  namespace: org.acme\n  name: widgets\nrevisions:\n  1.0.0:\n    licensed:\n      declared: MIT\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "org.acme:widgets", curations[0].Name)
+}
+
+func TestMatches(t *testing.T) {
+	pkg := &Package{Ecosystem: "Java-Maven", Name: "org.acme:widgets", Version: "1.2.3", Purl: "pkg:maven/org.acme/widgets@1.2.3"}
+	tests := []struct {
+		selector Selector
+		matches  bool
+	}{
+		{Selector{Purl: "pkg:maven/org.acme/widgets@1.2.3"}, true},
+		{Selector{Purl: "pkg:maven/org.acme/widgets"}, true},
+		{Selector{Purl: "pkg:maven/org.acme/widgets@1.2.4"}, false},
+		{Selector{Name: "org.acme:widgets"}, true},
+		{Selector{Name: "org.acme:widgets", Ecosystem: "maven"}, true},
+		{Selector{Name: "org.acme:widgets", Ecosystem: "java-maven"}, true},
+		{Selector{Name: "org.acme:widgets", Ecosystem: "npm"}, false},
+		{Selector{Name: "org.acme:widgets", Version: "*"}, true},
+		{Selector{Name: "org.acme:widgets", Version: ">=1.0.0 <2.0.0"}, true},
+		{Selector{Name: "org.acme:widgets", Version: "<1.2.0 || >=2.0.0"}, false},
+		{Selector{Name: "org.acme:widgets", Version: "1.2.3"}, true},
+		{Selector{Name: "org.acme:widgets", Version: "1.2"}, false},
+	}
+	for _, test := range tests {
+		selector := test.selector
+		require.NoError(t, selector.Check())
+		assert.Equal(t, test.matches, selector.Matches(pkg.Ecosystem, pkg.Name, pkg.Version, pkg.Purl), "%+v", test.selector)
+	}
+
+	// the versions that aren't semantic versions are matched as they are
+	selector := Selector{Name: "app", Version: "v0.0.0-20230627202907-fc5a182b1325"}
+	require.NoError(t, selector.Check())
+	assert.True(t, selector.Matches("", "app", "v0.0.0-20230627202907-fc5a182b1325", ""))
+}
+
+func TestApply(t *testing.T) {
+	curations := Curations{
+		{Selector: Selector{Name: "widgets"}, LicenseConcluded: "MIT", Homepage: "https://acme.org", Reason: "checked the sources"},
+		{Selector: Selector{Name: "widgets"}, Supplier: "Organization: Acme", LicenseDeclared: "Apache-2.0", MissingOnly: true},
+		{Selector: Selector{Name: "gadgets"}, Comment: "unused"},
+	}
+	pkg := &Package{Name: "widgets", LicenseConcluded: "NOASSERTION", LicenseDeclared: "MIT", Supplier: "NOASSERTION", Homepage: "https://acme.org"}
+
+	comment := curations.Apply(pkg)
+	assert.Equal(t, `Curated the concluded license from "NOASSERTION" to "MIT": checked the sources
+Curated the supplier from "NOASSERTION" to "Organization: Acme"`, comment)
+	assert.Equal(t, &Package{Name: "widgets", LicenseConcluded: "MIT", LicenseDeclared: "MIT", Supplier: "Organization: Acme", Homepage: "https://acme.org"}, pkg)
+
+	// nothing changes the second time
+	assert.Equal(t, "", curations.Apply(pkg))
+}
+
+func withoutVersions(curations Curations) Curations {
+	for i := range curations {
+		curations[i].versions = nil
+	}
+	return curations
+}
@@ -0,0 +1,272 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package externaldocs maps packages to the existing SPDX documents describing them, as the SBOMs
+// of internal dependencies, for the documents to refer to these rather than list their packages
+package externaldocs
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spdx/tools-golang/json"
+	"github.com/spdx/tools-golang/rdf"
+	"github.com/spdx/tools-golang/spdx"
+	"github.com/spdx/tools-golang/tagvalue"
+	"github.com/spdx/tools-golang/yaml"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/curation"
+)
+
+// Mapping maps the packages it selects to a package of an SPDX document
+type Mapping struct {
+	curation.Selector `yaml:",inline"`
+
+	// Document is the path of the SPDX document, as tag-value, JSON, YAML or RDF/XML
+	Document string `yaml:"document"`
+	// Element is the SPDX identifier of the package in the document, the package the
+	// document describes if empty
+	Element string `yaml:"element"`
+}
+
+// Document is an external SPDX document the packages refer to
+type Document struct {
+	// ID identifies the document in the documents referring to it, without its DocumentRef- prefix
+	ID        string
+	Path      string
+	Namespace string
+	// SHA1 is the checksum of the document file
+	SHA1 string
+
+	described []*spdx.Package
+}
+
+// Ref refers to a package of an external document
+type Ref struct {
+	Document *Document
+	// Element is the SPDX identifier of the package, without its SPDXRef- prefix
+	Element string
+}
+
+// Index looks the packages up in the mappings, in their order, then in the documents of the directory
+type Index struct {
+	documents []*Document
+	mappings  []indexMapping
+}
+
+type indexMapping struct {
+	selector curation.Selector
+	ref      Ref
+}
+
+// documentExtensions are the extensions of the SPDX documents read from a directory
+var documentExtensions = map[string]bool{".spdx": true, ".json": true, ".yaml": true, ".yml": true, ".rdf": true, ".xml": true}
+
+// invalidIDCharacters are the characters a document reference identifier can't hold
+var invalidIDCharacters = regexp.MustCompile(`[^A-Za-z0-9.-]+`)
+
+// Load reads the documents of the mappings, and the SPDX documents of the directory, if any,
+// mapping the packages they describe by name and version, and by package URL
+func Load(directory string, mappings []Mapping) (*Index, error) {
+	index := &Index{}
+	for i, mapping := range mappings {
+		if err := mapping.Check(); err != nil {
+			return nil, fmt.Errorf("external document mapping %d: %w", i+1, err)
+		}
+		if mapping.Document == "" {
+			return nil, fmt.Errorf("external document mapping %d: a document is required", i+1)
+		}
+		document, err := index.document(mapping.Document)
+		if err != nil {
+			return nil, fmt.Errorf("reading the external document %s: %w", mapping.Document, err)
+		}
+		element, err := document.element(mapping)
+		if err != nil {
+			return nil, fmt.Errorf("external document mapping %d: %w", i+1, err)
+		}
+		index.mappings = append(index.mappings, indexMapping{selector: mapping.Selector, ref: Ref{Document: document, Element: element}})
+	}
+
+	if directory == "" {
+		return index, nil
+	}
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return nil, fmt.Errorf("reading the external documents: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !documentExtensions[strings.ToLower(filepath.Ext(entry.Name()))] {
+			continue
+		}
+		path := filepath.Join(directory, entry.Name())
+		document, err := index.document(path)
+		if err != nil {
+			// the directory can hold other files with the extensions of the documents
+			log.Warnf("Skipping the external document %s: %v", path, err)
+			continue
+		}
+		index.addDescribed(document)
+	}
+	return index, nil
+}
+
+// Lookup returns the package of the external documents the package of the ecosystem, name,
+// version and package URL maps to
+func (i *Index) Lookup(ecosystem, name, version, purl string) (Ref, bool) {
+	for _, mapping := range i.mappings {
+		if mapping.selector.Matches(ecosystem, name, version, purl) {
+			return mapping.ref, true
+		}
+	}
+	return Ref{}, false
+}
+
+// Empty tells whether the index maps no package
+func (i *Index) Empty() bool {
+	return i == nil || len(i.mappings) == 0
+}
+
+// addDescribed maps the packages the document describes by name and version, and by package URL
+func (i *Index) addDescribed(document *Document) {
+	for _, pkg := range document.described {
+		ref := Ref{Document: document, Element: string(pkg.PackageSPDXIdentifier)}
+		for _, selector := range packageSelectors(pkg) {
+			if err := selector.Check(); err == nil {
+				i.mappings = append(i.mappings, indexMapping{selector: selector, ref: ref})
+			}
+		}
+	}
+}
+
+// document returns the document at path, reading it the first time
+func (i *Index) document(path string) (*Document, error) {
+	for _, document := range i.documents {
+		if document.Path == path {
+			return document, nil
+		}
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := read(path, bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	if doc.DocumentNamespace == "" {
+		return nil, errors.New("the document has no namespace")
+	}
+
+	document := &Document{
+		ID:        i.documentID(doc.DocumentName),
+		Path:      path,
+		Namespace: doc.DocumentNamespace,
+		SHA1:      fmt.Sprintf("%x", sha1.Sum(content)),
+		described: describedPackages(doc),
+	}
+	i.documents = append(i.documents, document)
+	return document, nil
+}
+
+// documentID returns a document reference identifier made of the name of the document,
+// unique among the documents of the index
+func (i *Index) documentID(name string) string {
+	base := strings.Trim(invalidIDCharacters.ReplaceAllString(name, "-"), "-")
+	if base == "" {
+		base = "document"
+	}
+	id := base
+	for n := 2; ; n++ {
+		taken := false
+		for _, document := range i.documents {
+			if document.ID == id {
+				taken = true
+				break
+			}
+		}
+		if !taken {
+			return id
+		}
+		id = fmt.Sprintf("%s-%d", base, n)
+	}
+}
+
+// element returns the SPDX identifier of the package of the document the mapping maps to
+func (d *Document) element(mapping Mapping) (string, error) {
+	if mapping.Element != "" {
+		return strings.TrimPrefix(mapping.Element, "SPDXRef-"), nil
+	}
+	switch len(d.described) {
+	case 0:
+		return "", fmt.Errorf("%s describes no package, an element is required", d.Path)
+	case 1:
+		return string(d.described[0].PackageSPDXIdentifier), nil
+	}
+	for _, pkg := range d.described {
+		if pkg.PackageName == mapping.Name {
+			return string(pkg.PackageSPDXIdentifier), nil
+		}
+	}
+	return "", fmt.Errorf("%s describes several packages, an element is required", d.Path)
+}
+
+// read reads the SPDX document in the format of the extension of its path
+func read(path string, content io.Reader) (*spdx.Document, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return json.Read(content)
+	case ".yaml", ".yml":
+		return yaml.Read(content)
+	case ".rdf", ".xml":
+		return rdf.Read(content)
+	default:
+		return tagvalue.Read(content)
+	}
+}
+
+// describedPackages returns the packages the document describes, sorted by SPDX identifier
+func describedPackages(doc *spdx.Document) []*spdx.Package {
+	described := map[string]bool{}
+	for _, relationship := range doc.Relationships {
+		switch {
+		case relationship.Relationship == "DESCRIBES" && relationship.RefA.ElementRefID == doc.SPDXIdentifier:
+			described[string(relationship.RefB.ElementRefID)] = true
+		case relationship.Relationship == "DESCRIBED_BY" && relationship.RefB.ElementRefID == doc.SPDXIdentifier:
+			described[string(relationship.RefA.ElementRefID)] = true
+		}
+	}
+
+	packages := []*spdx.Package{}
+	for _, pkg := range doc.Packages {
+		if described[string(pkg.PackageSPDXIdentifier)] {
+			packages = append(packages, pkg)
+		}
+	}
+	sort.Slice(packages, func(i, j int) bool {
+		return packages[i].PackageSPDXIdentifier < packages[j].PackageSPDXIdentifier
+	})
+	return packages
+}
+
+// packageSelectors returns the selectors of the package, by name and version, and by package URL
+func packageSelectors(pkg *spdx.Package) []curation.Selector {
+	selectors := []curation.Selector{}
+	if pkg.PackageName != "" && pkg.PackageVersion != "" {
+		selectors = append(selectors, curation.Selector{Name: pkg.PackageName, Version: pkg.PackageVersion})
+	}
+	for _, ref := range pkg.PackageExternalReferences {
+		if ref.RefType == "purl" && strings.Contains(ref.Locator, "@") {
+			selectors = append(selectors, curation.Selector{Purl: ref.Locator})
+		}
+	}
+	return selectors
+}
@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package externaldocs
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/curation"
+)
+
+const coreDocument = `{
+	"spdxVersion": "SPDX-2.3",
+	"dataLicense": "CC0-1.0",
+	"SPDXID": "SPDXRef-DOCUMENT",
+	"name": "acme core 1.2.0",
+	"documentNamespace": "https://sbom.acme.org/core-1.2.0",
+	"creationInfo": {"created": "2023-06-01T00:00:00Z", "creators": ["Tool: sbomgen"]},
+	"packages": [
+		{
+			"name": "acme.org/core",
+			"SPDXID": "SPDXRef-Package-core",
+			"versionInfo": "v1.2.0",
+			"downloadLocation": "NOASSERTION",
+			"externalRefs": [{"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl", "referenceLocator": "pkg:golang/acme.org/core@v1.2.0"}]
+		},
+		{
+			"name": "acme.org/util",
+			"SPDXID": "SPDXRef-Package-util",
+			"versionInfo": "v0.1.0",
+			"downloadLocation": "NOASSERTION"
+		}
+	],
+	"relationships": [
+		{"spdxElementId": "SPDXRef-DOCUMENT", "relationshipType": "DESCRIBES", "relatedSpdxElement": "SPDXRef-Package-core"},
+		{"spdxElementId": "SPDXRef-Package-core", "relationshipType": "DEPENDS_ON", "relatedSpdxElement": "SPDXRef-Package-util"}
+	]
+}`
+
+func writeDocument(t *testing.T, dir, name, contents string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0644))
+	return path
+}
+
+func TestLoadDirectory(t *testing.T) {
+	dir := t.TempDir()
+	path := writeDocument(t, dir, "core.spdx.json", coreDocument)
+	writeDocument(t, dir, "package.json", `{"name": "not an SPDX document"}`)
+	writeDocument(t, dir, "README.md", "# SBOMs")
+
+	index, err := Load(dir, nil)
+	require.NoError(t, err)
+	assert.False(t, index.Empty())
+
+	ref, ok := index.Lookup("go-mod", "acme.org/core", "v1.2.0", "")
+	require.True(t, ok)
+	assert.Equal(t, "Package-core", ref.Element)
+	assert.Equal(t, "acme-core-1.2.0", ref.Document.ID)
+	assert.Equal(t, "https://sbom.acme.org/core-1.2.0", ref.Document.Namespace)
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("%x", sha1.Sum(content)), ref.Document.SHA1)
+
+	// by package URL, whatever the name
+	_, ok = index.Lookup("go-mod", "core", "v1.2.0", "pkg:golang/acme.org/core@v1.2.0")
+	assert.True(t, ok)
+
+	// the packages the document doesn't describe, and the other versions, aren't mapped
+	_, ok = index.Lookup("go-mod", "acme.org/util", "v0.1.0", "")
+	assert.False(t, ok)
+	_, ok = index.Lookup("go-mod", "acme.org/core", "v1.3.0", "")
+	assert.False(t, ok)
+}
+
+func TestLoadMappings(t *testing.T) {
+	dir := t.TempDir()
+	path := writeDocument(t, dir, "core.json", coreDocument)
+	other := writeDocument(t, dir, "other.json", coreDocument)
+
+	index, err := Load("", []Mapping{
+		{Selector: curation.Selector{Name: "acme.org/core", Version: ">=1.0.0 <2.0.0"}, Document: path},
+		{Selector: curation.Selector{Name: "acme.org/util"}, Document: other, Element: "SPDXRef-Package-util"},
+	})
+	require.NoError(t, err)
+
+	ref, ok := index.Lookup("", "acme.org/core", "v1.3.0", "")
+	require.True(t, ok)
+	assert.Equal(t, Ref{Document: ref.Document, Element: "Package-core"}, ref)
+	assert.Equal(t, "acme-core-1.2.0", ref.Document.ID)
+
+	ref, ok = index.Lookup("", "acme.org/util", "v0.1.0", "")
+	require.True(t, ok)
+	assert.Equal(t, "Package-util", ref.Element)
+	// the documents of the same name get distinct identifiers
+	assert.Equal(t, "acme-core-1.2.0-2", ref.Document.ID)
+
+	for _, mappings := range [][]Mapping{
+		{{Selector: curation.Selector{Name: "acme.org/core"}}},
+		{{Document: path}},
+		{{Selector: curation.Selector{Name: "acme.org/core"}, Document: filepath.Join(dir, "missing.json")}},
+	} {
+		_, err := Load("", mappings)
+		assert.Error(t, err, "%+v", mappings)
+	}
+}
@@ -0,0 +1,317 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package externalplugin runs the plugins of private ecosystems out of process: the executables
+// named sbomgen-plugin-* on the PATH, answering one JSON request on their standard input with one
+// JSON response on their standard output
+package externalplugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/opensbom-generator/parsers/meta"
+	"github.com/opensbom-generator/parsers/plugin"
+	log "github.com/sirupsen/logrus"
+)
+
+// Prefix is the prefix of the names of the plugin executables, the rest of the name being
+// the default slug of the plugin
+const Prefix = "sbomgen-plugin-"
+
+// ProtocolVersion is the version of the protocol of the requests
+const ProtocolVersion = 1
+
+// NoAssertion is the supplier of the packages the executables give none
+const NoAssertion = "NOASSERTION"
+
+// Timeout is the time the executables have to answer a request, and ListTimeout the time they
+// have to answer the requests for the packages of a project, which may resolve them
+var (
+	Timeout     = time.Minute
+	ListTimeout = 30 * time.Minute
+)
+
+// waitDelay is the time the output of a killed executable is waited for
+const waitDelay = time.Second
+
+// The methods of the requests, one per method of the plugins
+const (
+	MethodGetMetadata         = "getMetadata"
+	MethodIsValid             = "isValid"
+	MethodGetVersion          = "getVersion"
+	MethodGetRootModule       = "getRootModule"
+	MethodListModulesWithDeps = "listModulesWithDeps"
+)
+
+// Request is the request written to the standard input of a plugin executable
+type Request struct {
+	ProtocolVersion   int    `json:"protocolVersion"`
+	Method            string `json:"method"`
+	Path              string `json:"path,omitempty"`
+	GlobalSettingFile string `json:"globalSettingFile,omitempty"`
+}
+
+// Response is the response a plugin executable writes to its standard output, with the
+// field of the method of the request, or the error it failed with
+type Response struct {
+	Metadata *Metadata     `json:"metadata,omitempty"`
+	Valid    bool          `json:"valid,omitempty"`
+	Version  string        `json:"version,omitempty"`
+	Package  *meta.Package `json:"package,omitempty"`
+	// Packages are the root packages, with Root set, and all their dependencies
+	Packages []meta.Package `json:"packages,omitempty"`
+	Error    string         `json:"error,omitempty"`
+}
+
+// Metadata describes a plugin, the slug being the name of its executable without Prefix if empty
+type Metadata struct {
+	Name        string   `json:"name"`
+	Slug        string   `json:"slug"`
+	Manifests   []string `json:"manifests"`
+	ModulePaths []string `json:"modulePaths"`
+}
+
+// Plugin is a plugin executable, one process being run per request
+type Plugin struct {
+	path     string
+	metadata plugin.Metadata
+}
+
+// Discover returns the plugins of the executables named with Prefix on the PATH, the first
+// of the executables of the same name, as exec.LookPath finds. The executables that don't
+// answer the metadata request are skipped
+func Discover() []*Plugin {
+	plugins := []*Plugin{}
+	seen := map[string]bool{}
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name := executableName(entry.Name())
+			if !strings.HasPrefix(name, Prefix) || seen[name] {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if !isExecutable(path) {
+				continue
+			}
+			seen[name] = true
+
+			p, err := New(path)
+			if err != nil {
+				log.Warnf("Skipping the external plugin %s: %v", path, err)
+				continue
+			}
+			plugins = append(plugins, p)
+		}
+	}
+	return plugins
+}
+
+// New returns the plugin of the executable at path, asking it for its metadata
+func New(path string) (*Plugin, error) {
+	p := &Plugin{path: path}
+	response, err := p.call(Request{Method: MethodGetMetadata})
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := Metadata{}
+	if response.Metadata != nil {
+		metadata = *response.Metadata
+	}
+	if metadata.Slug == "" {
+		metadata.Slug = strings.TrimPrefix(executableName(filepath.Base(path)), Prefix)
+	}
+	if metadata.Name == "" {
+		metadata.Name = metadata.Slug
+	}
+	p.metadata = plugin.Metadata{
+		Name:       metadata.Name,
+		Slug:       metadata.Slug,
+		Manifest:   metadata.Manifests,
+		ModulePath: metadata.ModulePaths,
+	}
+	return p, nil
+}
+
+// Path returns the path of the executable of the plugin
+func (p *Plugin) Path() string {
+	return p.path
+}
+
+// GetMetadata returns the metadata the executable answered when discovered
+func (p *Plugin) GetMetadata() plugin.Metadata {
+	return p.metadata
+}
+
+// SetRootModule does nothing, the path being sent with every request
+func (p *Plugin) SetRootModule(path string) error {
+	return nil
+}
+
+// IsValid asks the executable whether the project at path is one of its ecosystem
+func (p *Plugin) IsValid(path string) bool {
+	response, err := p.call(Request{Method: MethodIsValid, Path: path})
+	if err != nil {
+		log.Warnf("The %s plugin failed checking %s: %v", p.metadata.Slug, path, err)
+		return false
+	}
+	return response.Valid
+}
+
+// HasModulesInstalled does nothing, the executable reporting the missing modules when listing them
+func (p *Plugin) HasModulesInstalled(path string) error {
+	return nil
+}
+
+// GetVersion asks the executable for the version of the package manager of its ecosystem
+func (p *Plugin) GetVersion() (string, error) {
+	response, err := p.call(Request{Method: MethodGetVersion})
+	if err != nil {
+		return "", err
+	}
+	return response.Version, nil
+}
+
+// GetRootModule asks the executable for the root package of the project at path
+func (p *Plugin) GetRootModule(path string) (*meta.Package, error) {
+	response, err := p.call(Request{Method: MethodGetRootModule, Path: path})
+	if err != nil {
+		return nil, err
+	}
+	if response.Package == nil {
+		return nil, errors.New("no root package")
+	}
+	response.Package.Root = true
+	defaultSupplier(response.Package)
+	return response.Package, nil
+}
+
+// ListUsedModules lists the packages of the project at path, as ListModulesWithDeps does
+func (p *Plugin) ListUsedModules(path string) ([]meta.Package, error) {
+	return p.ListModulesWithDeps(path, "")
+}
+
+// ListModulesWithDeps asks the executable for the packages of the project at path, and their
+// dependencies, the root packages included
+func (p *Plugin) ListModulesWithDeps(path string, globalSettingFile string) ([]meta.Package, error) {
+	response, err := p.call(Request{Method: MethodListModulesWithDeps, Path: path, GlobalSettingFile: globalSettingFile})
+	if err != nil {
+		return nil, err
+	}
+	root := false
+	for i := range response.Packages {
+		defaultSupplier(&response.Packages[i])
+		root = root || response.Packages[i].Root
+	}
+	if !root {
+		return nil, errors.New("no root package")
+	}
+	return response.Packages, nil
+}
+
+// defaultSupplier sets the supplier the executable left out to NOASSERTION, and the type of
+// the supplier it didn't type to an organization, as the SPDX documents require
+func defaultSupplier(pkg *meta.Package) {
+	switch {
+	case pkg.Supplier.Name == "":
+		pkg.Supplier.Name = NoAssertion
+	case pkg.Supplier.Type == "" && pkg.Supplier.Name != NoAssertion:
+		pkg.Supplier.Type = meta.Organization
+	}
+}
+
+// call runs the executable with the request on its standard input, its standard error being
+// the one of the generator, and reads the response on its standard output. The executable is
+// killed when it doesn't answer in time or answers something else than a response
+func (p *Plugin) call(request Request) (*Response, error) {
+	request.ProtocolVersion = ProtocolVersion
+	input, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := Timeout
+	if request.Method == MethodGetRootModule || request.Method == MethodListModulesWithDeps {
+		timeout = ListTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, p.path)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stderr = os.Stderr
+	// the processes the executable started may keep its output open once it is killed
+	cmd.WaitDelay = waitDelay
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("running %s %s: %w", p.path, request.Method, err)
+	}
+
+	response := &Response{}
+	decoded := make(chan error, 1)
+	go func() {
+		decoded <- json.NewDecoder(stdout).Decode(response)
+	}()
+	var decodeErr error
+	select {
+	case decodeErr = <-decoded:
+	case <-ctx.Done():
+		decodeErr = ctx.Err()
+	}
+	if decodeErr != nil {
+		cmd.Process.Kill()
+	}
+	waitErr := cmd.Wait()
+
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return nil, fmt.Errorf("%s %s: no response within %s", p.path, request.Method, timeout)
+	case waitErr != nil && (decodeErr == nil || errors.Is(decodeErr, io.EOF)):
+		return nil, fmt.Errorf("running %s %s: %w", p.path, request.Method, waitErr)
+	case decodeErr != nil:
+		return nil, fmt.Errorf("reading the %s response of %s: %w", request.Method, p.path, decodeErr)
+	}
+	if response.Error != "" {
+		return nil, fmt.Errorf("%s %s: %s", p.path, request.Method, response.Error)
+	}
+	return response, nil
+}
+
+// executableName returns the name of the executable without its .exe extension on Windows
+func executableName(name string) string {
+	if runtime.GOOS == "windows" {
+		return strings.TrimSuffix(strings.ToLower(name), ".exe")
+	}
+	return name
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(filepath.Ext(path), ".exe")
+	}
+	return info.Mode()&0111 != 0
+}
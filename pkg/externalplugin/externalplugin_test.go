@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package externalplugin
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const acmePlugin = `#!/bin/sh
+# the requests are a single line, without a line break
+IFS= read -r request
+case "$request" in
+*'"protocolVersion":1'*) ;;
+*) echo '{"error": "unsupported protocol"}'; exit 0 ;;
+esac
+case "$request" in
+*'"getMetadata"'*)
+	echo '{"metadata": {"name": "Acme Build", "manifests": ["BUILD.acme"]}}' ;;
+*'"isValid"'*'acme-project'*)
+	echo '{"valid": true}' ;;
+*'"isValid"'*)
+	echo '{"valid": false}' ;;
+*'"getVersion"'*)
+	echo '{"version": "acme 3.1"}' ;;
+*'"getRootModule"'*)
+	echo '{"package": {"name": "app", "version": "1.0.0"}}' ;;
+*'"listModulesWithDeps"'*'"globalSettingFile":"settings.acme"'*)
+	echo '{"packages": [
+	{"name": "app", "version": "1.0.0", "Root": true, "Packages": {"lib": {"name": "lib", "version": "2.0.0"}}},
+	{"name": "lib", "version": "2.0.0", "purl": "pkg:generic/lib@2.0.0", "licenseDeclared": "MIT",
+		"Supplier": {"Type": "Organization", "Name": "Acme Inc."}, "Checksum": {"Algorithm": "SHA256", "Value": "abc"}}
+]}' ;;
+*'"listModulesWithDeps"'*)
+	echo 'no settings' >&2; exit 3 ;;
+esac
+`
+
+func writePlugin(t *testing.T, dir, name, contents string, mode os.FileMode) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(contents), mode))
+	return path
+}
+
+func TestPlugin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test plugin is a shell script")
+	}
+	path := writePlugin(t, t.TempDir(), Prefix+"acme", acmePlugin, 0755)
+
+	p, err := New(path)
+	require.NoError(t, err)
+	assert.Equal(t, "Acme Build", p.GetMetadata().Name)
+	assert.Equal(t, "acme", p.GetMetadata().Slug)
+	assert.Equal(t, []string{"BUILD.acme"}, p.GetMetadata().Manifest)
+
+	assert.True(t, p.IsValid("/src/acme-project"))
+	assert.False(t, p.IsValid("/src/other"))
+
+	version, err := p.GetVersion()
+	require.NoError(t, err)
+	assert.Equal(t, "acme 3.1", version)
+
+	root, err := p.GetRootModule("/src/acme-project")
+	require.NoError(t, err)
+	assert.Equal(t, "app", root.Name)
+	assert.True(t, root.Root)
+
+	packages, err := p.ListModulesWithDeps("/src/acme-project", "settings.acme")
+	require.NoError(t, err)
+	require.Len(t, packages, 2)
+	assert.True(t, packages[0].Root)
+	assert.Equal(t, NoAssertion, packages[0].Supplier.Name)
+	assert.Equal(t, "2.0.0", packages[0].Packages["lib"].Version)
+	assert.Equal(t, "pkg:generic/lib@2.0.0", packages[1].PackageURL)
+	assert.Equal(t, "MIT", packages[1].LicenseDeclared)
+	assert.Equal(t, "Organization: Acme Inc.", packages[1].Supplier.Get())
+	assert.Equal(t, "abc", packages[1].Checksum.String())
+
+	// the failures of the executable are errors
+	_, err = p.ListModulesWithDeps("/src/acme-project", "")
+	assert.Error(t, err)
+}
+
+func TestDiscover(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test plugins are shell scripts")
+	}
+	first, second := t.TempDir(), t.TempDir()
+	writePlugin(t, first, Prefix+"acme", acmePlugin, 0755)
+	writePlugin(t, second, Prefix+"acme", `#!/bin/sh
+echo '{"metadata": {"slug": "shadowed"}}'
+`, 0755)
+	writePlugin(t, second, Prefix+"broken", "#!/bin/sh\nexit 1\n", 0755)
+	writePlugin(t, second, Prefix+"notes.txt", "not executable", 0644)
+	writePlugin(t, second, "sbomgen", "#!/bin/sh\n", 0755)
+	t.Setenv("PATH", first+string(os.PathListSeparator)+second)
+
+	plugins := Discover()
+	require.Len(t, plugins, 1)
+	assert.Equal(t, "acme", plugins[0].GetMetadata().Slug)
+	assert.Equal(t, filepath.Join(first, Prefix+"acme"), plugins[0].Path())
+}
+
+func TestPluginKilled(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test plugins are shell scripts")
+	}
+	dir := t.TempDir()
+	hung := &Plugin{path: writePlugin(t, dir, Prefix+"hung", "#!/bin/sh\nexec sleep 30\n", 0755)}
+	garbled := &Plugin{path: writePlugin(t, dir, Prefix+"garbled", "#!/bin/sh\necho 'not a response'\nexec sleep 30\n", 0755)}
+
+	timeout := Timeout
+	Timeout = 200 * time.Millisecond
+	defer func() { Timeout = timeout }()
+
+	// the executable not answering in time is killed
+	start := time.Now()
+	_, err := hung.GetVersion()
+	assert.ErrorContains(t, err, "no response within")
+	assert.Less(t, time.Since(start), 10*time.Second)
+
+	// the executable answering something else than a response is killed without waiting for it
+	Timeout = time.Minute
+	start = time.Now()
+	_, err = garbled.GetVersion()
+	assert.ErrorContains(t, err, "reading the getVersion response")
+	assert.Less(t, time.Since(start), 10*time.Second)
+}
@@ -0,0 +1,315 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package files analyzes the files of the source tree of a package for the
+// SPDX File elements, PackageVerificationCode and LicenseInfoFromFiles
+package files
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+)
+
+const (
+	gitDir        = ".git"
+	gitignoreFile = ".gitignore"
+	// the header lines searched for SPDX-License-Identifier
+	headerLines = 100
+	// the bytes sniffed for NUL to tell binary files from text ones
+	sniffLength = 8000
+)
+
+// SPDX file types
+const (
+	TypeSource        = "SOURCE"
+	TypeBinary        = "BINARY"
+	TypeArchive       = "ARCHIVE"
+	TypeApplication   = "APPLICATION"
+	TypeAudio         = "AUDIO"
+	TypeImage         = "IMAGE"
+	TypeText          = "TEXT"
+	TypeVideo         = "VIDEO"
+	TypeDocumentation = "DOCUMENTATION"
+	TypeSPDX          = "SPDX"
+	TypeOther         = "OTHER"
+)
+
+var (
+	licenseIdentifierPattern = regexp.MustCompile(`SPDX-License-Identifier:\s*(.+)`)
+	// characters not allowed in SPDX identifiers
+	idReplacer = regexp.MustCompile(`[^A-Za-z0-9.-]+`)
+)
+
+// File is a file of the source tree
+type File struct {
+	// Path is relative to the root of the tree, "./" prefixed as SPDX file names are
+	Path string
+	// ID identifies the file among the files of the tree, see ID
+	ID     string
+	SHA1   string
+	SHA256 string
+	Types  []string
+	// Licenses are the licenses of the SPDX-License-Identifier header of the file
+	Licenses []string
+}
+
+// Analysis is the result of analyzing the files of a source tree
+type Analysis struct {
+	Files []File
+	// VerificationCode is the PackageVerificationCode of the files
+	VerificationCode string
+	// Licenses are the licenses found in all the files
+	Licenses []string
+}
+
+// Analyze walks the source tree at root, leaving out the files ignored by git, the files
+// of excludes, typically the SPDX documents being written, and the files matching one of
+// the gitignore patterns of ignores
+func Analyze(root string, excludes []string, ignores []string) (*Analysis, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	excluded := map[string]bool{}
+	for _, exclude := range excludes {
+		if abs, err := filepath.Abs(exclude); err == nil {
+			excluded[abs] = true
+		}
+	}
+
+	patterns := readIgnoreFile(filepath.Join(root, gitDir, "info", "exclude"), nil)
+	for _, ignore := range ignores {
+		patterns = append(patterns, gitignore.ParsePattern(ignore, nil))
+	}
+	analysis := &Analysis{}
+	err = walk(root, nil, patterns, excluded, analysis)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(analysis.Files, func(i, j int) bool {
+		return analysis.Files[i].Path < analysis.Files[j].Path
+	})
+
+	ids := map[string]int{}
+	for i := range analysis.Files {
+		id := strings.Trim(idReplacer.ReplaceAllString(strings.TrimPrefix(analysis.Files[i].Path, "./"), "-"), "-")
+		if ids[id]++; ids[id] > 1 {
+			id = fmt.Sprintf("%s-%d", id, ids[id])
+		}
+		analysis.Files[i].ID = id
+	}
+
+	licenses := map[string]bool{}
+	sha1s := make([]string, 0, len(analysis.Files))
+	for _, file := range analysis.Files {
+		sha1s = append(sha1s, file.SHA1)
+		for _, license := range file.Licenses {
+			licenses[license] = true
+		}
+	}
+	analysis.VerificationCode = VerificationCode(sha1s)
+	for license := range licenses {
+		analysis.Licenses = append(analysis.Licenses, license)
+	}
+	sort.Strings(analysis.Licenses)
+
+	return analysis, nil
+}
+
+// walk analyzes the files of the directory at path of the tree at root, the
+// patterns of the .gitignore of the directory adding to the ones of its parents
+func walk(root string, path []string, patterns []gitignore.Pattern, excluded map[string]bool, analysis *Analysis) error {
+	dir := filepath.Join(append([]string{root}, path...)...)
+	patterns = append(patterns, readIgnoreFile(filepath.Join(dir, gitignoreFile), path)...)
+	matcher := gitignore.NewMatcher(patterns)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		entryPath := append(append([]string{}, path...), name)
+		if name == gitDir || matcher.Match(entryPath, entry.IsDir()) {
+			continue
+		}
+
+		if entry.IsDir() {
+			if err := walk(root, entryPath, patterns, excluded, analysis); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// symbolic links and other special files aren't part of the package contents
+		if !entry.Type().IsRegular() {
+			continue
+		}
+
+		filePath := filepath.Join(dir, name)
+		if excluded[filePath] {
+			continue
+		}
+
+		file, err := analyzeFile(filePath)
+		if err != nil {
+			return err
+		}
+		file.Path = "./" + strings.Join(entryPath, "/")
+		analysis.Files = append(analysis.Files, file)
+	}
+
+	return nil
+}
+
+// readIgnoreFile reads the patterns of a gitignore file of the directory at path
+func readIgnoreFile(ignoreFile string, path []string) []gitignore.Pattern {
+	data, err := os.ReadFile(ignoreFile)
+	if err != nil {
+		return nil
+	}
+
+	patterns := []gitignore.Pattern{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
+			continue
+		}
+		patterns = append(patterns, gitignore.ParsePattern(line, path))
+	}
+	return patterns
+}
+
+// analyzeFile computes the checksums, the types and the licenses of the file at path
+func analyzeFile(path string) (File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return File{}, err
+	}
+	defer f.Close()
+
+	sha1Hash, sha256Hash := sha1.New(), sha256.New()
+	head := &bytes.Buffer{}
+	if _, err := io.Copy(io.MultiWriter(sha1Hash, sha256Hash, &limitedWriter{w: head, n: sniffLength}), f); err != nil {
+		return File{}, err
+	}
+
+	file := File{
+		SHA1:   hex.EncodeToString(sha1Hash.Sum(nil)),
+		SHA256: hex.EncodeToString(sha256Hash.Sum(nil)),
+		Types:  fileTypes(path, head.Bytes()),
+	}
+	if !isBinary(file.Types) {
+		file.Licenses, err = readLicenseIdentifiers(path)
+		if err != nil {
+			return File{}, err
+		}
+	}
+	return file, nil
+}
+
+// readLicenseIdentifiers returns the licenses of the SPDX-License-Identifier in the header of the file at path
+func readLicenseIdentifiers(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for i := 0; i < headerLines && scanner.Scan(); i++ {
+		if match := licenseIdentifierPattern.FindStringSubmatch(scanner.Text()); match != nil {
+			return ParseLicenseExpression(match[1]), nil
+		}
+	}
+	// a line longer than the buffer isn't a license header
+	if err := scanner.Err(); err != nil && err != bufio.ErrTooLong {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// ParseLicenseExpression returns the licenses of an SPDX license expression,
+// leaving out the operators and the license exceptions
+func ParseLicenseExpression(expression string) []string {
+	// the end of the comment the identifier is written in
+	for _, end := range []string{"*/", "-->", "--}", "*)", "#}"} {
+		if i := strings.Index(expression, end); i >= 0 {
+			expression = expression[:i]
+		}
+	}
+	expression = strings.NewReplacer("(", " ", ")", " ").Replace(expression)
+
+	licenses := []string{}
+	seen := map[string]bool{}
+	fields := strings.Fields(expression)
+	for i := 0; i < len(fields); i++ {
+		switch strings.ToUpper(fields[i]) {
+		case "AND", "OR":
+			continue
+		case "WITH":
+			i++
+			continue
+		}
+		if !seen[fields[i]] {
+			seen[fields[i]] = true
+			licenses = append(licenses, fields[i])
+		}
+	}
+	return licenses
+}
+
+// VerificationCode computes the PackageVerificationCode of the files of the given SHA1s,
+// that is the SHA1 of their sorted SHA1s concatenated
+func VerificationCode(sha1s []string) string {
+	sorted := make([]string, 0, len(sha1s))
+	for _, sum := range sha1s {
+		sorted = append(sorted, strings.ToLower(sum))
+	}
+	sort.Strings(sorted)
+
+	hash := sha1.New()
+	io.WriteString(hash, strings.Join(sorted, ""))
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// ID returns the SPDX identifier, without the SPDXRef- prefix, of a file of the package
+func ID(packageName string, file File) string {
+	return "File-" + strings.Trim(idReplacer.ReplaceAllString(packageName, "-"), "-") + "-" + file.ID
+}
+
+// limitedWriter keeps the first n bytes written to it
+type limitedWriter struct {
+	w io.Writer
+	n int
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if l.n > 0 {
+		keep := p
+		if len(keep) > l.n {
+			keep = keep[:l.n]
+		}
+		l.n -= len(keep)
+		if _, err := l.w.Write(keep); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
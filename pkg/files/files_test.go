@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package files
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFile(t *testing.T, path, contents string) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ".gitignore"), "/out\n*.log\n")
+	writeFile(t, filepath.Join(dir, "main.go"), "// SPDX-License-Identifier: Apache-2.0\n\npackage main\n")
+	writeFile(t, filepath.Join(dir, "lib", ".gitignore"), "generated.go\n")
+	writeFile(t, filepath.Join(dir, "lib", "lib.c"), "/* SPDX-License-Identifier: (MIT OR GPL-2.0-only WITH Linux-syscall-note) */\n")
+	writeFile(t, filepath.Join(dir, "lib", "generated.go"), "package lib\n")
+	writeFile(t, filepath.Join(dir, "lib", "debug.log"), "log\n")
+	writeFile(t, filepath.Join(dir, "out", "app"), "\x00\x01")
+	writeFile(t, filepath.Join(dir, ".git", "HEAD"), "ref: refs/heads/main\n")
+	writeFile(t, filepath.Join(dir, "bom-go-mod.spdx"), "SPDXVersion: SPDX-2.2\n")
+
+	analysis, err := Analyze(dir, []string{filepath.Join(dir, "bom-go-mod.spdx")}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paths := []string{}
+	for _, file := range analysis.Files {
+		paths = append(paths, file.Path)
+	}
+	assert.Equal(t, []string{"./.gitignore", "./lib/.gitignore", "./lib/lib.c", "./main.go"}, paths)
+
+	assert.Equal(t, "lib-lib.c", analysis.Files[2].ID)
+	assert.Equal(t, []string{TypeSource}, analysis.Files[2].Types)
+	assert.Equal(t, []string{"MIT", "GPL-2.0-only"}, analysis.Files[2].Licenses)
+	assert.Equal(t, "d2da73660cca9b37f6839316d56787f602a30ee4", analysis.Files[3].SHA1)
+	assert.Equal(t, []string{"Apache-2.0", "GPL-2.0-only", "MIT"}, analysis.Licenses)
+
+	sha1s := []string{}
+	for _, file := range analysis.Files {
+		sha1s = append(sha1s, file.SHA1)
+	}
+	assert.Equal(t, VerificationCode(sha1s), analysis.VerificationCode)
+
+	// the ignored patterns add to the ones of git
+	analysis, err = Analyze(dir, nil, []string{"lib/", "*.spdx"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths = []string{}
+	for _, file := range analysis.Files {
+		paths = append(paths, file.Path)
+	}
+	assert.Equal(t, []string{"./.gitignore", "./main.go"}, paths)
+}
+
+func TestVerificationCode(t *testing.T) {
+	// the SHA1 of the sorted SHA1s concatenated, whatever their order and case
+	code := VerificationCode([]string{"B", "a"})
+	assert.Equal(t, VerificationCode([]string{"a", "b"}), code)
+	assert.Equal(t, "da23614e02469a0d7c7bd1bdab5c9c474b1904dc", code)
+}
+
+func TestFileTypes(t *testing.T) {
+	assert.Equal(t, []string{TypeSource}, fileTypes("main.go", nil))
+	assert.Equal(t, []string{TypeDocumentation, TypeText}, fileTypes("LICENSE", nil))
+	assert.Equal(t, []string{TypeArchive, TypeBinary}, fileTypes("app.jar", nil))
+	assert.Equal(t, []string{TypeSPDX}, fileTypes("bom.spdx.json", nil))
+	assert.Equal(t, []string{TypeBinary}, fileTypes("app", []byte{0x7f, 'E', 'L', 'F', 0}))
+	assert.Equal(t, []string{TypeText}, fileTypes("go.sum", []byte("h1:")))
+}
@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package files
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+)
+
+// the file types by extension, files of other extensions are either BINARY or TEXT
+var extensionTypes = map[string]string{
+	// source
+	".c": TypeSource, ".cc": TypeSource, ".cpp": TypeSource, ".cs": TypeSource, ".css": TypeSource,
+	".cxx": TypeSource, ".dart": TypeSource, ".ex": TypeSource, ".exs": TypeSource, ".fs": TypeSource,
+	".go": TypeSource, ".groovy": TypeSource, ".h": TypeSource, ".hpp": TypeSource, ".java": TypeSource,
+	".js": TypeSource, ".jsx": TypeSource, ".kt": TypeSource, ".kts": TypeSource, ".less": TypeSource,
+	".lua": TypeSource, ".m": TypeSource, ".mjs": TypeSource, ".cjs": TypeSource, ".php": TypeSource,
+	".pl": TypeSource, ".proto": TypeSource, ".py": TypeSource, ".r": TypeSource, ".rb": TypeSource,
+	".rs": TypeSource, ".s": TypeSource, ".scala": TypeSource, ".scss": TypeSource, ".sh": TypeSource,
+	".sql": TypeSource, ".swift": TypeSource, ".ts": TypeSource, ".tsx": TypeSource, ".vue": TypeSource,
+	".bash": TypeSource, ".ps1": TypeSource, ".bat": TypeSource, ".gradle": TypeSource, ".html": TypeSource,
+	".htm": TypeSource, ".xsl": TypeSource,
+	// documentation
+	".md": TypeDocumentation, ".markdown": TypeDocumentation, ".rst": TypeDocumentation,
+	".adoc": TypeDocumentation, ".pdf": TypeDocumentation, ".doc": TypeDocumentation,
+	".docx": TypeDocumentation, ".1": TypeDocumentation, ".man": TypeDocumentation,
+	// archives
+	".zip": TypeArchive, ".tar": TypeArchive, ".gz": TypeArchive, ".tgz": TypeArchive, ".bz2": TypeArchive,
+	".xz": TypeArchive, ".7z": TypeArchive, ".rar": TypeArchive, ".jar": TypeArchive, ".war": TypeArchive,
+	".ear": TypeArchive, ".whl": TypeArchive, ".gem": TypeArchive, ".nupkg": TypeArchive, ".zst": TypeArchive,
+	// applications
+	".exe": TypeApplication, ".dll": TypeApplication, ".so": TypeApplication, ".dylib": TypeApplication,
+	".apk": TypeApplication, ".msi": TypeApplication, ".wasm": TypeApplication,
+	// media
+	".png": TypeImage, ".jpg": TypeImage, ".jpeg": TypeImage, ".gif": TypeImage, ".bmp": TypeImage,
+	".ico": TypeImage, ".svg": TypeImage, ".webp": TypeImage, ".tiff": TypeImage,
+	".mp3": TypeAudio, ".wav": TypeAudio, ".ogg": TypeAudio, ".flac": TypeAudio,
+	".mp4": TypeVideo, ".mov": TypeVideo, ".avi": TypeVideo, ".mkv": TypeVideo, ".webm": TypeVideo,
+	// SPDX documents
+	".spdx": TypeSPDX,
+}
+
+// names of files which are documentation whatever their extension
+var documentationNames = []string{"README", "LICENSE", "LICENCE", "COPYING", "NOTICE", "CHANGELOG", "AUTHORS", "CONTRIBUTING"}
+
+// fileTypes returns the SPDX types of the file at path whose content starts with head
+func fileTypes(path string, head []byte) []string {
+	name := filepath.Base(path)
+	ext := strings.ToLower(filepath.Ext(name))
+	if strings.HasSuffix(strings.ToLower(name), ".spdx.json") {
+		return []string{TypeSPDX}
+	}
+	if fileType, ok := extensionTypes[ext]; ok {
+		// SVG images are text, the others are binary
+		if fileType == TypeImage && ext != ".svg" {
+			return []string{TypeImage, TypeBinary}
+		}
+		if fileType == TypeArchive || fileType == TypeApplication {
+			return []string{fileType, TypeBinary}
+		}
+		return []string{fileType}
+	}
+
+	upper := strings.ToUpper(strings.TrimSuffix(name, filepath.Ext(name)))
+	for _, documentation := range documentationNames {
+		if upper == documentation {
+			return []string{TypeDocumentation, TypeText}
+		}
+	}
+
+	if bytes.IndexByte(head, 0) >= 0 {
+		return []string{TypeBinary}
+	}
+	return []string{TypeText}
+}
+
+func isBinary(types []string) bool {
+	for _, fileType := range types {
+		if fileType == TypeBinary {
+			return true
+		}
+	}
+	return false
+}
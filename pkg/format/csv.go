@@ -0,0 +1,190 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// CSVColumns are the columns of the CSV and TSV package lists, in their default order
+var CSVColumns = []string{
+	"ecosystem",
+	"name",
+	"version",
+	"purl",
+	"supplier",
+	"license_declared",
+	"license_concluded",
+	"checksum",
+	"download_location",
+	"dependency",
+	"parent",
+}
+
+// csvExtraColumns are the columns that can be selected besides the default ones
+var csvExtraColumns = []string{"spdxid", "homepage"}
+
+// ParseCSVColumns checks the names of the columns selected for the CSV and TSV package lists,
+// all the default columns being listed if none is
+func ParseCSVColumns(names []string) ([]string, error) {
+	if len(names) == 0 {
+		return CSVColumns, nil
+	}
+
+	columns := make([]string, 0, len(names))
+	for _, name := range names {
+		column := strings.ToLower(strings.TrimSpace(name))
+		if !containsString(CSVColumns, column) && !containsString(csvExtraColumns, column) {
+			return nil, fmt.Errorf("unknown column %q, expected one of %s", name, strings.Join(append(CSVColumns, csvExtraColumns...), ", "))
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// CSVSPDXRenderer implements an SPDXRenderer that outputs the packages of SPDX documents,
+// one row per package, for the spreadsheets
+type CSVSPDXRenderer struct {
+	// Comma separates the fields, ',' unless set, '\t' for TSV
+	Comma rune
+	// Columns are the columns listed, CSVColumns unless set
+	Columns []string
+	// Ecosystem is the ecosystem of the packages, the package manager of the plugin,
+	// unless Ecosystems maps their SPDX identifier to theirs
+	Ecosystem  string
+	Ecosystems map[string]string
+}
+
+// RenderDocument writes a header row and a row per package, in the order of the document.
+// The dependency column tells the root packages from their direct and transitive dependencies,
+// and the parent column names the package the dependency is first reached from
+func (c CSVSPDXRenderer) RenderDocument(document models.Document) ([]byte, error) {
+	columns := c.Columns
+	if len(columns) == 0 {
+		columns = CSVColumns
+	}
+
+	packages := map[string]models.Package{}
+	for _, pkg := range document.Packages {
+		packages[pkg.SPDXID] = pkg
+	}
+	dependencies, parents := c.dependencies(document, packages)
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if c.Comma != 0 {
+		w.Comma = c.Comma
+	}
+	if err := w.Write(columns); err != nil {
+		return nil, err
+	}
+	for _, pkg := range document.Packages {
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			row[i] = c.value(pkg, column, dependencies[pkg.SPDXID], packages[parents[pkg.SPDXID]])
+		}
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// dependencies walks the dependency graph breadth-first from the packages the document describes,
+// returning the kind of dependency each package reached is and the package it is reached from
+func (c CSVSPDXRenderer) dependencies(document models.Document, packages map[string]models.Package) (map[string]string, map[string]string) {
+	roots, edges := dependencyEdges(document, func(id string) bool {
+		_, ok := packages[id]
+		return ok
+	})
+
+	dependencies, parents := map[string]string{}, map[string]string{}
+	queue := []string{}
+	for _, root := range roots {
+		if _, ok := dependencies[root]; !ok {
+			dependencies[root] = "root"
+			queue = append(queue, root)
+		}
+	}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, edge := range edges[id] {
+			if _, ok := dependencies[edge.to]; ok {
+				continue
+			}
+			dependencies[edge.to] = "transitive"
+			if dependencies[id] == "root" {
+				dependencies[edge.to] = "direct"
+			}
+			parents[edge.to] = id
+			queue = append(queue, edge.to)
+		}
+	}
+	return dependencies, parents
+}
+
+// value returns the value of the column for the package, empty for NOASSERTION
+func (c CSVSPDXRenderer) value(pkg models.Package, column, dependency string, parent models.Package) string {
+	switch column {
+	case "ecosystem":
+		return c.ecosystem(pkg)
+	case "name":
+		return pkg.PackageName
+	case "version":
+		return pkg.PackageVersion
+	case "purl":
+		return PackageURL(c.ecosystem(pkg), pkg.PackageName, pkg.PackageVersion)
+	case "supplier":
+		return assertedValue(pkg.PackageSupplier)
+	case "license_declared":
+		return assertedValue(pkg.PackageLicenseDeclared)
+	case "license_concluded":
+		return assertedValue(pkg.PackageLicenseConcluded)
+	case "checksum":
+		checksums := make([]string, 0, len(pkg.PackageChecksums))
+		for _, checksum := range pkg.PackageChecksums {
+			checksums = append(checksums, fmt.Sprintf("%s:%s", checksum.Algorithm, checksum.Value))
+		}
+		return strings.Join(checksums, " ")
+	case "download_location":
+		return assertedValue(pkg.PackageDownloadLocation)
+	case "dependency":
+		return dependency
+	case "parent":
+		if parent.PackageName == "" {
+			return ""
+		}
+		if parent.PackageVersion == "" {
+			return parent.PackageName
+		}
+		return parent.PackageName + "@" + parent.PackageVersion
+	case "spdxid":
+		return pkg.SPDXID
+	case "homepage":
+		return assertedValue(pkg.PackageHomePage)
+	}
+	return ""
+}
+
+func (c CSVSPDXRenderer) ecosystem(pkg models.Package) string {
+	if ecosystem, ok := c.Ecosystems[pkg.SPDXID]; ok {
+		return ecosystem
+	}
+	return c.Ecosystem
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestPackageURL(t *testing.T) {
+	tests := []struct {
+		ecosystem, name, version, purl string
+	}{
+		{"go-mod", "github.com/sirupsen/logrus", "v1.9.3", "pkg:golang/github.com/sirupsen/logrus@v1.9.3"},
+		{"npm", "@babel/core", "7.0.0", "pkg:npm/%40babel/core@7.0.0"},
+		{"yarn", "lodash", "4.17.21", "pkg:npm/lodash@4.17.21"},
+		{"Java-Maven", "org.apache.commons:commons-lang3", "3.12.0", "pkg:maven/org.apache.commons/commons-lang3@3.12.0"},
+		{"poetry", "Django_Extensions", "3.2.1", "pkg:pypi/django-extensions@3.2.1"},
+		{"composer", "symfony/console", "v6.0.0", "pkg:composer/symfony/console@v6.0.0"},
+		{"bundler", "rails", "7.0.4", "pkg:gem/rails@7.0.4"},
+		{"nuget", "Newtonsoft.Json", "", "pkg:nuget/Newtonsoft.Json"},
+		{"unknown", "a", "1.0", ""},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.purl, PackageURL(test.ecosystem, test.name, test.version), test.name)
+	}
+}
+
+func TestParseCSVColumns(t *testing.T) {
+	columns, err := ParseCSVColumns(nil)
+	require.NoError(t, err)
+	assert.Equal(t, CSVColumns, columns)
+
+	columns, err = ParseCSVColumns([]string{"Name", " purl", "spdxid"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"name", "purl", "spdxid"}, columns)
+
+	_, err = ParseCSVColumns([]string{"name", "color"})
+	assert.Error(t, err)
+}
+
+func TestCSVSPDXRenderer(t *testing.T) {
+	document := reportDocument
+	document.Packages = append([]models.Package{}, reportDocument.Packages...)
+	document.Packages = append(document.Packages, models.Package{
+		SPDXID:           "SPDXRef-Package-c-3.0",
+		PackageName:      "c",
+		PackageVersion:   "3.0",
+		PackageChecksums: []models.PackageChecksum{{Algorithm: "SHA1", Value: "abc"}},
+	})
+	document.Relationships = append([]models.Relationship{}, reportDocument.Relationships...)
+	document.Relationships = append(document.Relationships, models.Relationship{
+		SPDXElementID: "SPDXRef-Package-b-2.0", RelationshipType: "DEPENDS_ON", RelatedSPDXElement: "SPDXRef-Package-c-3.0",
+	})
+
+	renderer := CSVSPDXRenderer{Ecosystem: "npm", Ecosystems: map[string]string{"SPDXRef-Package-test-1.0": "pip"}}
+	content, err := renderer.RenderDocument(document)
+	require.NoError(t, err)
+	assert.Equal(t, `ecosystem,name,version,purl,supplier,license_declared,license_concluded,checksum,download_location,dependency,parent
+npm,app,,pkg:npm/app,,,Apache-2.0,,,root,
+npm,a,1.0,pkg:npm/a@1.0,Organization: A | Co,,MIT,,,direct,app
+npm,b,2.0,pkg:npm/b@2.0,,MIT,,,,direct,app
+pip,test,1.0,pkg:pypi/test@1.0,,,,,,direct,app
+npm,c,3.0,pkg:npm/c@3.0,,,,SHA1:abc,,transitive,b@2.0
+`, string(content))
+
+	renderer = CSVSPDXRenderer{Comma: '\t', Columns: []string{"name", "spdxid"}}
+	content, err = renderer.RenderDocument(document)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "name\tspdxid\napp\tSPDXRef-Package-app\n")
+}
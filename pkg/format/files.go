@@ -0,0 +1,60 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"github.com/spdx/spdx-sbom-generator/pkg/files"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// addPackageFiles adds the files of the source tree of the module to the document,
+// the package containing them
+func (f *Format) addPackageFiles(document *models.Document, pkg *models.Package, module models.Module) error {
+	dir := module.LocalPath
+	if dir == "" {
+		dir = f.Config.SourcePath
+	}
+
+	// the document being written isn't part of the package
+	analysis, err := files.Analyze(dir, []string{f.Config.Filename}, nil)
+	if err != nil {
+		return err
+	}
+
+	pkg.FilesAnalyzed = true
+	pkg.PackageVerificationCode = &models.PackageVerificationCode{Value: analysis.VerificationCode}
+	pkg.LicenseInfoFromFiles = buildLicenseInfo(analysis.Licenses)
+
+	for _, file := range analysis.Files {
+		spdxFile := models.File{
+			FileName:  file.Path,
+			SPDXID:    "SPDXRef-" + files.ID(module.Name, file),
+			FileTypes: file.Types,
+			Checksums: []models.PackageChecksum{
+				{Algorithm: models.HashAlgoSHA1, Value: file.SHA1},
+				{Algorithm: models.HashAlgoSHA256, Value: file.SHA256},
+			},
+			LicenseConcluded:   noAssertion,
+			LicenseInfoInFiles: buildLicenseInfo(file.Licenses),
+			CopyrightText:      noAssertion,
+		}
+		pkg.Files = append(pkg.Files, spdxFile)
+		pkg.HasFiles = append(pkg.HasFiles, spdxFile.SPDXID)
+		document.Files = append(document.Files, spdxFile)
+		document.Relationships = append(document.Relationships, models.Relationship{
+			SPDXElementID:      pkg.SPDXID,
+			RelatedSPDXElement: spdxFile.SPDXID,
+			RelationshipType:   string(models.Contains),
+		})
+	}
+
+	return nil
+}
+
+// buildLicenseInfo returns the licenses found in files, NOASSERTION if none
+func buildLicenseInfo(licenses []string) []string {
+	if len(licenses) == 0 {
+		return []string{noAssertion}
+	}
+	return licenses
+}
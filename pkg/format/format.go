@@ -10,7 +10,11 @@ import (
 
 	"github.com/go-git/go-git/v5"
 	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
 
+	"github.com/spdx/spdx-sbom-generator/pkg/copyright"
+	"github.com/spdx/spdx-sbom-generator/pkg/curation"
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
 	"github.com/spdx/spdx-sbom-generator/pkg/models"
 )
 
@@ -33,6 +37,29 @@ type Config struct {
 	OutputFormat      models.OutputFormat
 	GetSource         func() []models.Module
 	GlobalSettingFile string
+	// Annotations are the comments the document is annotated with
+	Annotations []string
+	// AnalyzeFiles adds the files of the source tree of the root modules,
+	// found at SourcePath unless their LocalPath is set
+	AnalyzeFiles bool
+	SourcePath   string
+	// IncludeLicenseText adds the canonical texts of the licenses of the SPDX license
+	// list the modules conclude, those of the other licenses being always included
+	IncludeLicenseText bool
+	// Ecosystem is the package manager of the plugin, listed with the packages of the
+	// csv and tsv output formats along with the Columns selected, all of them if empty
+	Ecosystem string
+	Columns   []string
+	// Creators are the creators of the document besides the tool, as "Organization: Acme Inc.",
+	// Namespace the base URI of its namespace, http://spdx.org/spdxpackages unless set, and
+	// DocumentName overrides the name and version of the root module as its name
+	Creators        []string
+	Namespace       string
+	DocumentName    string
+	DocumentComment string
+	// Curations override the metadata of the packages they match, each package curated
+	// being annotated with the values changed
+	Curations curation.Curations
 }
 
 func init() {
@@ -54,17 +81,34 @@ type SPDXRenderer interface {
 
 // Render prepares and generates the final SPDX document in the specified format
 func (f *Format) Render() error {
+	_, err := f.RenderDocument()
+	return err
+}
+
+// RenderDocument works like Render and returns the document written, e.g. for a report of it
+func (f *Format) RenderDocument() (*models.Document, error) {
 	modules := sortModules(f.Config.GetSource())
-	document, err := buildBaseDocument(f.Config.ToolVersion, modules[0])
+	document, err := f.buildBaseDocument(modules[0])
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	err = f.annotateDocumentWithPackages(modules, document)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	f.curatePackages(document)
+
+	for _, comment := range f.Config.Annotations {
+		document.Annotations = append(document.Annotations, f.buildAnnotation(document, document.SPDXID, comment))
+	}
+
+	return document, f.write(document)
+}
+
+// write renders the document in the output format to the output file
+func (f *Format) write(document *models.Document) error {
 	file, err := os.Create(f.Config.Filename)
 	if err != nil {
 		return err
@@ -77,6 +121,14 @@ func (f *Format) Render() error {
 		spdxRenderer = TagValueSPDXRenderer{}
 	case models.OutputFormatJson:
 		spdxRenderer = JsonSPDXRenderer{}
+	case models.OutputFormatCsv:
+		spdxRenderer = CSVSPDXRenderer{Columns: f.Config.Columns, Ecosystem: f.Config.Ecosystem}
+	case models.OutputFormatTsv:
+		spdxRenderer = CSVSPDXRenderer{Comma: '\t', Columns: f.Config.Columns, Ecosystem: f.Config.Ecosystem}
+	case models.OutputFormatYaml:
+		spdxRenderer = YamlSPDXRenderer{}
+	case models.OutputFormatRdf:
+		spdxRenderer = RDFSPDXRenderer{}
 	}
 
 	outputBytes, err := spdxRenderer.RenderDocument(*document)
@@ -91,15 +143,30 @@ func (f *Format) Render() error {
 	return nil
 }
 
-func buildBaseDocument(toolVersion string, module models.Module) (*models.Document, error) {
+func (f *Format) buildBaseDocument(module models.Module) (*models.Document, error) {
+	creators := []string{fmt.Sprintf("Tool: spdx-sbom-generator-%s", f.Config.ToolVersion)}
+	for _, creator := range f.Config.Creators {
+		creatorType, name, email, err := helper.ParseActor(creator, "Person", "Organization", "Tool")
+		if err != nil {
+			return nil, fmt.Errorf("invalid creator: %w", err)
+		}
+		creators = append(creators, helper.FormatActor(creatorType, name, email))
+	}
+
+	name := f.Config.DocumentName
+	if name == "" {
+		name = buildName(module.Name, module.Version)
+	}
+
 	return &models.Document{
 		SPDXVersion:       "SPDX-2.2",
 		DataLicense:       "CC0-1.0",
 		SPDXID:            "SPDXRef-DOCUMENT",
-		DocumentName:      buildName(module.Name, module.Version),
-		DocumentNamespace: buildNamespace(module.Name, module.Version),
+		DocumentName:      name,
+		DocumentNamespace: buildNamespace(f.Config.Namespace, module.Name, module.Version),
+		DocumentComment:   f.Config.DocumentComment,
 		CreationInfo: models.CreationInfo{
-			Creators: []string{fmt.Sprintf("Tool: spdx-sbom-generator-%s", toolVersion)},
+			Creators: creators,
 			Created:  time.Now().UTC().Format(time.RFC3339),
 		},
 		Packages:                []models.Package{},
@@ -108,10 +175,46 @@ func buildBaseDocument(toolVersion string, module models.Module) (*models.Docume
 	}, nil
 }
 
+// curatePackages applies the curations to the packages of the document
+func (f *Format) curatePackages(document *models.Document) {
+	for i := range document.Packages {
+		pkg := &document.Packages[i]
+		curated := curation.Package{
+			Ecosystem:        f.Config.Ecosystem,
+			Name:             pkg.PackageName,
+			Version:          pkg.PackageVersion,
+			Purl:             PackageURL(f.Config.Ecosystem, pkg.PackageName, pkg.PackageVersion),
+			LicenseConcluded: pkg.PackageLicenseConcluded,
+			LicenseDeclared:  pkg.PackageLicenseDeclared,
+			Supplier:         pkg.PackageSupplier,
+			Originator:       pkg.PackageOriginator,
+			Homepage:         pkg.PackageHomePage,
+			DownloadLocation: pkg.PackageDownloadLocation,
+			Comment:          pkg.PackageComment,
+			LicenseComments:  pkg.PackageLicenseComments,
+		}
+		comment := f.Config.Curations.Apply(&curated)
+		if comment == "" {
+			continue
+		}
+
+		pkg.PackageLicenseConcluded = curated.LicenseConcluded
+		pkg.PackageLicenseDeclared = curated.LicenseDeclared
+		pkg.PackageSupplier = curated.Supplier
+		pkg.PackageOriginator = curated.Originator
+		pkg.PackageHomePage = curated.Homepage
+		pkg.PackageDownloadLocation = curated.DownloadLocation
+		pkg.PackageComment = curated.Comment
+		pkg.PackageLicenseComments = curated.LicenseComments
+		pkg.Annotations = append(pkg.Annotations, f.buildAnnotation(document, pkg.SPDXID, comment))
+	}
+}
+
 // WIP
 func (f *Format) annotateDocumentWithPackages(modules []models.Module, document *models.Document) error {
 	for _, module := range modules {
 		pkg, err := f.convertToPackage(module)
+		pkg.PackageCopyrightText = buildCopyrightText(module)
 		if pkg.RootPackage {
 			document.Relationships = append(document.Relationships, models.Relationship{
 				SPDXElementID:      document.SPDXID,
@@ -127,27 +230,85 @@ func (f *Format) annotateDocumentWithPackages(modules []models.Module, document
 			if err != nil {
 				return fmt.Errorf("failed to convert submodule %w", err)
 			}
-			document.Relationships = append(document.Relationships, models.Relationship{
+			relationship := models.Relationship{
 				SPDXElementID:      pkg.SPDXID,
 				RelatedSPDXElement: subPkg.SPDXID,
-				RelationshipType:   "DEPENDS_ON",
-			})
+				RelationshipType:   string(subMod.GetRelationship()),
+			}
+			if subMod.GetRelationship().Reversed() {
+				relationship.SPDXElementID, relationship.RelatedSPDXElement = subPkg.SPDXID, pkg.SPDXID
+			}
+			document.Relationships = append(document.Relationships, relationship)
 		}
-		for licence := range module.OtherLicense {
-			document.ExtractedLicensingInfos = append(document.ExtractedLicensingInfos, models.ExtractedLicensingInfo{
-				LicenseID:      module.OtherLicense[licence].ID,
-				ExtractedText:  module.OtherLicense[licence].ExtractedText,
-				LicenseName:    module.OtherLicense[licence].Name,
-				LicenseComment: module.OtherLicense[licence].Comments,
-			})
+		otherLicenses := append([]*models.License{}, module.OtherLicense...)
+		if f.Config.IncludeLicenseText {
+			otherLicenses = append(otherLicenses, helper.CanonicalLicenses(pkg.PackageLicenseConcluded)...)
+			otherLicenses = append(otherLicenses, helper.CanonicalLicenses(pkg.PackageLicenseDeclared)...)
+		}
+		renamed := addExtractedLicensingInfos(document, otherLicenses)
+		pkg.PackageLicenseConcluded = helper.RenameLicenseRefs(pkg.PackageLicenseConcluded, renamed)
+		pkg.PackageLicenseDeclared = helper.RenameLicenseRefs(pkg.PackageLicenseDeclared, renamed)
+		for _, comment := range module.Annotations {
+			pkg.Annotations = append(pkg.Annotations, f.buildAnnotation(document, pkg.SPDXID, comment))
+		}
+		if f.Config.AnalyzeFiles && module.Root {
+			if err := f.addPackageFiles(document, &pkg, module); err != nil {
+				return fmt.Errorf("failed to analyze the files of %s: %w", module.Name, err)
+			}
 		}
 		document.Packages = append(document.Packages, pkg)
 	}
 	return nil
 }
 
+// addExtractedLicensingInfos adds the licenses the document holds the text of, once whatever
+// the number of modules concluding them. A LicenseRef- license whose text the document
+// already holds is renamed after it, the identifiers renamed being returned
+func addExtractedLicensingInfos(document *models.Document, licenses []*models.License) map[string]string {
+	renamed := map[string]string{}
+	for _, license := range licenses {
+		found := false
+		for _, other := range document.ExtractedLicensingInfos {
+			if other.LicenseID == license.ID {
+				found = true
+				break
+			}
+			if strings.HasPrefix(license.ID, "LicenseRef-") && license.ExtractedText != "" && helper.SameLicenseText(other.ExtractedText, license.ExtractedText) {
+				renamed[license.ID] = other.LicenseID
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+		document.ExtractedLicensingInfos = append(document.ExtractedLicensingInfos, models.ExtractedLicensingInfo{
+			LicenseID:      license.ID,
+			ExtractedText:  license.ExtractedText,
+			LicenseName:    license.Name,
+			LicenseComment: license.Comments,
+		})
+	}
+	return renamed
+}
+
+// buildAnnotation annotates the element spdxRef of the document with comment on behalf of the tool
+func (f *Format) buildAnnotation(document *models.Document, spdxRef, comment string) models.Annotation {
+	return models.Annotation{
+		Annotator:      fmt.Sprintf("Tool: spdx-sbom-generator-%s", f.Config.ToolVersion),
+		AnnotationDate: document.CreationInfo.Created,
+		AnnotationType: "OTHER",
+		SPDXREF:        spdxRef,
+		Comment:        comment,
+	}
+}
+
 // WIP
 func (f *Format) convertToPackage(module models.Module) (models.Package, error) {
+	concluded, concludedNotes := buildLicense(module.LicenseConcluded)
+	declared, declaredNotes := buildLicense(module.LicenseDeclared)
+	comments := helper.AppendLicenseNotes(module.CommentsLicense, append(concludedNotes, declaredNotes...)...)
+
 	return models.Package{
 		PackageName:             module.Name,
 		SPDXID:                  setPkgSPDXID(module.Name, module.Version, module.Root),
@@ -155,20 +316,59 @@ func (f *Format) convertToPackage(module models.Module) (models.Package, error)
 		PackageSupplier:         setPkgValue(module.Supplier.Get()),
 		PackageDownloadLocation: setPkgValue(module.PackageDownloadLocation),
 		FilesAnalyzed:           false,
-		PackageChecksums: []models.PackageChecksum{{
-			Algorithm: module.CheckSum.Algorithm,
-			Value:     module.CheckSum.String(),
-		}},
+		PackageChecksums:        buildChecksums(module),
 		PackageHomePage:         buildHomepageURL(module.PackageURL),
-		PackageLicenseConcluded: noAssertion, // setPkgValue(module.LicenseConcluded),
-		PackageLicenseDeclared:  noAssertion, // setPkgValue(module.LicenseDeclared),
-		PackageCopyrightText:    noAssertion, // setPkgValue(module.Copyright),
-		PackageLicenseComments:  setPkgValue(""),
+		PackageLicenseConcluded: concluded,
+		PackageLicenseDeclared:  declared,
+		PackageCopyrightText:    noAssertion,
+		PackageLicenseComments:  setPkgValue(comments),
 		PackageComment:          setPkgValue(""),
 		RootPackage:             module.Root,
 	}, nil
 }
 
+// buildChecksums returns the checksum of the module, none if the plugin gave it none
+func buildChecksums(module models.Module) []models.PackageChecksum {
+	if module.CheckSum == nil {
+		return []models.PackageChecksum{}
+	}
+	return []models.PackageChecksum{{
+		Algorithm: module.CheckSum.Algorithm,
+		Value:     module.CheckSum.String(),
+	}}
+}
+
+// buildCopyrightText aggregates the copyright the plugin read for the module with the
+// statements found in the module sources, NOASSERTION if none
+func buildCopyrightText(module models.Module) string {
+	statements, err := copyright.Collect(module.Copyright, module.LocalPath)
+	if err != nil {
+		log.Debugf("failed to scan the copyrights of %s: %v", module.Name, err)
+	}
+	if len(statements) == 0 {
+		return noAssertion
+	}
+
+	return copyright.Text(statements)
+}
+
+// buildLicense returns the license expression the plugin found for the module, with
+// the case of its identifiers corrected, and notes on its deprecated identifiers.
+// It returns NOASSERTION if none or not a valid SPDX license expression
+func buildLicense(license string) (string, []string) {
+	if license == noAssertion || license == "NONE" {
+		return license, nil
+	}
+	if normalized, notes, ok := helper.NormalizeLicenseExpression(license); ok {
+		return normalized, notes
+	}
+	if license != "" {
+		log.Debugf("ignoring the license %q, not an SPDX license expression", license)
+	}
+
+	return noAssertion, nil
+}
+
 // todo: complete build package homepage rules
 func buildHomepageURL(url string) string {
 	if url == "" {
@@ -232,13 +432,20 @@ func sortModules(modules []models.Module) []models.Module {
 	return modules
 }
 
-func buildNamespace(name, version string) string {
+// buildNamespace returns a unique namespace for the document of the module under base,
+// http://spdx.org/spdxpackages unless set
+func buildNamespace(base, name, version string) string {
+	if base == "" {
+		base = fmt.Sprintf("%s://spdx.org/spdxpackages", httpPrefix)
+	}
+	base = strings.TrimSuffix(base, "/")
+
 	uuid := uuid.New().String()
 	if version == "" {
-		return fmt.Sprintf("http://spdx.org/spdxpackages/%s-%s", name, uuid)
+		return fmt.Sprintf("%s/%s-%s", base, name, uuid)
 	}
 
-	return fmt.Sprintf("http://spdx.org/spdxpackages/%s-%s-%s", name, version, uuid)
+	return fmt.Sprintf("%s/%s-%s-%s", base, name, version, uuid)
 }
 
 func buildName(name, version string) string {
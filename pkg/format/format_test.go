@@ -0,0 +1,48 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestBuildBaseDocument(t *testing.T) {
+	f := Format{Config: Config{
+		ToolVersion:     "v1.0.0",
+		Creators:        []string{"organization: Acme Inc. (sbom@acme.com)", "Person: Jane Doe"},
+		Namespace:       "https://sbom.acme.com/spdxdocs/",
+		DocumentComment: "Built by the release pipeline",
+	}}
+	document, err := f.buildBaseDocument(models.Module{Name: "app", Version: "1.2.0"})
+	require.NoError(t, err)
+	assert.Equal(t, "app-1.2.0", document.DocumentName)
+	assert.True(t, strings.HasPrefix(document.DocumentNamespace, "https://sbom.acme.com/spdxdocs/app-1.2.0-"), document.DocumentNamespace)
+	assert.Equal(t, []string{
+		"Tool: spdx-sbom-generator-v1.0.0",
+		"Organization: Acme Inc. (sbom@acme.com)",
+		"Person: Jane Doe",
+	}, document.CreationInfo.Creators)
+
+	content, err := TagValueSPDXRenderer{}.RenderDocument(*document)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `Creator: Tool: spdx-sbom-generator-v1.0.0
+Creator: Organization: Acme Inc. (sbom@acme.com)
+Creator: Person: Jane Doe
+Created: `)
+	assert.Contains(t, string(content), "DocumentComment: <text>Built by the release pipeline</text>\n")
+
+	f.Config.DocumentName = "acme-app"
+	document, err = f.buildBaseDocument(models.Module{Name: "app", Version: "1.2.0"})
+	require.NoError(t, err)
+	assert.Equal(t, "acme-app", document.DocumentName)
+
+	f.Config.Creators = []string{"Acme Inc."}
+	_, err = f.buildBaseDocument(models.Module{Name: "app"})
+	assert.Error(t, err)
+}
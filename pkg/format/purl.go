@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"net/url"
+	"strings"
+)
+
+// the package-url types of the ecosystems, named after the slugs of their plugins
+var purlTypes = map[string]string{
+	"cargo":       "cargo",
+	"composer":    "composer",
+	"bundler":     "gem",
+	"go-mod":      "golang",
+	"Java-Gradle": "maven",
+	"Java-Maven":  "maven",
+	"npm":         "npm",
+	"yarn":        "npm",
+	"nuget":       "nuget",
+	"pip":         "pypi",
+	"pipenv":      "pypi",
+	"poetry":      "pypi",
+	"pyenv":       "pypi",
+	"swift":       "swift",
+}
+
+// PackageURL returns the package-url (https://github.com/package-url/purl-spec) of the
+// package of the ecosystem, empty if the ecosystem has no package-url type
+func PackageURL(ecosystem, name, version string) string {
+	purlType, ok := purlTypes[ecosystem]
+	if !ok || name == "" {
+		return ""
+	}
+
+	namespace := ""
+	switch purlType {
+	case "maven":
+		// Maven packages are named groupId:artifactId
+		if i := strings.LastIndex(name, ":"); i >= 0 {
+			namespace, name = name[:i], name[i+1:]
+		}
+	case "pypi":
+		name = strings.ReplaceAll(strings.ToLower(name), "_", "-")
+	case "golang", "swift", "npm", "composer":
+		// the namespace of a Go module or Swift package is the path of its repository, that of
+		// an npm package its scope and that of a Composer package its vendor
+		if i := strings.LastIndex(name, "/"); i >= 0 {
+			namespace, name = name[:i], name[i+1:]
+		}
+	}
+
+	purl := "pkg:" + purlType + "/"
+	if namespace != "" {
+		segments := strings.Split(namespace, "/")
+		for i, segment := range segments {
+			segments[i] = purlEscape(segment)
+		}
+		purl += strings.Join(segments, "/") + "/"
+	}
+	purl += purlEscape(name)
+	if version != "" {
+		purl += "@" + purlEscape(version)
+	}
+	return purl
+}
+
+// purlEscape percent-encodes a segment of a package-url, including the @ url.PathEscape leaves
+func purlEscape(segment string) string {
+	return strings.ReplaceAll(url.PathEscape(segment), "@", "%40")
+}
@@ -0,0 +1,539 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+const (
+	rdfNamespace      = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	rdfsNamespace     = "http://www.w3.org/2000/01/rdf-schema#"
+	spdxNamespace     = "http://spdx.org/rdf/terms#"
+	doapNamespace     = "http://usefulinc.com/ns/doap#"
+	spdxLicensePrefix = "http://spdx.org/licenses/"
+)
+
+// RDFSPDXRenderer implements an SPDXRenderer that outputs RDF/XML formatted SPDX documents,
+// following the SPDX ontology (https://spdx.org/rdf/terms)
+type RDFSPDXRenderer struct{}
+
+// RenderDocument writes the document as RDF/XML. Every package and file is described where
+// it is first related to, as the readers of SPDX documents expect, and pointed to afterwards
+func (r RDFSPDXRenderer) RenderDocument(document models.Document) ([]byte, error) {
+	w := &rdfWriter{
+		namespace:     document.DocumentNamespace,
+		external:      map[string]string{},
+		packages:      map[string]models.Package{},
+		files:         map[string]models.File{},
+		relationships: map[string][]models.Relationship{},
+		annotations:   map[string][]models.Annotation{},
+		written:       map[string]bool{document.SPDXID: true},
+	}
+	for _, ref := range document.ExternalDocumentRefs {
+		w.external[ref.ExternalDocumentID] = ref.SPDXDocument
+	}
+	for _, pkg := range document.Packages {
+		w.packages[pkg.SPDXID] = pkg
+	}
+	for _, file := range document.Files {
+		w.files[file.SPDXID] = file
+	}
+	for _, relationship := range document.Relationships {
+		w.relationships[relationship.SPDXElementID] = append(w.relationships[relationship.SPDXElementID], relationship)
+	}
+	for _, annotation := range document.Annotations {
+		id := annotation.SPDXREF
+		if id == "" {
+			id = document.SPDXID
+		}
+		w.annotations[id] = append(w.annotations[id], annotation)
+	}
+
+	w.buf.WriteString(xml.Header)
+	w.start("rdf:RDF",
+		"xmlns:rdf", rdfNamespace,
+		"xmlns:rdfs", rdfsNamespace,
+		"xmlns:spdx", spdxNamespace,
+		"xmlns:doap", doapNamespace)
+
+	w.start("spdx:SpdxDocument", "rdf:about", w.element(document.SPDXID))
+	w.text("spdx:specVersion", document.SPDXVersion)
+	w.resource("spdx:dataLicense", spdxLicensePrefix+document.DataLicense)
+	w.text("spdx:name", document.DocumentName)
+	w.text("rdfs:comment", document.DocumentComment)
+	w.start("spdx:creationInfo")
+	w.start("spdx:CreationInfo")
+	w.text("spdx:created", document.CreationInfo.Created)
+	for _, creator := range document.CreationInfo.Creators {
+		w.text("spdx:creator", creator)
+	}
+	w.text("spdx:licenseListVersion", document.CreationInfo.LicenceListVersion)
+	w.text("rdfs:comment", document.CreationInfo.Comment)
+	w.end("spdx:CreationInfo")
+	w.end("spdx:creationInfo")
+	for _, ref := range document.ExternalDocumentRefs {
+		w.start("spdx:externalDocumentRef")
+		w.start("spdx:ExternalDocumentRef")
+		w.text("spdx:externalDocumentId", ref.ExternalDocumentID)
+		w.resource("spdx:spdxDocument", ref.SPDXDocument)
+		w.checksums([]models.PackageChecksum{ref.Checksum})
+		w.end("spdx:ExternalDocumentRef")
+		w.end("spdx:externalDocumentRef")
+	}
+	for _, license := range document.ExtractedLicensingInfos {
+		w.start("spdx:hasExtractedLicensingInfo")
+		w.start("spdx:ExtractedLicensingInfo", "rdf:about", w.element(license.LicenseID))
+		w.text("spdx:licenseId", license.LicenseID)
+		w.text("spdx:extractedText", license.ExtractedText)
+		w.text("spdx:name", license.LicenseName)
+		w.text("rdfs:comment", license.LicenseComment)
+		w.end("spdx:ExtractedLicensingInfo")
+		w.end("spdx:hasExtractedLicensingInfo")
+	}
+	w.elementRelationships(document.SPDXID)
+	w.elementAnnotations(document.SPDXID, nil)
+	w.end("spdx:SpdxDocument")
+
+	// the elements no relationship leads to, and the elements of other documents
+	// relationships are from, are described on their own
+	for _, pkg := range document.Packages {
+		w.pkg(pkg.SPDXID)
+	}
+	for _, file := range document.Files {
+		w.file(file.SPDXID)
+	}
+	for _, relationship := range document.Relationships {
+		if w.written[relationship.SPDXElementID] {
+			continue
+		}
+		w.written[relationship.SPDXElementID] = true
+		w.start("spdx:SpdxElement", "rdf:about", w.element(relationship.SPDXElementID))
+		w.elementRelationships(relationship.SPDXElementID)
+		w.end("spdx:SpdxElement")
+	}
+
+	w.end("rdf:RDF")
+	if w.err != nil {
+		return nil, w.err
+	}
+	return w.buf.Bytes(), nil
+}
+
+// rdfWriter writes indented RDF/XML, the identifiers of the elements being resolved in namespace,
+// or in the namespace of their external document
+type rdfWriter struct {
+	buf           bytes.Buffer
+	namespace     string
+	depth         int
+	err           error
+	packages      map[string]models.Package
+	files         map[string]models.File
+	relationships map[string][]models.Relationship
+	annotations   map[string][]models.Annotation
+	// external maps the DocumentRef- identifiers of the external documents to their namespace
+	external map[string]string
+	// written are the elements already described
+	written map[string]bool
+}
+
+// property writes the property pointing to the element id, described there unless already written
+func (w *rdfWriter) property(tag, id string) {
+	_, isPackage := w.packages[id]
+	_, isFile := w.files[id]
+	if w.written[id] || (!isPackage && !isFile) {
+		w.resource(tag, w.element(id))
+		return
+	}
+	w.start(tag)
+	w.pkg(id)
+	w.file(id)
+	w.end(tag)
+}
+
+// pkg describes the package id unless already written
+func (w *rdfWriter) pkg(id string) {
+	pkg, ok := w.packages[id]
+	if !ok || w.written[id] {
+		return
+	}
+	w.written[id] = true
+
+	w.start("spdx:Package", "rdf:about", w.element(pkg.SPDXID))
+	w.text("spdx:name", pkg.PackageName)
+	w.text("spdx:versionInfo", pkg.PackageVersion)
+	w.text("spdx:supplier", pkg.PackageSupplier)
+	w.text("spdx:originator", pkg.PackageOriginator)
+	if pkg.PackageDownloadLocation == noAssertion || pkg.PackageDownloadLocation == "NONE" {
+		w.resource("spdx:downloadLocation", w.element(pkg.PackageDownloadLocation))
+	} else {
+		w.text("spdx:downloadLocation", pkg.PackageDownloadLocation)
+	}
+	w.text("spdx:filesAnalyzed", fmt.Sprint(pkg.FilesAnalyzed))
+	if code := pkg.PackageVerificationCode; code != nil {
+		w.start("spdx:packageVerificationCode")
+		w.start("spdx:PackageVerificationCode")
+		w.text("spdx:packageVerificationCodeValue", code.Value)
+		for _, file := range code.ExcludedFiles {
+			w.text("spdx:packageVerificationCodeExcludedFile", file)
+		}
+		w.end("spdx:PackageVerificationCode")
+		w.end("spdx:packageVerificationCode")
+	}
+	w.checksums(pkg.PackageChecksums)
+	// the home page is a URI, left out when unknown
+	if pkg.PackageHomePage != noAssertion && pkg.PackageHomePage != "NONE" {
+		w.text("doap:homepage", pkg.PackageHomePage)
+	}
+	w.license("spdx:licenseConcluded", pkg.PackageLicenseConcluded)
+	w.license("spdx:licenseDeclared", pkg.PackageLicenseDeclared)
+	for _, license := range pkg.LicenseInfoFromFiles {
+		w.license("spdx:licenseInfoFromFiles", license)
+	}
+	w.text("spdx:copyrightText", pkg.PackageCopyrightText)
+	w.text("spdx:licenseComments", pkg.PackageLicenseComments)
+	w.text("rdfs:comment", pkg.PackageComment)
+	for _, file := range pkg.HasFiles {
+		w.property("spdx:hasFile", file)
+	}
+	w.elementRelationships(pkg.SPDXID)
+	w.elementAnnotations(pkg.SPDXID, pkg.Annotations)
+	w.end("spdx:Package")
+}
+
+// file describes the file id unless already written
+func (w *rdfWriter) file(id string) {
+	file, ok := w.files[id]
+	if !ok || w.written[id] {
+		return
+	}
+	w.written[id] = true
+
+	w.start("spdx:File", "rdf:about", w.element(file.SPDXID))
+	w.text("spdx:fileName", file.FileName)
+	for _, fileType := range file.FileTypes {
+		w.resource("spdx:fileType", spdxNamespace+"fileType_"+strings.ToLower(fileType))
+	}
+	w.checksums(file.Checksums)
+	w.license("spdx:licenseConcluded", file.LicenseConcluded)
+	for _, license := range file.LicenseInfoInFiles {
+		w.license("spdx:licenseInfoInFile", license)
+	}
+	w.text("spdx:copyrightText", file.CopyrightText)
+	w.elementRelationships(file.SPDXID)
+	w.end("spdx:File")
+}
+
+// element returns the URI of the element of the document, NONE and NOASSERTION being
+// the individuals of the SPDX ontology
+func (w *rdfWriter) element(id string) string {
+	switch id {
+	case "NONE":
+		return spdxNamespace + "none"
+	case noAssertion:
+		return spdxNamespace + "noassertion"
+	}
+	// the elements of the external documents are the ones of their namespace
+	if ref, element, found := strings.Cut(id, ":"); found && strings.HasPrefix(ref, "DocumentRef-") {
+		if namespace, ok := w.external[ref]; ok {
+			return namespace + "#" + element
+		}
+	}
+	return w.namespace + "#" + id
+}
+
+func (w *rdfWriter) indent() {
+	w.buf.WriteString(strings.Repeat("  ", w.depth))
+}
+
+func (w *rdfWriter) attributes(attrs []string) {
+	for i := 0; i+1 < len(attrs); i += 2 {
+		w.buf.WriteString(" " + attrs[i] + `="`)
+		w.escape(attrs[i+1])
+		w.buf.WriteString(`"`)
+	}
+}
+
+func (w *rdfWriter) escape(s string) {
+	if err := xml.EscapeText(&w.buf, []byte(s)); err != nil && w.err == nil {
+		w.err = err
+	}
+}
+
+// start opens the tag with the attributes, given as name and value pairs
+func (w *rdfWriter) start(tag string, attrs ...string) {
+	w.indent()
+	w.buf.WriteString("<" + tag)
+	w.attributes(attrs)
+	w.buf.WriteString(">\n")
+	w.depth++
+}
+
+func (w *rdfWriter) end(tag string) {
+	w.depth--
+	w.indent()
+	w.buf.WriteString("</" + tag + ">\n")
+}
+
+// text writes the property with a literal value, left out when empty
+func (w *rdfWriter) text(tag, value string) {
+	if value == "" {
+		return
+	}
+	w.indent()
+	w.buf.WriteString("<" + tag + ">")
+	w.escape(value)
+	w.buf.WriteString("</" + tag + ">\n")
+}
+
+// resource writes the property pointing to the resource uri
+func (w *rdfWriter) resource(tag, uri string) {
+	w.indent()
+	w.buf.WriteString("<" + tag)
+	w.attributes([]string{"rdf:resource", uri})
+	w.buf.WriteString("/>\n")
+}
+
+func (w *rdfWriter) checksums(checksums []models.PackageChecksum) {
+	for _, checksum := range checksums {
+		algorithm := strings.ToLower(string(checksum.Algorithm))
+		if strings.HasPrefix(algorithm, "blake2b") {
+			algorithm = strings.ReplaceAll(algorithm, "-", "")
+		}
+		w.start("spdx:checksum")
+		w.start("spdx:Checksum")
+		w.resource("spdx:algorithm", spdxNamespace+"checksumAlgorithm_"+strings.ReplaceAll(algorithm, "-", "_"))
+		w.text("spdx:checksumValue", checksum.Value)
+		w.end("spdx:Checksum")
+		w.end("spdx:checksum")
+	}
+}
+
+// elementRelationships writes the relationships from the element id
+func (w *rdfWriter) elementRelationships(id string) {
+	for _, relationship := range w.relationships[id] {
+		w.start("spdx:relationship")
+		w.start("spdx:Relationship")
+		w.resource("spdx:relationshipType", spdxNamespace+"relationshipType_"+lowerCamelCase(relationship.RelationshipType))
+		w.property("spdx:relatedSpdxElement", relationship.RelatedSPDXElement)
+		w.end("spdx:Relationship")
+		w.end("spdx:relationship")
+	}
+}
+
+// elementAnnotations writes the annotations of the element id, those of the document pointing
+// to it and those of the element
+func (w *rdfWriter) elementAnnotations(id string, annotations []models.Annotation) {
+	for _, annotation := range append(append([]models.Annotation{}, w.annotations[id]...), annotations...) {
+		w.start("spdx:annotation")
+		w.start("spdx:Annotation")
+		w.text("spdx:annotator", annotation.Annotator)
+		w.text("spdx:annotationDate", annotation.AnnotationDate)
+		w.resource("spdx:annotationType", spdxNamespace+"annotationType_"+strings.ToLower(annotation.AnnotationType))
+		w.text("rdfs:comment", annotation.Comment)
+		w.end("spdx:Annotation")
+		w.end("spdx:annotation")
+	}
+}
+
+// license writes the property with the license expression, its operators being the
+// license sets and operators of the SPDX ontology
+func (w *rdfWriter) license(tag, expression string) {
+	if expression == "" {
+		return
+	}
+	tokens := strings.Fields(strings.NewReplacer("(", " ( ", ")", " ) ").Replace(expression))
+	parser := &licenseParser{tokens: tokens}
+	node := parser.or()
+	if parser.pos < len(tokens) || node == nil {
+		// not an expression the ontology can describe, kept as a literal
+		w.text(tag, expression)
+		return
+	}
+	w.licenseNode(tag, node)
+}
+
+func (w *rdfWriter) licenseNode(tag string, node *licenseNode) {
+	switch node.operator {
+	case "":
+		w.resource(tag, w.licenseURI(node.id))
+		return
+	case "+":
+		w.start(tag)
+		w.start("spdx:OrLaterOperator")
+		w.simpleLicense(node.members[0].id)
+		w.end("spdx:OrLaterOperator")
+		w.end(tag)
+		return
+	case "WITH":
+		w.start(tag)
+		w.start("spdx:WithExceptionOperator")
+		w.licenseMember(node.members[0])
+		w.start("spdx:licenseException")
+		w.start("spdx:LicenseException", "rdf:about", spdxLicensePrefix+node.id)
+		w.text("spdx:licenseExceptionId", node.id)
+		w.end("spdx:LicenseException")
+		w.end("spdx:licenseException")
+		w.end("spdx:WithExceptionOperator")
+		w.end(tag)
+		return
+	}
+
+	set := "spdx:ConjunctiveLicenseSet"
+	if node.operator == "OR" {
+		set = "spdx:DisjunctiveLicenseSet"
+	}
+	w.start(tag)
+	w.start(set)
+	for _, member := range node.members {
+		w.licenseNode("spdx:member", member)
+	}
+	w.end(set)
+	w.end(tag)
+}
+
+// licenseMember writes the license of the exception operator, which may be an or later one
+func (w *rdfWriter) licenseMember(node *licenseNode) {
+	if node.operator != "+" {
+		w.simpleLicense(node.id)
+		return
+	}
+	w.start("spdx:member")
+	w.start("spdx:OrLaterOperator")
+	w.simpleLicense(node.members[0].id)
+	w.end("spdx:OrLaterOperator")
+	w.end("spdx:member")
+}
+
+// simpleLicense writes the member of an operator, described by its identifier
+func (w *rdfWriter) simpleLicense(id string) {
+	class := "spdx:ListedLicense"
+	if strings.HasPrefix(id, "LicenseRef-") || strings.HasPrefix(id, "DocumentRef-") {
+		class = "spdx:ExtractedLicensingInfo"
+	}
+	w.start("spdx:member")
+	w.start(class, "rdf:about", w.licenseURI(id))
+	w.text("spdx:licenseId", id)
+	w.end(class)
+	w.end("spdx:member")
+}
+
+// licenseURI returns the URI of the license, the licenses of the document and of
+// other documents being resolved in the namespace of the document
+func (w *rdfWriter) licenseURI(id string) string {
+	if id == "NONE" || id == noAssertion || strings.HasPrefix(id, "LicenseRef-") || strings.HasPrefix(id, "DocumentRef-") {
+		return w.element(id)
+	}
+	return spdxLicensePrefix + id
+}
+
+// licenseNode is a license of a license expression, or an operator of its members
+type licenseNode struct {
+	// operator is AND, OR, WITH, + or empty for a license
+	operator string
+	// id is the license, or the exception of the WITH operator
+	id      string
+	members []*licenseNode
+}
+
+// licenseParser parses a license expression, AND taking precedence over OR
+type licenseParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *licenseParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *licenseParser) or() *licenseNode {
+	return p.set("OR", p.and)
+}
+
+func (p *licenseParser) and() *licenseNode {
+	return p.set("AND", p.with)
+}
+
+// set parses the members joined by the operator, a single member being returned as is
+func (p *licenseParser) set(operator string, member func() *licenseNode) *licenseNode {
+	first := member()
+	if first == nil {
+		return nil
+	}
+	node := &licenseNode{operator: operator, members: []*licenseNode{first}}
+	for strings.EqualFold(p.peek(), operator) {
+		p.pos++
+		next := member()
+		if next == nil {
+			return nil
+		}
+		node.members = append(node.members, next)
+	}
+	if len(node.members) == 1 {
+		return first
+	}
+	return node
+}
+
+func (p *licenseParser) with() *licenseNode {
+	license := p.license()
+	if license == nil || !strings.EqualFold(p.peek(), "WITH") {
+		return license
+	}
+	p.pos++
+	exception := p.peek()
+	if exception == "" || exception == "(" || exception == ")" {
+		return nil
+	}
+	p.pos++
+	return &licenseNode{operator: "WITH", id: exception, members: []*licenseNode{license}}
+}
+
+func (p *licenseParser) license() *licenseNode {
+	token := p.peek()
+	switch {
+	case token == "(":
+		p.pos++
+		node := p.or()
+		if node == nil || p.peek() != ")" {
+			return nil
+		}
+		p.pos++
+		return node
+	case token == "" || token == ")" || strings.EqualFold(token, "AND") || strings.EqualFold(token, "OR") || strings.EqualFold(token, "WITH"):
+		return nil
+	}
+	p.pos++
+	if strings.HasSuffix(token, "+") && len(token) > 1 {
+		return &licenseNode{operator: "+", members: []*licenseNode{{id: strings.TrimSuffix(token, "+")}}}
+	}
+	return &licenseNode{id: token}
+}
+
+// lowerCamelCase converts the SPDX relationship type, as DEPENDS_ON, to its name in the ontology, dependsOn
+func lowerCamelCase(s string) string {
+	var b strings.Builder
+	upper := false
+	for i, r := range strings.ToLower(s) {
+		switch {
+		case r == '_':
+			upper = true
+		case upper && i > 0:
+			b.WriteRune(unicode.ToUpper(r))
+			upper = false
+		default:
+			b.WriteRune(r)
+			upper = false
+		}
+	}
+	return b.String()
+}
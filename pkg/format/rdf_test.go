@@ -0,0 +1,141 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestRDFSPDXRenderer(t *testing.T) {
+	document := reportDocument
+	document.SPDXVersion = "SPDX-2.2"
+	document.DataLicense = "CC0-1.0"
+	document.DocumentNamespace = "http://example.com/app"
+	document.Packages = append([]models.Package{}, reportDocument.Packages...)
+	document.Packages[0].PackageLicenseConcluded = "(MIT OR GPL-2.0+) AND Apache-2.0 WITH LLVM-exception"
+	document.Packages[0].PackageLicenseDeclared = "LicenseRef-app"
+	document.ExtractedLicensingInfos = []models.ExtractedLicensingInfo{{LicenseID: "LicenseRef-app", ExtractedText: "<b>App</b> license"}}
+
+	content, err := RDFSPDXRenderer{}.RenderDocument(document)
+	require.NoError(t, err)
+	rdf := string(content)
+
+	// the document is well-formed XML
+	decoder := xml.NewDecoder(strings.NewReader(rdf))
+	for {
+		_, err := decoder.Token()
+		if err != nil {
+			assert.Equal(t, io.EOF, err)
+			break
+		}
+	}
+
+	assert.Contains(t, rdf, `<spdx:SpdxDocument rdf:about="http://example.com/app#SPDXRef-DOCUMENT">`)
+	assert.Contains(t, rdf, `<spdx:extractedText>&lt;b&gt;App&lt;/b&gt; license</spdx:extractedText>`)
+	assert.Contains(t, rdf, `<spdx:relationshipType rdf:resource="http://spdx.org/rdf/terms#relationshipType_describes"/>`)
+	assert.Contains(t, rdf, `<spdx:relationshipType rdf:resource="http://spdx.org/rdf/terms#relationshipType_devDependencyOf"/>`)
+	assert.Contains(t, rdf, `<spdx:downloadLocation rdf:resource="http://spdx.org/rdf/terms#noassertion"/>`)
+	assert.Contains(t, rdf, `<spdx:licenseDeclared rdf:resource="http://example.com/app#LicenseRef-app"/>`)
+
+	// the packages are described once, where they are first related to
+	assert.Equal(t, 1, strings.Count(rdf, `<spdx:Package rdf:about="http://example.com/app#SPDXRef-Package-a-1.0">`))
+	assert.Less(t, strings.Index(rdf, `<spdx:Package rdf:about="http://example.com/app#SPDXRef-Package-app">`), strings.Index(rdf, "</spdx:SpdxDocument>"))
+	assert.Contains(t, rdf, `<spdx:relatedSpdxElement rdf:resource="http://example.com/app#SPDXRef-Package-b-2.0"/>`)
+	// the package depending on nothing the document relates to is described on its own
+	assert.Greater(t, strings.Index(rdf, `<spdx:Package rdf:about="http://example.com/app#SPDXRef-Package-test-1.0">`), strings.Index(rdf, "</spdx:SpdxDocument>"))
+
+	concluded := rdf[strings.Index(rdf, "<spdx:licenseConcluded>"):strings.Index(rdf, "</spdx:licenseConcluded>")]
+	assert.Equal(t, []string{
+		"spdx:ConjunctiveLicenseSet",
+		"spdx:DisjunctiveLicenseSet",
+		`rdf:resource="http://spdx.org/licenses/MIT"`,
+		"spdx:OrLaterOperator",
+		`spdx:ListedLicense rdf:about="http://spdx.org/licenses/GPL-2.0"`,
+		"spdx:WithExceptionOperator",
+		`spdx:ListedLicense rdf:about="http://spdx.org/licenses/Apache-2.0"`,
+		`spdx:LicenseException rdf:about="http://spdx.org/licenses/LLVM-exception"`,
+	}, licenseOutline(concluded))
+}
+
+func TestRDFSPDXRendererInvalidExpression(t *testing.T) {
+	document := models.Document{
+		SPDXID:   "SPDXRef-DOCUMENT",
+		Packages: []models.Package{{SPDXID: "SPDXRef-Package-a", PackageName: "a", PackageLicenseConcluded: "MIT AND (Apache-2.0"}},
+	}
+
+	content, err := RDFSPDXRenderer{}.RenderDocument(document)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "<spdx:licenseConcluded>MIT AND (Apache-2.0</spdx:licenseConcluded>")
+}
+
+func TestRDFSPDXRendererOrLaterWithException(t *testing.T) {
+	document := models.Document{
+		SPDXID: "SPDXRef-DOCUMENT",
+		Packages: []models.Package{{
+			SPDXID: "SPDXRef-Package-a", PackageName: "a", PackageLicenseConcluded: "GPL-2.0+ WITH Classpath-exception-2.0",
+		}},
+	}
+
+	content, err := RDFSPDXRenderer{}.RenderDocument(document)
+	require.NoError(t, err)
+	rdf := string(content)
+	assert.NotContains(t, rdf, `rdf:about="http://spdx.org/licenses/"`)
+
+	// the license of the exception is the or later operator
+	concluded := rdf[strings.Index(rdf, "<spdx:licenseConcluded>"):strings.Index(rdf, "</spdx:licenseConcluded>")]
+	assert.Equal(t, []string{
+		"spdx:WithExceptionOperator",
+		"spdx:OrLaterOperator",
+		`spdx:ListedLicense rdf:about="http://spdx.org/licenses/GPL-2.0"`,
+		`spdx:LicenseException rdf:about="http://spdx.org/licenses/Classpath-exception-2.0"`,
+	}, licenseOutline(concluded))
+}
+
+func TestRDFSPDXRendererExternalDocuments(t *testing.T) {
+	document := models.Document{
+		SPDXID:            "SPDXRef-DOCUMENT",
+		DocumentNamespace: "http://example.com/app",
+		ExternalDocumentRefs: []models.ExternalDocumentRef{{
+			ExternalDocumentID: "DocumentRef-core",
+			SPDXDocument:       "https://sbom.acme.org/core-1.2.0",
+			Checksum:           models.PackageChecksum{Algorithm: "SHA1", Value: "d6a770ba38583ed4bb4525bd96e50461655d2759"},
+		}},
+		Packages: []models.Package{{SPDXID: "SPDXRef-Package-app", PackageName: "app"}},
+		Relationships: []models.Relationship{
+			{SPDXElementID: "SPDXRef-DOCUMENT", RelatedSPDXElement: "SPDXRef-Package-app", RelationshipType: "DESCRIBES"},
+			{SPDXElementID: "SPDXRef-Package-app", RelatedSPDXElement: "DocumentRef-core:SPDXRef-Package-core", RelationshipType: "DEPENDS_ON"},
+		},
+	}
+
+	content, err := RDFSPDXRenderer{}.RenderDocument(document)
+	require.NoError(t, err)
+	rdf := string(content)
+	assert.Contains(t, rdf, `<spdx:externalDocumentId>DocumentRef-core</spdx:externalDocumentId>`)
+	assert.Contains(t, rdf, `<spdx:spdxDocument rdf:resource="https://sbom.acme.org/core-1.2.0"/>`)
+	assert.Contains(t, rdf, `<spdx:checksumValue>d6a770ba38583ed4bb4525bd96e50461655d2759</spdx:checksumValue>`)
+	// the packages of the external documents are the ones of their namespace
+	assert.Contains(t, rdf, `<spdx:relatedSpdxElement rdf:resource="https://sbom.acme.org/core-1.2.0#SPDXRef-Package-core"/>`)
+}
+
+// licenseOutline lists the license classes and the licenses pointed to, in the order of the RDF
+func licenseOutline(rdf string) []string {
+	outline := []string{}
+	for _, line := range strings.Split(rdf, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "<spdx:member rdf:resource"):
+			outline = append(outline, strings.TrimSuffix(strings.TrimPrefix(line, "<spdx:member "), "/>"))
+		case strings.HasPrefix(line, "<spdx:") && len(line) > 6 && line[6] >= 'A' && line[6] <= 'Z':
+			outline = append(outline, strings.Trim(line, "<>"))
+		}
+	}
+	return outline
+}
@@ -0,0 +1,263 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// ReportFormat is the format of the human-readable report of SPDX documents
+type ReportFormat int
+
+const (
+	ReportFormatHTML ReportFormat = iota
+	ReportFormatMarkdown
+)
+
+// ParseReportFormat returns the report format named html or markdown (md)
+func ParseReportFormat(name string) (ReportFormat, error) {
+	switch strings.ToLower(name) {
+	case "html":
+		return ReportFormatHTML, nil
+	case "markdown", "md":
+		return ReportFormatMarkdown, nil
+	default:
+		return ReportFormatHTML, fmt.Errorf("unknown report format %q, expected html or markdown", name)
+	}
+}
+
+// Filename returns the name of the report file of the format
+func (f ReportFormat) Filename() string {
+	if f == ReportFormatMarkdown {
+		return "bom-report.md"
+	}
+	return "bom-report.html"
+}
+
+// Report summarizes SPDX documents for the readers of neither tag-value nor JSON
+type Report struct {
+	Title      string
+	Created    string
+	Creators   []string
+	Ecosystems []ReportSummary
+	Licenses   []ReportSummary
+	Packages   []ReportPackage
+	// Tree is the dependency tree of the root packages, built from the relationships
+	Tree []*ReportNode
+}
+
+// ReportSummary counts the packages of an ecosystem or a license
+type ReportSummary struct {
+	Name     string
+	Packages int
+}
+
+// ReportPackage is a package of the report
+type ReportPackage struct {
+	SPDXID           string
+	Name             string
+	Version          string
+	Ecosystem        string
+	Supplier         string
+	License          string
+	DownloadLocation string
+	HomePage         string
+}
+
+// ReportNode is a package of the dependency tree with the packages it relates to
+type ReportNode struct {
+	Package      ReportPackage
+	Relationship string
+	Children     []*ReportNode
+	// Repeated is set on the packages already expanded higher in the tree, their children being left out
+	Repeated bool
+}
+
+// ReportSource is an SPDX document the report is built from
+type ReportSource struct {
+	Document models.Document
+	// Ecosystem is the ecosystem of the packages of the document, the package manager
+	// of the plugin, unless Ecosystems maps their SPDX identifier to theirs
+	Ecosystem  string
+	Ecosystems map[string]string
+}
+
+// BuildReport summarizes the documents by ecosystem and license and lists their
+// packages, each once, and the dependency tree of their root packages. The report
+// is titled after the first root package unless title is set
+func BuildReport(title string, sources []ReportSource) Report {
+	report := Report{Title: title}
+	ecosystems, licenses := map[string]int{}, map[string]int{}
+	seen := map[string]bool{}
+	for _, source := range sources {
+		document := source.Document
+		report.Created = document.CreationInfo.Created
+		report.Creators = mergeStrings(report.Creators, document.CreationInfo.Creators)
+
+		packages := map[string]ReportPackage{}
+		for _, pkg := range document.Packages {
+			reportPkg := buildReportPackage(pkg, source)
+			packages[pkg.SPDXID] = reportPkg
+			key := fmt.Sprintf("%s/%s@%s", reportPkg.Ecosystem, reportPkg.Name, reportPkg.Version)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			report.Packages = append(report.Packages, reportPkg)
+			ecosystems[reportPkg.Ecosystem]++
+			licenses[reportPkg.License]++
+		}
+		report.Tree = append(report.Tree, buildReportTree(document, packages)...)
+	}
+
+	sort.SliceStable(report.Packages, func(i, j int) bool {
+		a, b := report.Packages[i], report.Packages[j]
+		if a.Ecosystem != b.Ecosystem {
+			return a.Ecosystem < b.Ecosystem
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Version < b.Version
+	})
+	report.Ecosystems = buildReportSummaries(ecosystems)
+	report.Licenses = buildReportSummaries(licenses)
+	if report.Title == "" && len(report.Tree) > 0 {
+		report.Title = report.Tree[0].Package.Name
+	}
+	return report
+}
+
+// buildReportPackage converts a package of the document, its license being the concluded
+// license unless NOASSERTION
+func buildReportPackage(pkg models.Package, source ReportSource) ReportPackage {
+	ecosystem := source.Ecosystem
+	if e, ok := source.Ecosystems[pkg.SPDXID]; ok {
+		ecosystem = e
+	}
+	license := pkg.PackageLicenseConcluded
+	if license == "" || license == noAssertion {
+		license = pkg.PackageLicenseDeclared
+	}
+	if license == "" {
+		license = noAssertion
+	}
+	return ReportPackage{
+		SPDXID:           pkg.SPDXID,
+		Name:             pkg.PackageName,
+		Version:          pkg.PackageVersion,
+		Ecosystem:        ecosystem,
+		Supplier:         assertedValue(pkg.PackageSupplier),
+		License:          license,
+		DownloadLocation: assertedValue(pkg.PackageDownloadLocation),
+		HomePage:         assertedValue(pkg.PackageHomePage),
+	}
+}
+
+// buildReportTree builds the dependency tree of the packages the document describes
+func buildReportTree(document models.Document, packages map[string]ReportPackage) []*ReportNode {
+	roots, edges := dependencyEdges(document, func(id string) bool {
+		_, ok := packages[id]
+		return ok
+	})
+
+	// every package is expanded once, the first time the depth-first walk reaches it
+	expanded := map[string]bool{}
+	var walk func(id, relationship string) *ReportNode
+	walk = func(id, relationship string) *ReportNode {
+		node := &ReportNode{Package: packages[id], Relationship: relationship}
+		if expanded[id] {
+			node.Repeated = len(edges[id]) > 0
+			return node
+		}
+		expanded[id] = true
+		children := edges[id]
+		sort.SliceStable(children, func(i, j int) bool {
+			return packages[children[i].to].Name < packages[children[j].to].Name
+		})
+		for _, child := range children {
+			node.Children = append(node.Children, walk(child.to, child.relationship))
+		}
+		return node
+	}
+
+	tree := []*ReportNode{}
+	for _, root := range roots {
+		tree = append(tree, walk(root, "DESCRIBES"))
+	}
+	return tree
+}
+
+// dependencyEdge is a relationship of the dependency graph, from the package it is keyed by
+type dependencyEdge struct {
+	to, relationship string
+}
+
+// dependencyEdges returns the packages the document describes and the relationships between
+// its packages, reported by isPackage. The relationships named after the element they point
+// from, as DEV_DEPENDENCY_OF, are reversed, and the relationships with files are left out
+func dependencyEdges(document models.Document, isPackage func(id string) bool) ([]string, map[string][]dependencyEdge) {
+	edges := map[string][]dependencyEdge{}
+	roots := []string{}
+	for _, relationship := range document.Relationships {
+		from, to := relationship.SPDXElementID, relationship.RelatedSPDXElement
+		if relationship.RelationshipType == "DESCRIBES" && from == document.SPDXID {
+			if isPackage(to) {
+				roots = append(roots, to)
+			}
+			continue
+		}
+		if strings.HasSuffix(relationship.RelationshipType, "_OF") || strings.HasSuffix(relationship.RelationshipType, "_BY") {
+			from, to = to, from
+		}
+		if !isPackage(from) || !isPackage(to) {
+			continue
+		}
+		edges[from] = append(edges[from], dependencyEdge{to: to, relationship: relationship.RelationshipType})
+	}
+	return roots, edges
+}
+
+// buildReportSummaries sorts the counts by decreasing number of packages, then by name
+func buildReportSummaries(counts map[string]int) []ReportSummary {
+	summaries := make([]ReportSummary, 0, len(counts))
+	for name, count := range counts {
+		summaries = append(summaries, ReportSummary{Name: name, Packages: count})
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Packages != summaries[j].Packages {
+			return summaries[i].Packages > summaries[j].Packages
+		}
+		return summaries[i].Name < summaries[j].Name
+	})
+	return summaries
+}
+
+// assertedValue returns the value of a package field, empty for NOASSERTION
+func assertedValue(value string) string {
+	if value == noAssertion {
+		return ""
+	}
+	return value
+}
+
+// mergeStrings appends the values to merged, once each
+func mergeStrings(merged, values []string) []string {
+	for _, value := range values {
+		found := false
+		for _, m := range merged {
+			if m == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, value)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,169 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	htmlTemplate "html/template"
+	"io"
+	"strings"
+	"text/template"
+)
+
+const htmlReportTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>SBOM report of {{ .Title }}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2em auto; max-width: 80em; padding: 0 1em; color: #222; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { border: 1px solid #ccc; padding: .3em .6em; text-align: left; vertical-align: top; }
+th { background: #f4f4f4; }
+#packages { width: 100%; }
+#search { width: 100%; max-width: 30em; padding: .4em; font-size: 1em; }
+.tree ul { list-style: none; margin: 0; padding-left: 1.5em; border-left: 1px dotted #bbb; }
+.tree summary { cursor: pointer; }
+.relationship, .repeated { color: #777; font-size: .85em; }
+</style>
+</head>
+<body>
+<h1>SBOM report of {{ .Title }}</h1>
+<p>Created {{ .Created }} by {{ join .Creators ", " }}, listing {{ len .Packages }} packages.</p>
+
+<h2>Ecosystems</h2>
+<table>
+<thead><tr><th>Ecosystem</th><th>Packages</th></tr></thead>
+<tbody>
+{{- range .Ecosystems }}
+<tr><td>{{ .Name }}</td><td>{{ .Packages }}</td></tr>
+{{- end }}
+</tbody>
+</table>
+
+<h2>Licenses</h2>
+<table>
+<thead><tr><th>License</th><th>Packages</th></tr></thead>
+<tbody>
+{{- range .Licenses }}
+<tr><td>{{ .Name }}</td><td>{{ .Packages }}</td></tr>
+{{- end }}
+</tbody>
+</table>
+
+<h2>Packages</h2>
+<input id="search" type="search" placeholder="Search the packages" oninput="filterPackages(this.value)">
+<table id="packages">
+<thead><tr><th>Package</th><th>Version</th><th>Ecosystem</th><th>Supplier</th><th>License</th><th>Download location</th></tr></thead>
+<tbody>
+{{- range .Packages }}
+<tr><td>{{ if .HomePage }}<a href="{{ .HomePage }}">{{ .Name }}</a>{{ else }}{{ .Name }}{{ end }}</td><td>{{ .Version }}</td><td>{{ .Ecosystem }}</td><td>{{ .Supplier }}</td><td>{{ .License }}</td><td>{{ .DownloadLocation }}</td></tr>
+{{- end }}
+</tbody>
+</table>
+
+<h2>Dependency tree</h2>
+<div class="tree">
+{{- range .Tree }}
+{{ template "node" . }}
+{{- end }}
+</div>
+
+<script>
+function filterPackages(query) {
+  query = query.toLowerCase();
+  for (const row of document.querySelectorAll("#packages tbody tr")) {
+    row.hidden = !row.textContent.toLowerCase().includes(query);
+  }
+}
+</script>
+</body>
+</html>
+{{- define "label" }}{{ .Package.Name }}{{ with .Package.Version }} {{ . }}{{ end }}{{ if ne .Relationship "DESCRIBES" }} <span class="relationship">{{ .Relationship }}</span>{{ end }}{{ if .Repeated }} <span class="repeated">(dependencies listed above)</span>{{ end }}{{ end }}
+{{- define "node" }}{{ if .Children }}<details{{ if eq .Relationship "DESCRIBES" }} open{{ end }}><summary>{{ template "label" . }}</summary><ul>{{ range .Children }}<li>{{ template "node" . }}</li>{{ end }}</ul></details>{{ else }}{{ template "label" . }}{{ end }}{{ end }}
+`
+
+const markdownReportTemplate = `# SBOM report of {{ .Title }}
+
+Created {{ .Created }} by {{ join .Creators ", " }}, listing {{ len .Packages }} packages.
+
+## Ecosystems
+
+| Ecosystem | Packages |
+| --- | --- |
+{{- range .Ecosystems }}
+| {{ cell .Name }} | {{ .Packages }} |
+{{- end }}
+
+## Licenses
+
+| License | Packages |
+| --- | --- |
+{{- range .Licenses }}
+| {{ cell .Name }} | {{ .Packages }} |
+{{- end }}
+
+## Packages
+
+| Package | Version | Ecosystem | Supplier | License | Download location |
+| --- | --- | --- | --- | --- | --- |
+{{- range .Packages }}
+| {{ if .HomePage }}[{{ cell .Name }}]({{ .HomePage }}){{ else }}{{ cell .Name }}{{ end }} | {{ cell .Version }} | {{ cell .Ecosystem }} | {{ cell .Supplier }} | {{ cell .License }} | {{ cell .DownloadLocation }} |
+{{- end }}
+
+## Dependency tree
+
+<details>
+<summary>{{ len .Tree }} root package(s)</summary>
+
+{{ range treeLines .Tree -}}
+{{ indent .Depth }}- {{ .Node.Package.Name }}{{ with .Node.Package.Version }} {{ . }}{{ end }}{{ if gt .Depth 0 }} ({{ .Node.Relationship }}){{ end }}{{ if .Node.Repeated }}, dependencies listed above{{ end }}
+{{ end }}
+</details>
+`
+
+// reportTreeLine is a node of the dependency tree of the Markdown report at its depth
+type reportTreeLine struct {
+	Depth int
+	Node  *ReportNode
+}
+
+var reportFuncs = map[string]interface{}{
+	"join": strings.Join,
+	// cell escapes the pipes of the cells of the Markdown tables
+	"cell": func(s string) string {
+		return strings.ReplaceAll(s, "|", `\|`)
+	},
+	"indent": func(depth int) string {
+		return strings.Repeat("  ", depth)
+	},
+	// treeLines flattens the dependency tree for the nested lists of the Markdown report
+	"treeLines": func(tree []*ReportNode) []reportTreeLine {
+		lines := []reportTreeLine{}
+		var walk func(nodes []*ReportNode, depth int)
+		walk = func(nodes []*ReportNode, depth int) {
+			for _, node := range nodes {
+				lines = append(lines, reportTreeLine{Depth: depth, Node: node})
+				walk(node.Children, depth+1)
+			}
+		}
+		walk(tree, 0)
+		return lines
+	},
+}
+
+// RenderReport writes the report in the format, the HTML report being a self-contained page
+func RenderReport(w io.Writer, report Report, format ReportFormat) error {
+	if format == ReportFormatMarkdown {
+		tmpl, err := template.New("markdownReport").Funcs(reportFuncs).Parse(markdownReportTemplate)
+		if err != nil {
+			return err
+		}
+		return tmpl.Execute(w, report)
+	}
+
+	tmpl, err := htmlTemplate.New("htmlReport").Funcs(reportFuncs).Parse(htmlReportTemplate)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, report)
+}
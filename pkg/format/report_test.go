@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+var reportDocument = models.Document{
+	SPDXID: "SPDXRef-DOCUMENT",
+	CreationInfo: models.CreationInfo{
+		Created:  "2023-01-01T00:00:00Z",
+		Creators: []string{"Tool: spdx-sbom-generator-test"},
+	},
+	Packages: []models.Package{
+		{SPDXID: "SPDXRef-Package-app", PackageName: "app", PackageLicenseConcluded: "Apache-2.0", PackageDownloadLocation: noAssertion},
+		{SPDXID: "SPDXRef-Package-a-1.0", PackageName: "a", PackageVersion: "1.0", PackageLicenseConcluded: "MIT", PackageSupplier: "Organization: A | Co"},
+		{SPDXID: "SPDXRef-Package-b-2.0", PackageName: "b", PackageVersion: "2.0", PackageLicenseConcluded: noAssertion, PackageLicenseDeclared: "MIT"},
+		{SPDXID: "SPDXRef-Package-test-1.0", PackageName: "test", PackageVersion: "1.0", PackageLicenseConcluded: noAssertion},
+	},
+	Relationships: []models.Relationship{
+		{SPDXElementID: "SPDXRef-DOCUMENT", RelationshipType: "DESCRIBES", RelatedSPDXElement: "SPDXRef-Package-app"},
+		{SPDXElementID: "SPDXRef-Package-app", RelationshipType: "DEPENDS_ON", RelatedSPDXElement: "SPDXRef-Package-a-1.0"},
+		{SPDXElementID: "SPDXRef-Package-app", RelationshipType: "DEPENDS_ON", RelatedSPDXElement: "SPDXRef-Package-b-2.0"},
+		{SPDXElementID: "SPDXRef-Package-a-1.0", RelationshipType: "DEPENDS_ON", RelatedSPDXElement: "SPDXRef-Package-b-2.0"},
+		{SPDXElementID: "SPDXRef-Package-b-2.0", RelationshipType: "DEPENDS_ON", RelatedSPDXElement: "SPDXRef-Package-a-1.0"},
+		{SPDXElementID: "SPDXRef-Package-test-1.0", RelationshipType: "DEV_DEPENDENCY_OF", RelatedSPDXElement: "SPDXRef-Package-app"},
+	},
+}
+
+func TestBuildReport(t *testing.T) {
+	report := BuildReport("", []ReportSource{{
+		Document:   reportDocument,
+		Ecosystem:  "npm",
+		Ecosystems: map[string]string{"SPDXRef-Package-test-1.0": "pip"},
+	}})
+
+	assert.Equal(t, "app", report.Title)
+	assert.Equal(t, []ReportSummary{{Name: "npm", Packages: 3}, {Name: "pip", Packages: 1}}, report.Ecosystems)
+	assert.Equal(t, []ReportSummary{{Name: "MIT", Packages: 2}, {Name: "Apache-2.0", Packages: 1}, {Name: "NOASSERTION", Packages: 1}}, report.Licenses)
+	require.Len(t, report.Packages, 4)
+	assert.Equal(t, "", report.Packages[0].DownloadLocation)
+
+	// the packages are expanded once, the cycle between a and b being cut
+	require.Len(t, report.Tree, 1)
+	app := report.Tree[0]
+	require.Len(t, app.Children, 3)
+	a, b, test := app.Children[0], app.Children[1], app.Children[2]
+	assert.Equal(t, "a", a.Package.Name)
+	require.Len(t, a.Children, 1)
+	assert.True(t, a.Children[0].Children[0].Repeated)
+	assert.Equal(t, "b", b.Package.Name)
+	assert.True(t, b.Repeated)
+	assert.Empty(t, b.Children)
+	assert.Equal(t, "test", test.Package.Name)
+	assert.Equal(t, "DEV_DEPENDENCY_OF", test.Relationship)
+}
+
+func TestRenderReport(t *testing.T) {
+	report := BuildReport("", []ReportSource{{Document: reportDocument, Ecosystem: "npm"}})
+
+	buffer := &bytes.Buffer{}
+	require.NoError(t, RenderReport(buffer, report, ReportFormatMarkdown))
+	assert.Contains(t, buffer.String(), "| a | 1.0 | npm | Organization: A \\| Co | MIT |  |")
+	assert.Contains(t, buffer.String(), "- app\n  - a 1.0 (DEPENDS_ON)\n    - b 2.0 (DEPENDS_ON)\n")
+
+	buffer.Reset()
+	require.NoError(t, RenderReport(buffer, report, ReportFormatHTML))
+	assert.Contains(t, buffer.String(), "<td>Organization: A | Co</td>")
+	assert.Contains(t, buffer.String(), `<input id="search"`)
+	assert.Contains(t, buffer.String(), "<details open><summary>app</summary>")
+}
@@ -18,8 +18,21 @@ DataLicense: {{ .DataLicense }}
 SPDXID: {{ .SPDXID }}
 DocumentName: {{ .DocumentName }}
 DocumentNamespace: {{ .DocumentNamespace }}
-Creator: {{ range .CreationInfo.Creators }}{{ . -}} {{ end }}
+{{- range .CreationInfo.Creators }}
+Creator: {{ . }}
+{{- end }}
 Created: {{ .CreationInfo.Created }}
+{{- with .DocumentComment }}
+DocumentComment: <text>{{ . }}</text>
+{{- end }}
+{{- range .Annotations }}
+
+Annotator: {{ .Annotator }}
+AnnotationDate: {{ .AnnotationDate }}
+AnnotationType: {{ .AnnotationType }}
+SPDXREF: {{ .SPDXREF }}
+AnnotationComment: <text>{{ .Comment }}</text>
+{{- end }}
 
 {{ range .Packages }}
 ##### Package representing the {{.PackageName}}
@@ -30,17 +43,50 @@ SPDXID: {{ .SPDXID }}
 PackageVersion: {{ . }}
 {{- end }}
 PackageSupplier: {{ .PackageSupplier }}
+{{- with .PackageOriginator }}
+PackageOriginator: {{ . }}
+{{- end }}
 PackageDownloadLocation: {{ .PackageDownloadLocation }}
 FilesAnalyzed: {{ .FilesAnalyzed }}
+{{- with .PackageVerificationCode }}
+PackageVerificationCode: {{ .Value }}
+{{- end }}
 {{- range .PackageChecksums }}
 PackageChecksum: {{ .Algorithm }}: {{ .Value }}
 {{- end }}
 PackageHomePage: {{ .PackageHomePage }}
 PackageLicenseConcluded: {{ .PackageLicenseConcluded }}
 PackageLicenseDeclared: {{ .PackageLicenseDeclared }}
-PackageCopyrightText: {{ .PackageCopyrightText }}
-PackageLicenseComments: {{ .PackageLicenseComments }}
+{{- range .LicenseInfoFromFiles }}
+PackageLicenseInfoFromFiles: {{ . }}
+{{- end }}
+PackageCopyrightText: {{ text .PackageCopyrightText }}
+PackageLicenseComments: {{ text .PackageLicenseComments }}
 PackageComment: {{ .PackageComment }}
+{{- range .Annotations }}
+
+Annotator: {{ .Annotator }}
+AnnotationDate: {{ .AnnotationDate }}
+AnnotationType: {{ .AnnotationType }}
+SPDXREF: {{ .SPDXREF }}
+AnnotationComment: <text>{{ .Comment }}</text>
+{{- end }}
+{{- range .Files }}
+
+FileName: {{ .FileName }}
+SPDXID: {{ .SPDXID }}
+{{- range .FileTypes }}
+FileType: {{ . }}
+{{- end }}
+{{- range .Checksums }}
+FileChecksum: {{ .Algorithm }}: {{ .Value }}
+{{- end }}
+LicenseConcluded: {{ .LicenseConcluded }}
+{{- range .LicenseInfoInFiles }}
+LicenseInfoInFile: {{ . }}
+{{- end }}
+FileCopyrightText: {{ .CopyrightText }}
+{{- end }}
 {{ end }}
 {{- range .Relationships }}
 Relationship: {{ .SPDXElementID }} {{ .RelationshipType }} {{ .RelatedSPDXElement }}
@@ -50,9 +96,9 @@ Relationship: {{ .SPDXElementID }} {{ .RelationshipType }} {{ .RelatedSPDXElemen
 ##### Non-standard license
 {{ range . }}
 LicenseID: {{ .LicenseID }}
-ExtractedText: {{ .ExtractedText }}
+ExtractedText: {{ text .ExtractedText }}
 LicenseName: {{ .LicenseName }}
-LicenseComment: {{ .LicenseComment }}
+LicenseComment: {{ text .LicenseComment }}
 {{- end -}}
 {{- end -}}`
 
@@ -63,6 +109,13 @@ func (t TagValueSPDXRenderer) RenderDocument(document models.Document) ([]byte,
 		"isAsserted": func(s string) bool {
 			return !strings.Contains(s, noAssertion)
 		},
+		// values spanning several lines are wrapped in <text></text>
+		"text": func(s string) string {
+			if strings.Contains(s, "\n") {
+				return "<text>" + s + "</text>"
+			}
+			return s
+		},
 	}).Parse(tagValueTemplate)
 
 	if err != nil {
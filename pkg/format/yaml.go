@@ -0,0 +1,17 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"sigs.k8s.io/yaml"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// YamlSPDXRenderer implements an SPDXRenderer that outputs YAML formatted SPDX documents
+type YamlSPDXRenderer struct{}
+
+// RenderDocument converts the document to YAML through its JSON, the fields being named as in the JSON documents
+func (y YamlSPDXRenderer) RenderDocument(document models.Document) ([]byte, error) {
+	return yaml.Marshal(document)
+}
@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"fmt"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// limitDepth prunes the dependency graph to depth levels from the root modules,
+// depth 1 keeping the direct dependencies only. Modules the root modules don't
+// reach at all are left alone, and the modules kept lose their dependencies on
+// the pruned ones only. It reports whether anything was pruned
+func limitDepth(modules []models.Module, depth int) ([]models.Module, bool) {
+	if depth <= 0 {
+		return modules, false
+	}
+
+	index := newModuleIndex(modules)
+	roots := make([]bool, len(modules))
+	for i := range modules {
+		roots[i] = modules[i].Root
+	}
+	kept := models.WithinDepth(roots, func(i int) []int {
+		deps := []int{}
+		for _, dep := range modules[i].Modules {
+			if j, ok := index.find(dep); ok {
+				deps = append(deps, j)
+			}
+		}
+		return deps
+	}, depth)
+
+	truncated := false
+	pruned := make([]models.Module, 0, len(modules))
+	for i := range modules {
+		if !kept[i] {
+			truncated = true
+			continue
+		}
+		module := modules[i]
+		deps := make(map[string]*models.Module, len(module.Modules))
+		for key, dep := range module.Modules {
+			if j, ok := index.find(dep); ok && !kept[j] {
+				continue
+			}
+			deps[key] = dep
+		}
+		if len(deps) != len(module.Modules) {
+			module.Modules = deps
+		}
+		pruned = append(pruned, module)
+	}
+	return pruned, truncated
+}
+
+// depthComment is the comment of the annotation recording that the document is truncated
+func depthComment(depth int) string {
+	return fmt.Sprintf("The dependencies of this document are truncated to %d level(s) from the root packages, it doesn't list the full dependency graph", depth)
+}
@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// diamondModules is a diamond, root depending on a and b which both depend on c, with a
+// depending on b too and c on d. u depends on d without any root reaching it
+func diamondModules() []models.Module {
+	d := &models.Module{Name: "d", Version: "1.0.0"}
+	c := &models.Module{Name: "c", Version: "1.0.0", Modules: map[string]*models.Module{"d": d}}
+	b := &models.Module{Name: "b", Version: "1.0.0", Modules: map[string]*models.Module{"c": c}}
+	a := &models.Module{Name: "a", Version: "1.0.0", Modules: map[string]*models.Module{"b": b, "c": c}}
+	u := &models.Module{Name: "u", Version: "1.0.0", Modules: map[string]*models.Module{"d": d}}
+	root := models.Module{Name: "root", Version: "1.0.0", Root: true, Modules: map[string]*models.Module{"a": a, "b": b}}
+	return []models.Module{root, *a, *b, *c, *d, *u}
+}
+
+func TestLimitDepth(t *testing.T) {
+	full := map[string][]string{
+		"root": {"a", "b"}, "a": {"b", "c"}, "b": {"c"}, "c": {"d"}, "d": {}, "u": {"d"},
+	}
+	for _, test := range []struct {
+		name      string
+		depth     int
+		expected  map[string][]string
+		truncated bool
+	}{
+		{"no limit", 0, full, false},
+		// a keeps its dependency on b, at the same level, and u loses the one on the pruned d
+		{"direct dependencies", 1, map[string][]string{
+			"root": {"a", "b"}, "a": {"b"}, "b": {}, "u": {},
+		}, true},
+		{"two levels", 2, map[string][]string{
+			"root": {"a", "b"}, "a": {"b", "c"}, "b": {"c"}, "c": {}, "u": {},
+		}, true},
+		{"graph height", 3, full, false},
+		{"beyond the graph height", 10, full, false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			pruned, truncated := limitDepth(diamondModules(), test.depth)
+			assert.Equal(t, test.truncated, truncated)
+			graph := map[string][]string{}
+			for _, module := range pruned {
+				deps := []string{}
+				for _, dep := range module.Modules {
+					deps = append(deps, dep.Name)
+				}
+				sort.Strings(deps)
+				graph[module.Name] = deps
+			}
+			assert.Equal(t, test.expected, graph)
+		})
+	}
+}
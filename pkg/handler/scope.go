@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// excludeScopes removes the dependencies of the excluded scopes, along with the
+// modules which are only reachable from the root modules through them. Modules
+// the root modules don't reach at all are left alone
+func excludeScopes(modules []models.Module, scopes []models.Scope) []models.Module {
+	if len(scopes) == 0 {
+		return modules
+	}
+	excluded := map[models.Scope]bool{}
+	for _, scope := range scopes {
+		excluded[scope] = true
+	}
+
+	index := newModuleIndex(modules)
+	reachable := reachableModules(modules, index, map[models.Scope]bool{})
+	kept := reachableModules(modules, index, excluded)
+
+	pruned := make([]models.Module, 0, len(modules))
+	for i := range modules {
+		if reachable[i] && !kept[i] {
+			continue
+		}
+		module := modules[i]
+		module.Modules = make(map[string]*models.Module, len(modules[i].Modules))
+		for key, dep := range modules[i].Modules {
+			if !excluded[dep.Scope] {
+				module.Modules[key] = dep
+			}
+		}
+		pruned = append(pruned, module)
+	}
+	return pruned
+}
+
+// reachableModules flags the modules reachable from the root modules through
+// the dependencies which aren't of the excluded scopes
+func reachableModules(modules []models.Module, index moduleIndex, excluded map[models.Scope]bool) []bool {
+	reachable := make([]bool, len(modules))
+	queue := []int{}
+	for i := range modules {
+		if modules[i].Root {
+			reachable[i] = true
+			queue = append(queue, i)
+		}
+	}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, dep := range modules[current].Modules {
+			if excluded[dep.Scope] {
+				continue
+			}
+			if j, ok := index.find(dep); ok && !reachable[j] {
+				reachable[j] = true
+				queue = append(queue, j)
+			}
+		}
+	}
+	return reachable
+}
@@ -5,14 +5,17 @@ package handler
 import (
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/spdx/spdx-sbom-generator/pkg/curation"
 	"github.com/spdx/spdx-sbom-generator/pkg/format"
 	"github.com/spdx/spdx-sbom-generator/pkg/helper"
 	"github.com/spdx/spdx-sbom-generator/pkg/models"
 	"github.com/spdx/spdx-sbom-generator/pkg/modules"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/gomod"
 )
 
 var errNoModuleManagerFound = errors.New("No module manager found")
@@ -20,14 +23,42 @@ var errOutputDirDoesNotExist = errors.New("Output Directory does not exist")
 
 // SPDXSettings ...
 type SPDXSettings struct {
-	Version           string
-	Path              string
-	License           bool
-	Depth             string
-	OutputDir         string
-	Schema            string
-	Format            models.OutputFormat
-	GlobalSettingFile string
+	Version              string
+	Path                 string
+	License              bool
+	Depth                int
+	OutputDir            string
+	Schema               string
+	Format               models.OutputFormat
+	GlobalSettingFile    string
+	SplitModules         bool
+	ExcludeScopes        []models.Scope
+	GradleConfigurations []string
+	GoPackages           []string
+	GOOS                 string
+	GOARCH               string
+	GoTags               []string
+	AnalyzeFiles         bool
+	// LicenseThreshold is the confidence a detected license needs to be concluded, 0 for the default
+	LicenseThreshold float32
+	// Reports are the formats of the human-readable reports written along the documents
+	Reports []format.ReportFormat
+	// Columns are the columns of the csv and tsv package lists, all of them if empty
+	Columns []string
+	// Creators are the creators of the documents besides the tool, as "Organization: Acme Inc.",
+	// and Namespace the base URI of their namespaces
+	Creators  []string
+	Namespace string
+	// DocumentName is the name of the documents of the plugins, the ones of the deployable
+	// modules keeping theirs, and DocumentComment the comment of all of them
+	DocumentName    string
+	DocumentComment string
+	// Curations override the metadata of the packages they match
+	Curations curation.Curations
+	// EnablePlugins are the slugs of the only plugins run, all of them if empty, and DisablePlugins
+	// the slugs of the plugins not run
+	EnablePlugins  []string
+	DisablePlugins []string
 }
 
 type spdxHandler struct {
@@ -36,6 +67,8 @@ type spdxHandler struct {
 	format         format.Format
 	outputFiles    map[string]string
 	errors         map[string]error
+	// reportSources are the documents written, one per plugin, for the reports
+	reportSources []format.ReportSource
 }
 
 // getFiletypeForOutputFormat gets the type suffix for the type of output chosen
@@ -45,6 +78,14 @@ func getFiletypeForOutputFormat(outputFormat models.OutputFormat) string {
 		return "spdx" // nolint
 	case models.OutputFormatJson:
 		return "json"
+	case models.OutputFormatCsv:
+		return "csv"
+	case models.OutputFormatTsv:
+		return "tsv"
+	case models.OutputFormatYaml:
+		return "yaml"
+	case models.OutputFormatRdf:
+		return "rdf"
 	default:
 		return "spdx"
 	}
@@ -56,9 +97,23 @@ func NewSPDX(settings SPDXSettings) (Handler, error) {
 		return nil, errOutputDirDoesNotExist
 	}
 
+	// the plugins detect the licenses of the modules with the helper
+	if settings.LicenseThreshold > 0 {
+		helper.LicenseThreshold = settings.LicenseThreshold
+	}
+
 	mm, err := modules.New(modules.Config{
-		Path:              settings.Path,
-		GlobalSettingFile: settings.GlobalSettingFile,
+		Path:                 settings.Path,
+		GlobalSettingFile:    settings.GlobalSettingFile,
+		GradleConfigurations: settings.GradleConfigurations,
+		GoBuild: gomod.Build{
+			Packages: settings.GoPackages,
+			GOOS:     settings.GOOS,
+			GOARCH:   settings.GOARCH,
+			Tags:     settings.GoTags,
+		},
+		EnablePlugins:  settings.EnablePlugins,
+		DisablePlugins: settings.DisablePlugins,
 	})
 	if err != nil {
 		return nil, err
@@ -82,7 +137,6 @@ func (sh *spdxHandler) Run() error {
 		plugin := mm.Plugin.GetMetadata()
 		filename := fmt.Sprintf("bom-%s.%s", plugin.Slug, getFiletypeForOutputFormat(sh.config.Format))
 		outputFile := filepath.Join(sh.config.OutputDir, filename)
-		globalSettingFile := sh.config.GlobalSettingFile
 
 		log.Infof("Running generator for Module Manager: `%s` with output `%s`", plugin.Slug, outputFile)
 		if err := mm.Run(); err != nil {
@@ -90,29 +144,95 @@ func (sh *spdxHandler) Run() error {
 			continue
 		}
 
-		format, err := format.New(format.Config{
-			Filename:     outputFile,
-			ToolVersion:  sh.config.Version,
-			OutputFormat: sh.config.Format,
-			GetSource: func() []models.Module {
-				return mm.GetSource()
-			},
-			GlobalSettingFile: globalSettingFile,
-		})
+		modules := excludeScopes(mm.GetSource(), sh.config.ExcludeScopes)
+		modules, truncated := limitDepth(modules, sh.config.Depth)
+		annotations := []string{}
+		if truncated {
+			annotations = append(annotations, depthComment(sh.config.Depth))
+		}
+
+		document, err := sh.render(outputFile, plugin.Slug, sh.config.DocumentName, modules, annotations)
 		if err != nil {
 			sh.errors[plugin.Slug] = err
 			continue
 		}
-		if err := format.Render(); err != nil {
-			sh.errors[plugin.Slug] = err
+		sh.outputFiles[plugin.Slug] = outputFile
+		sh.reportSources = append(sh.reportSources, format.ReportSource{Document: *document, Ecosystem: plugin.Slug})
+
+		if !sh.config.SplitModules {
 			continue
 		}
-		sh.outputFiles[plugin.Slug] = outputFile
+
+		for name, modules := range splitDeployableModules(modules) {
+			moduleSlug := fmt.Sprintf("%s-%s", plugin.Slug, name)
+			filename := fmt.Sprintf("bom-%s.%s", moduleSlug, getFiletypeForOutputFormat(sh.config.Format))
+			moduleFile := filepath.Join(sh.config.OutputDir, filename)
+			if _, err := sh.render(moduleFile, plugin.Slug, "", modules, annotations); err != nil {
+				sh.errors[moduleSlug] = err
+				continue
+			}
+			sh.outputFiles[moduleSlug] = moduleFile
+		}
+	}
+
+	return sh.writeReports()
+}
+
+// writeReports writes the reports of the documents of all the plugins
+func (sh *spdxHandler) writeReports() error {
+	if len(sh.reportSources) == 0 {
+		return nil
 	}
 
+	report := format.BuildReport("", sh.reportSources)
+	for _, reportFormat := range sh.config.Reports {
+		outputFile := filepath.Join(sh.config.OutputDir, reportFormat.Filename())
+		file, err := os.Create(outputFile)
+		if err != nil {
+			return err
+		}
+		err = format.RenderReport(file, report, reportFormat)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("writing the report %s: %w", outputFile, err)
+		}
+		log.Infof("Report written to %s", outputFile)
+	}
 	return nil
 }
 
+// render writes the SPDX document of the modules of the ecosystem to outputFile, annotated with annotations,
+// named documentName unless empty
+func (sh *spdxHandler) render(outputFile, ecosystem, documentName string, modules []models.Module, annotations []string) (*models.Document, error) {
+	format, err := format.New(format.Config{
+		Filename:     outputFile,
+		ToolVersion:  sh.config.Version,
+		OutputFormat: sh.config.Format,
+		GetSource: func() []models.Module {
+			return modules
+		},
+		GlobalSettingFile:  sh.config.GlobalSettingFile,
+		Annotations:        annotations,
+		AnalyzeFiles:       sh.config.AnalyzeFiles,
+		SourcePath:         sh.config.Path,
+		IncludeLicenseText: sh.config.License,
+		Ecosystem:          ecosystem,
+		Columns:            sh.config.Columns,
+		Creators:           sh.config.Creators,
+		Namespace:          sh.config.Namespace,
+		DocumentName:       documentName,
+		DocumentComment:    sh.config.DocumentComment,
+		Curations:          sh.config.Curations,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return format.RenderDocument()
+}
+
 // Complete ...
 func (sh *spdxHandler) Complete() error {
 	if len(sh.errors) > 0 {
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"fmt"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// splitDeployableModules returns, for every deployable module of a multi-module
+// project, the module as the root of its own dependency subtree
+func splitDeployableModules(modules []models.Module) map[string][]models.Module {
+	index := newModuleIndex(modules)
+
+	split := map[string][]models.Module{}
+	for i := range modules {
+		if modules[i].Root || !modules[i].Deployable {
+			continue
+		}
+
+		root := modules[i]
+		root.Root = true
+		subtree := []models.Module{root}
+		visited := map[int]bool{i: true}
+		queue := []*models.Module{&root}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			for _, dep := range current.Modules {
+				j, ok := index.find(dep)
+				if !ok || visited[j] {
+					continue
+				}
+				visited[j] = true
+
+				mod := modules[j]
+				mod.Root = false
+				subtree = append(subtree, mod)
+				queue = append(queue, &mod)
+			}
+		}
+		split[root.Name] = subtree
+	}
+
+	return split
+}
+
+// moduleIndex finds modules by name and version, or by name only for the
+// references to modules which don't carry the resolved version
+type moduleIndex map[string]int
+
+func newModuleIndex(modules []models.Module) moduleIndex {
+	index := moduleIndex{}
+	for i := range modules {
+		index[moduleKey(modules[i].Name, modules[i].Version)] = i
+		if _, ok := index[modules[i].Name]; !ok {
+			index[modules[i].Name] = i
+		}
+	}
+	return index
+}
+
+func (index moduleIndex) find(module *models.Module) (int, bool) {
+	if i, ok := index[moduleKey(module.Name, module.Version)]; ok {
+		return i, true
+	}
+	i, ok := index[module.Name]
+	return i, ok
+}
+
+func moduleKey(name, version string) string {
+	return fmt.Sprintf("%s@%s", name, version)
+}
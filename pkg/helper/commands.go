@@ -5,6 +5,7 @@ package helper
 import (
 	"errors"
 	"io"
+	"os"
 	"os/exec"
 )
 
@@ -15,6 +16,8 @@ type CmdOptions struct {
 	Name      string
 	Args      []string
 	Directory string
+	// Env is added to the environment of the command, e.g. "GOOS=linux"
+	Env []string
 }
 
 // Cmd ...
@@ -38,6 +41,9 @@ func (c *Cmd) Build() error {
 
 	c.cmd = exec.Command(c.options.Name, c.options.Args...)
 	c.cmd.Dir = c.options.Directory
+	if len(c.options.Env) > 0 {
+		c.cmd.Env = append(os.Environ(), c.options.Env...)
+	}
 
 	return nil
 }
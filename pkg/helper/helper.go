@@ -4,19 +4,14 @@ package helper
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
-	"io/ioutil"
+	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
 
-	"github.com/go-enry/go-license-detector/v4/licensedb"
-	log "github.com/sirupsen/logrus"
-
 	"github.com/spdx/spdx-sbom-generator/pkg/licenses"
-	"github.com/spdx/spdx-sbom-generator/pkg/models"
 )
 
 const copyrightLookup = "copyright"
@@ -65,30 +60,9 @@ func Exists(filepath string) bool {
 	return true
 }
 
-// GetLicenses ...
-func GetLicenses(modulePath string) (*models.License, error) {
-	if modulePath != "" {
-		licenses := licensedb.Analyse(modulePath)
-		for i := range licenses {
-			for j := range licenses[i].Matches {
-				//returns the first element, the best match
-				return &models.License{ID: licenses[i].Matches[j].License,
-					Name:          licenses[i].Matches[j].License,
-					ExtractedText: extractLicenseContent(modulePath, licenses[i].Matches[j].File),
-					Comments:      "",
-					File:          licenses[i].Matches[j].File}, nil
-			}
-		}
-	}
-	return nil, errors.New(fmt.Sprintf("could not detect license for %s\n", modulePath))
-}
-
 // LicenseExist ...
 func LicenseSPDXExists(license string) bool {
-	if _, ok := licenses.DB[license]; !ok {
-		return false
-	}
-	return true
+	return licenses.Exists(license)
 }
 
 // BuildModuleName ...
@@ -119,18 +93,6 @@ func BuildLicenseConcluded(license string) string {
 	return fmt.Sprintf("LicenseRef-%s", license)
 }
 
-// todo: figure out how to extract only required text
-func extractLicenseContent(path, filename string) string {
-	bytes, err := ioutil.ReadFile(fmt.Sprintf("%s/%s", path, filename))
-	if err != nil {
-		log.Errorf("Could not read license file: %v", err)
-		return ""
-	}
-
-	// extract license required segment
-	return string(bytes)
-}
-
 // GetCopyright parses the license file found at plugin module or vendor folder
 // Extract the text found starting with the keyword 'Copyright (c)' and until the newline
 func GetCopyright(content string) string {
@@ -199,3 +161,52 @@ func RemoveURLProtocol(url string) string {
 	trimmedURL = strings.TrimPrefix(trimmedURL, "http://")
 	return trimmedURL
 }
+
+// ParseActor parses an SPDX creator or supplier, as "Organization: Acme Inc. (sbom@acme.com)",
+// into its type, name and email, the type being one of types
+func ParseActor(actor string, types ...string) (string, string, string, error) {
+	actorType, rest, found := strings.Cut(actor, ":")
+	actorType, rest = strings.TrimSpace(actorType), strings.TrimSpace(rest)
+	valid := false
+	for _, t := range types {
+		if strings.EqualFold(actorType, t) {
+			actorType, valid = t, true
+			break
+		}
+	}
+	if !found || !valid || rest == "" {
+		return "", "", "", fmt.Errorf("invalid %q, expected %s: name (email)", actor, strings.Join(types, ", "))
+	}
+
+	name, email := rest, ""
+	if i := strings.LastIndex(rest, "("); i >= 0 && strings.HasSuffix(rest, ")") {
+		name, email = strings.TrimSpace(rest[:i]), strings.TrimSpace(rest[i+1:len(rest)-1])
+	}
+	return actorType, name, email, nil
+}
+
+// FormatActor formats an actor parsed by ParseActor back, as "Organization: Acme Inc. (sbom@acme.com)"
+func FormatActor(actorType, name, email string) string {
+	if email == "" {
+		return fmt.Sprintf("%s: %s", actorType, name)
+	}
+	return fmt.Sprintf("%s: %s (%s)", actorType, name, email)
+}
+
+// SameSlug tells whether the name is the slug of a plugin, regardless of the case, the dashes
+// and the underscores, gomod being go-mod
+func SameSlug(name, slug string) bool {
+	normalize := func(s string) string {
+		return strings.ToLower(strings.NewReplacer("-", "", "_", "").Replace(strings.TrimSpace(s)))
+	}
+	return normalize(name) == normalize(slug)
+}
+
+// CheckNamespace checks that a base URI of document namespaces is an absolute URI without fragment
+func CheckNamespace(namespace string) error {
+	u, err := url.Parse(namespace)
+	if err != nil || !u.IsAbs() || strings.Contains(namespace, "#") {
+		return fmt.Errorf("invalid namespace %q, expected an absolute URI without #", namespace)
+	}
+	return nil
+}
@@ -27,6 +27,28 @@ func TestGetCopyright(t *testing.T) {
 	assert.Equal(t, "Copyright (c) Dylan Greene", res)
 }
 
+func TestParseActor(t *testing.T) {
+	actorType, name, email, err := ParseActor("organization: Acme Inc. (sbom@acme.com)", "Person", "Organization")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Organization", "Acme Inc.", "sbom@acme.com"}, []string{actorType, name, email})
+
+	_, name, email, err = ParseActor("Person: Jane Doe", "Person")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Jane Doe", ""}, []string{name, email})
+
+	for _, actor := range []string{"Jane Doe", "Tool: sbomgen", "Person:"} {
+		_, _, _, err = ParseActor(actor, "Person", "Organization")
+		assert.Error(t, err, actor)
+	}
+}
+
+func TestSameSlug(t *testing.T) {
+	assert.True(t, SameSlug("go-mod", "go-mod"))
+	assert.True(t, SameSlug("gomod", "go-mod"))
+	assert.True(t, SameSlug(" java_maven", "Java-Maven"))
+	assert.False(t, SameSlug("npm", "yarn"))
+}
+
 func getPath() string {
 	cmd := exec.Command("pwd")
 	output, err := cmd.Output()
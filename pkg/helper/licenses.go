@@ -0,0 +1,382 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package helper
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-enry/go-license-detector/v4/licensedb"
+	"github.com/go-enry/go-license-detector/v4/licensedb/filer"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/files"
+	"github.com/spdx/spdx-sbom-generator/pkg/licenses"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// DefaultLicenseThreshold is the confidence a license match needs to be concluded,
+// the matches below it being only reported in the license comments
+const DefaultLicenseThreshold = 0.85
+
+// LicenseThreshold is the confidence threshold the plugins detect licenses with
+var LicenseThreshold float32 = DefaultLicenseThreshold
+
+// the characters of SPDX license expressions
+var licenseExpression = regexp.MustCompile(`^[A-Za-z0-9.+:() -]+$`)
+
+// license files named after one of the licenses of a package, LICENSE-MIT, LICENSE.APACHE...
+var alternativeLicenseFile = regexp.MustCompile(`(?i)^(?:licen[cs]e|copying)[-._]([a-z0-9.-]+?)(?:\.txt|\.md)?$`)
+
+// LicenseMatch is a license matched in a file of a package
+type LicenseMatch struct {
+	License    string
+	File       string
+	Confidence float32
+}
+
+func (m LicenseMatch) String() string {
+	return fmt.Sprintf("%s (%s, confidence %.0f%%)", m.File, m.License, m.Confidence*100)
+}
+
+// LicenseDetection is the result of detecting the licenses of a package
+type LicenseDetection struct {
+	// Matches are the best match of each license file reaching the threshold
+	Matches []LicenseMatch
+	// LowConfidence are the best matches of the license files below the threshold
+	LowConfidence []LicenseMatch
+	// Expression combines the licenses of the matches, "MIT OR Apache-2.0"
+	Expression string
+	// texts are the contents of the files of the matches
+	texts map[string]string
+}
+
+// DetectLicenses detects the licenses of the package at modulePath, keeping the
+// best match of every license file whose confidence reaches threshold
+func DetectLicenses(modulePath string, threshold float32) (*LicenseDetection, error) {
+	if modulePath == "" {
+		return nil, fmt.Errorf("could not detect license, no path given")
+	}
+
+	f, err := filer.FromDirectory(modulePath)
+	if err != nil {
+		return nil, err
+	}
+	detection, err := detectLicenses(f, threshold)
+	if err != nil {
+		return nil, fmt.Errorf("could not detect license for %s: %w", modulePath, err)
+	}
+	return detection, nil
+}
+
+// DetectLicensesFromFS works like DetectLicenses for packages which are not unpacked
+// on disk, e.g. the zip archives of the Yarn Berry cache
+func DetectLicensesFromFS(fsys fs.FS, threshold float32) (*LicenseDetection, error) {
+	return detectLicenses(filer.FromFS(fsys), threshold)
+}
+
+func detectLicenses(f filer.Filer, threshold float32) (*LicenseDetection, error) {
+	matches, err := licensedb.Detect(f)
+	if err != nil {
+		return nil, err
+	}
+
+	// the licenses matching a file are alternatives, the file being only given its best match
+	best := map[string]LicenseMatch{}
+	for license, match := range matches {
+		files := match.Files
+		if len(files) == 0 {
+			files = map[string]float32{match.File: match.Confidence}
+		}
+		for file, confidence := range files {
+			current, ok := best[file]
+			if !ok || confidence > current.Confidence || confidence == current.Confidence && license < current.License {
+				best[file] = LicenseMatch{License: license, File: file, Confidence: confidence}
+			}
+		}
+	}
+
+	detection := &LicenseDetection{texts: map[string]string{}}
+	for _, match := range best {
+		if match.Confidence < threshold {
+			detection.LowConfidence = append(detection.LowConfidence, match)
+			continue
+		}
+		detection.Matches = append(detection.Matches, match)
+		if content, err := f.ReadFile(match.File); err == nil {
+			detection.texts[match.File] = string(content)
+		}
+	}
+	if len(detection.Matches) == 0 && len(detection.LowConfidence) == 0 {
+		return nil, licensedb.ErrNoLicenseFound
+	}
+
+	sortLicenseMatches(detection.Matches)
+	sortLicenseMatches(detection.LowConfidence)
+	detection.Expression = buildLicenseExpression(detection.Matches)
+	return detection, nil
+}
+
+// sortLicenseMatches sorts the matches by decreasing confidence, then by file
+func sortLicenseMatches(matches []LicenseMatch) {
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Confidence != matches[j].Confidence {
+			return matches[i].Confidence > matches[j].Confidence
+		}
+		return matches[i].File < matches[j].File
+	})
+}
+
+// buildLicenseExpression combines the licenses of the matches. License files named
+// after their license, as LICENSE-MIT and LICENSE-APACHE, let the user choose one of
+// them, the licenses of other files all apply
+func buildLicenseExpression(matches []LicenseMatch) string {
+	licenses := []string{}
+	seen := map[string]bool{}
+	alternatives := true
+	for _, match := range matches {
+		if !isAlternativeLicenseFile(match) {
+			alternatives = false
+		}
+		if !seen[match.License] {
+			seen[match.License] = true
+			licenses = append(licenses, BuildLicenseConcluded(match.License))
+		}
+	}
+	if len(licenses) == 0 {
+		return ""
+	}
+
+	sort.Strings(licenses)
+	operator := " AND "
+	if alternatives {
+		operator = " OR "
+	}
+	return strings.Join(licenses, operator)
+}
+
+// isAlternativeLicenseFile reports whether the license file of the match is named after
+// its license, unlike LICENSE.code and LICENSE.docs whose licenses apply to parts of the package
+func isAlternativeLicenseFile(match LicenseMatch) bool {
+	name := alternativeLicenseFile.FindStringSubmatch(path.Base(match.File))
+	if name == nil {
+		return false
+	}
+	return strings.HasPrefix(strings.ToLower(match.License), strings.ToLower(name[1]))
+}
+
+// Licenses returns the concluded licenses, one per license, their text being the
+// content of the file they were matched in
+func (d *LicenseDetection) Licenses() []*models.License {
+	licenses := []*models.License{}
+	seen := map[string]bool{}
+	for _, match := range d.Matches {
+		if seen[match.License] {
+			continue
+		}
+		seen[match.License] = true
+		licenses = append(licenses, &models.License{
+			ID:            match.License,
+			Name:          match.License,
+			ExtractedText: d.texts[match.File],
+			Comments:      fmt.Sprintf("Detected in %s", match),
+			File:          match.File,
+		})
+	}
+	return licenses
+}
+
+// OtherLicenses returns the concluded licenses which aren't on the SPDX license
+// list, identified by LicenseRef- identifiers
+func (d *LicenseDetection) OtherLicenses() []*models.License {
+	others := []*models.License{}
+	for _, license := range d.Licenses() {
+		if LicenseSPDXExists(license.ID) {
+			continue
+		}
+		license.ID = BuildLicenseConcluded(license.ID)
+		others = append(others, license)
+	}
+	return others
+}
+
+// Text joins the texts of the license files of the matches, e.g. for their copyright statements
+func (d *LicenseDetection) Text() string {
+	texts := []string{}
+	for _, match := range d.Matches {
+		if text, ok := d.texts[match.File]; ok {
+			texts = append(texts, text)
+		}
+	}
+	return strings.Join(texts, "\n\n")
+}
+
+// Comments records the evidence of the concluded licenses and the matches
+// below the threshold, for the license comments of the package
+func (d *LicenseDetection) Comments() string {
+	comments := []string{}
+	if len(d.Matches) > 0 {
+		comments = append(comments, fmt.Sprintf("Concluded from %s.", joinLicenseMatches(d.Matches)))
+	}
+	if len(d.LowConfidence) > 0 {
+		comments = append(comments, fmt.Sprintf("Low-confidence matches, not concluded: %s.", joinLicenseMatches(d.LowConfidence)))
+	}
+	return strings.Join(comments, " ")
+}
+
+func joinLicenseMatches(matches []LicenseMatch) string {
+	s := make([]string, 0, len(matches))
+	for _, match := range matches {
+		s = append(s, match.String())
+	}
+	return strings.Join(s, ", ")
+}
+
+// SetModuleLicenses sets the licenses the detection concluded on module, the
+// licenses which aren't on the SPDX license list being added to its other licenses
+func SetModuleLicenses(module *models.Module, detection *LicenseDetection) {
+	module.CommentsLicense = detection.Comments()
+	if detection.Expression == "" {
+		return
+	}
+
+	module.LicenseDeclared = detection.Expression
+	module.LicenseConcluded = detection.Expression
+	module.OtherLicense = append(module.OtherLicense, detection.OtherLicenses()...)
+}
+
+// ValidLicenseExpression reports whether expression is an SPDX license expression of
+// licenses of the SPDX license list and LicenseRef- licenses, e.g. not the "MIT/Apache-2.0"
+// of old package metadata, whatever the case of its identifiers
+func ValidLicenseExpression(expression string) bool {
+	_, _, ok := NormalizeLicenseExpression(expression)
+	return ok
+}
+
+// NormalizeLicenseExpression corrects the case of the identifiers and operators of an SPDX
+// license expression, "mit or apache-2.0" becoming "MIT OR Apache-2.0", and returns notes
+// on its deprecated identifiers, as GPL-2.0 which is either GPL-2.0-only or GPL-2.0-or-later.
+// It reports whether the expression is valid
+func NormalizeLicenseExpression(expression string) (string, []string, bool) {
+	if !licenseExpression.MatchString(expression) || len(files.ParseLicenseExpression(expression)) == 0 {
+		return expression, nil, false
+	}
+
+	tokens := splitLicenseTokens(expression)
+	notes := []string{}
+	for i, token := range tokens {
+		switch upper := strings.ToUpper(token); {
+		case token == "(" || token == ")":
+		case upper == "AND" || upper == "OR" || upper == "WITH":
+			tokens[i] = upper
+		case i > 0 && tokens[i-1] == "WITH":
+			exception, ok := licenses.GetException(token)
+			if !ok {
+				return expression, nil, false
+			}
+			tokens[i] = exception.ID
+			if exception.IsDeprecated {
+				notes = append(notes, fmt.Sprintf("%s is a deprecated SPDX license exception identifier.", exception.ID))
+			}
+		case strings.HasPrefix(upper, "LICENSEREF-"), strings.HasPrefix(upper, "DOCUMENTREF-"):
+			tokens[i] = normalizeLicenseRef(token)
+		default:
+			id := strings.TrimSuffix(token, "+")
+			license, ok := licenses.Get(id)
+			if !ok {
+				return expression, nil, false
+			}
+			tokens[i] = license.ID + strings.TrimPrefix(token, id)
+			if license.IsDeprecated {
+				notes = append(notes, fmt.Sprintf("%s is a deprecated SPDX license identifier.", license.ID))
+			}
+		}
+	}
+
+	return joinLicenseTokens(tokens), notes, true
+}
+
+// normalizeLicenseRef corrects the case of the prefixes of LicenseRef-custom and
+// DocumentRef-doc:LicenseRef-custom, their identifiers being kept as they are
+func normalizeLicenseRef(ref string) string {
+	parts := strings.Split(ref, ":")
+	for i, part := range parts {
+		for _, prefix := range []string{"LicenseRef-", "DocumentRef-"} {
+			if strings.HasPrefix(strings.ToUpper(part), strings.ToUpper(prefix)) {
+				parts[i] = prefix + part[len(prefix):]
+			}
+		}
+	}
+	return strings.Join(parts, ":")
+}
+
+// AppendLicenseNotes appends the notes NormalizeLicenseExpression returned, once each,
+// to the license comments of a package
+func AppendLicenseNotes(comments string, notes ...string) string {
+	seen := map[string]bool{}
+	for _, note := range notes {
+		if seen[note] || strings.Contains(comments, note) {
+			continue
+		}
+		seen[note] = true
+		comments = strings.TrimSpace(comments + " " + note)
+	}
+	return comments
+}
+
+// CanonicalLicenses returns the licenses of the expression which are on the SPDX license
+// list with their canonical text, for the documents including the full license texts
+func CanonicalLicenses(expression string) []*models.License {
+	canonical := []*models.License{}
+	for _, id := range files.ParseLicenseExpression(expression) {
+		license, ok := licenses.Get(strings.TrimSuffix(id, "+"))
+		if !ok {
+			continue
+		}
+		text, ok := licenses.Text(license.ID)
+		if !ok {
+			continue
+		}
+		canonical = append(canonical, &models.License{
+			ID:            license.ID,
+			Name:          license.Name,
+			ExtractedText: text,
+			Comments:      fmt.Sprintf("Text of the SPDX License List %s", licenses.Version()),
+		})
+	}
+	return canonical
+}
+
+// SameLicenseText reports whether two license texts are the same, whatever their line wrapping
+func SameLicenseText(a, b string) bool {
+	return strings.Join(strings.Fields(a), " ") == strings.Join(strings.Fields(b), " ")
+}
+
+// RenameLicenseRefs replaces the identifiers of the expression which renamed maps, the
+// LicenseRef- licenses whose text a document already holds under another identifier
+func RenameLicenseRefs(expression string, renamed map[string]string) string {
+	if len(renamed) == 0 {
+		return expression
+	}
+	tokens := splitLicenseTokens(expression)
+	for i, token := range tokens {
+		if id, ok := renamed[token]; ok {
+			tokens[i] = id
+		}
+	}
+	return joinLicenseTokens(tokens)
+}
+
+// splitLicenseTokens splits an expression into its identifiers, operators and parentheses
+func splitLicenseTokens(expression string) []string {
+	return strings.Fields(strings.NewReplacer("(", " ( ", ")", " ) ").Replace(expression))
+}
+
+// joinLicenseTokens joins the tokens splitLicenseTokens returned into an expression
+func joinLicenseTokens(tokens []string) string {
+	expression := strings.Join(tokens, " ")
+	return strings.ReplaceAll(strings.ReplaceAll(expression, "( ", "("), " )", ")")
+}
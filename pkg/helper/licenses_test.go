@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package helper
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-enry/go-license-detector/v4/licensedb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// writeLicenseFiles copies license texts of the repository to a temporary package
+func writeLicenseFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, source := range files {
+		content, err := os.ReadFile(filepath.Join("..", "..", filepath.FromSlash(source)))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), content, 0o644))
+	}
+	return dir
+}
+
+func TestDetectLicenses(t *testing.T) {
+	tests := []struct {
+		name       string
+		files      map[string]string
+		expression string
+	}{
+		{
+			name:       "single license file",
+			files:      map[string]string{"LICENSE": "LICENSES/Apache-2.0.txt"},
+			expression: "Apache-2.0",
+		},
+		{
+			name: "license files named after their license are alternatives",
+			files: map[string]string{
+				"LICENSE-APACHE": "LICENSES/Apache-2.0.txt",
+				"LICENSE-MIT":    "pkg/modules/swift/test/LICENSE.txt",
+			},
+			expression: "Apache-2.0 OR MIT",
+		},
+		{
+			name: "license files of parts of the package all apply",
+			files: map[string]string{
+				"LICENSE.code": "LICENSES/Apache-2.0.txt",
+				"LICENSE.docs": "LICENSES/CC-BY-4.0.txt",
+			},
+			expression: "Apache-2.0 AND CC-BY-4.0",
+		},
+		{
+			name: "other license files all apply",
+			files: map[string]string{
+				"LICENSE": "LICENSES/Apache-2.0.txt",
+				"COPYING": "pkg/modules/swift/test/LICENSE.txt",
+			},
+			expression: "Apache-2.0 AND MIT",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			detection, err := DetectLicenses(writeLicenseFiles(t, test.files), DefaultLicenseThreshold)
+			require.NoError(t, err)
+			assert.Equal(t, test.expression, detection.Expression)
+			assert.Len(t, detection.Matches, len(test.files))
+			assert.Contains(t, detection.Comments(), "Concluded from ")
+		})
+	}
+}
+
+func TestDetectLicensesThreshold(t *testing.T) {
+	dir := writeLicenseFiles(t, map[string]string{"LICENSE": "pkg/modules/swift/test/LICENSE.txt"})
+
+	// no match reaches a confidence over 100%
+	detection, err := DetectLicenses(dir, 1.01)
+	require.NoError(t, err)
+	assert.Empty(t, detection.Expression)
+	assert.Empty(t, detection.Matches)
+	require.Len(t, detection.LowConfidence, 1)
+	assert.Equal(t, "MIT", detection.LowConfidence[0].License)
+	assert.Contains(t, detection.Comments(), "Low-confidence matches, not concluded: LICENSE (MIT, confidence")
+
+	module := &models.Module{}
+	SetModuleLicenses(module, detection)
+	assert.Empty(t, module.LicenseConcluded)
+	assert.Equal(t, detection.Comments(), module.CommentsLicense)
+}
+
+func TestDetectLicensesFromFS(t *testing.T) {
+	content, err := os.ReadFile(filepath.Join("..", "..", "pkg", "modules", "swift", "test", "LICENSE.txt"))
+	require.NoError(t, err)
+
+	detection, err := DetectLicensesFromFS(fstest.MapFS{"LICENSE": {Data: content}}, DefaultLicenseThreshold)
+	require.NoError(t, err)
+	assert.Equal(t, "MIT", detection.Expression)
+
+	// a package without license files is an error, rather than a blank license
+	_, err = DetectLicensesFromFS(fstest.MapFS{"index.js": {Data: []byte("module.exports = {}")}}, DefaultLicenseThreshold)
+	assert.ErrorIs(t, err, licensedb.ErrNoLicenseFound)
+}
+
+func TestValidLicenseExpression(t *testing.T) {
+	assert.True(t, ValidLicenseExpression("MIT"))
+	assert.True(t, ValidLicenseExpression("(MIT OR Apache-2.0) AND LicenseRef-Custom"))
+	assert.True(t, ValidLicenseExpression("GPL-2.0-or-later WITH Classpath-exception-2.0"))
+	assert.False(t, ValidLicenseExpression("MIT/Apache-2.0"))
+	assert.False(t, ValidLicenseExpression("Custom License"))
+	assert.False(t, ValidLicenseExpression(""))
+}
+
+func TestNormalizeLicenseExpression(t *testing.T) {
+	normalized, notes, ok := NormalizeLicenseExpression("(mit or apache-2.0) and licenseref-Custom")
+	assert.True(t, ok)
+	assert.Equal(t, "(MIT OR Apache-2.0) AND LicenseRef-Custom", normalized)
+	assert.Empty(t, notes)
+
+	normalized, notes, ok = NormalizeLicenseExpression("gpl-2.0+ with classpath-exception-2.0")
+	assert.True(t, ok)
+	assert.Equal(t, "GPL-2.0+ WITH Classpath-exception-2.0", normalized)
+	assert.Equal(t, []string{"GPL-2.0 is a deprecated SPDX license identifier."}, notes)
+
+	_, _, ok = NormalizeLicenseExpression("MIT WITH Custom-exception")
+	assert.False(t, ok)
+
+	comments := AppendLicenseNotes("Concluded from LICENSE.", notes[0], notes[0])
+	assert.Equal(t, "Concluded from LICENSE. GPL-2.0 is a deprecated SPDX license identifier.", comments)
+}
+
+func TestCanonicalLicenses(t *testing.T) {
+	canonical := CanonicalLicenses("(MIT OR GPL-2.0-or-later WITH Classpath-exception-2.0) AND LicenseRef-Custom")
+	require.Len(t, canonical, 2)
+	assert.Equal(t, "MIT", canonical[0].ID)
+	assert.Equal(t, "MIT License", canonical[0].Name)
+	assert.Contains(t, canonical[0].ExtractedText, "Permission is hereby granted")
+	assert.Equal(t, "GPL-2.0-or-later", canonical[1].ID)
+}
+
+func TestRenameLicenseRefs(t *testing.T) {
+	assert.True(t, SameLicenseText("Some license\ntext.", "Some license text.\n"))
+	assert.False(t, SameLicenseText("Some license text.", "Other license text."))
+
+	renamed := map[string]string{"LicenseRef-Copy": "LicenseRef-Custom"}
+	assert.Equal(t, "(MIT OR LicenseRef-Custom) AND LicenseRef-Custom", RenameLicenseRefs("(MIT OR LicenseRef-Copy) AND LicenseRef-Custom", renamed))
+	assert.Equal(t, "LicenseRef-Copyleft", RenameLicenseRefs("LicenseRef-Copyleft", renamed))
+}
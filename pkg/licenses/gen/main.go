@@ -0,0 +1,182 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Command gen refreshes the SPDX license list embedded in package licenses from
+// a release of https://github.com/spdx/license-list-data, either downloaded or
+// already checked out:
+//
+//	go run ./gen -version v3.23
+//	go run ./gen -source ../license-list-data
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const releaseURL = "https://github.com/spdx/license-list-data/archive/refs/tags/%s.tar.gz"
+
+// the files of license-list-data the embedded list is made of
+const (
+	licensesFile   = "json/licenses.json"
+	exceptionsFile = "json/exceptions.json"
+	textDir        = "text/"
+)
+
+func main() {
+	version := flag.String("version", "", "tag of the license-list-data release to download, e.g. v3.23")
+	source := flag.String("source", "", "directory of a license-list-data checkout, instead of downloading a release")
+	out := flag.String("out", "data", "directory to write the embedded license list to")
+	flag.Parse()
+
+	var files map[string][]byte
+	var err error
+	switch {
+	case *source != "":
+		files, err = readSource(*source)
+	case *version != "":
+		files, err = download(fmt.Sprintf(releaseURL, *version))
+	default:
+		err = fmt.Errorf("either -version or -source is required")
+	}
+	if err != nil {
+		log.Fatalf("Failed to read the license list: %v", err)
+	}
+
+	if err := write(*out, files); err != nil {
+		log.Fatalf("Failed to write the license list: %v", err)
+	}
+}
+
+// isListFile reports whether the file of license-list-data, relative to its root, is part of the embedded list
+func isListFile(name string) bool {
+	return name == licensesFile || name == exceptionsFile ||
+		strings.HasPrefix(name, textDir) && strings.HasSuffix(name, ".txt") && !strings.Contains(strings.TrimPrefix(name, textDir), "/")
+}
+
+// readSource reads the list from a license-list-data checkout
+func readSource(dir string) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	err := filepath.Walk(dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		name, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+		if name = filepath.ToSlash(name); !isListFile(name) {
+			return nil
+		}
+		files[name], err = os.ReadFile(file)
+		return err
+	})
+	return files, err
+}
+
+// download reads the list from the tarball of a license-list-data release
+func download(url string) (map[string][]byte, error) {
+	client := http.Client{Timeout: 5 * time.Minute}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading %s: %s", url, resp.Status)
+	}
+
+	gz, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	files := map[string][]byte{}
+	archive := tar.NewReader(gz)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// the files of the tarball are in a license-list-data-<version> directory
+		parts := strings.SplitN(header.Name, "/", 2)
+		if header.Typeflag != tar.TypeReg || len(parts) != 2 || !isListFile(parts[1]) {
+			continue
+		}
+		if files[parts[1]], err = io.ReadAll(archive); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// write writes licenses.json and exceptions.json as released, after checking
+// them, and the texts of the licenses and exceptions to texts.tar.gz
+func write(out string, files map[string][]byte) error {
+	for _, name := range []string{licensesFile, exceptionsFile} {
+		content, ok := files[name]
+		if !ok {
+			return fmt.Errorf("%s is missing", name)
+		}
+		var list struct {
+			Version string `json:"licenseListVersion"`
+		}
+		if err := json.Unmarshal(content, &list); err != nil {
+			return fmt.Errorf("parsing %s: %w", name, err)
+		}
+		if err := os.WriteFile(filepath.Join(out, path.Base(name)), content, 0o644); err != nil {
+			return err
+		}
+		log.Infof("Wrote %s of license list %s", path.Base(name), list.Version)
+	}
+
+	texts := []string{}
+	for name := range files {
+		if strings.HasPrefix(name, textDir) {
+			texts = append(texts, name)
+		}
+	}
+	sort.Strings(texts)
+
+	buffer := &bytes.Buffer{}
+	gz := gzip.NewWriter(buffer)
+	archive := tar.NewWriter(gz)
+	for _, name := range texts {
+		header := &tar.Header{
+			Name:     strings.TrimPrefix(name, textDir),
+			Mode:     0o644,
+			Size:     int64(len(files[name])),
+			Typeflag: tar.TypeReg,
+		}
+		if err := archive.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := archive.Write(files[name]); err != nil {
+			return err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+
+	log.Infof("Wrote the texts of %d licenses and exceptions", len(texts))
+	return os.WriteFile(filepath.Join(out, "texts.tar.gz"), buffer.Bytes(), 0o644)
+}
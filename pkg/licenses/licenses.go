@@ -1,436 +1,205 @@
 // SPDX-License-Identifier: Apache-2.0
 
+// Package licenses embeds the SPDX license list, its licenses, exceptions and
+// their texts, as released at https://github.com/spdx/license-list-data. Run
+// go generate to refresh it, after updating the version below
 package licenses
 
-var DB = map[string]string{
-	"0BSD":                                 "BSD Zero Clause License",
-	"AAL":                                  "Attribution Assurance License",
-	"Abstyles":                             "Abstyles License",
-	"Adobe-2006":                           "Adobe Systems Incorporated Source Code License Agreement",
-	"Adobe-Glyph":                          "Adobe Glyph List License",
-	"ADSL":                                 "Amazon Digital Services License",
-	"AFL-1.1":                              "Academic Free License v1.1",
-	"AFL-1.2":                              "Academic Free License v1.2",
-	"AFL-2.0":                              "Academic Free License v2.0",
-	"AFL-2.1":                              "Academic Free License v2.1",
-	"AFL-3.0":                              "Academic Free License v3.0",
-	"Afmparse":                             "Afmparse License",
-	"AGPL-1.0-only":                        "Affero General Public License v1.0 only",
-	"AGPL-1.0-or-later":                    "Affero General Public License v1.0 or later",
-	"AGPL-3.0-only":                        "GNU Affero General Public License v3.0 only",
-	"AGPL-3.0-or-later":                    "GNU Affero General Public License v3.0 or later",
-	"Aladdin":                              "Aladdin Free Public License",
-	"AMDPLPA":                              "AMD's plpa_map.c License",
-	"AML":                                  "Apple MIT License",
-	"AMPAS":                                "Academy of Motion Picture Arts and Sciences BSD",
-	"ANTLR-PD":                             "ANTLR Software Rights Notice",
-	"ANTLR-PD-fallback":                    "ANTLR Software Rights Notice with license fallback",
-	"Apache-1.0":                           "Apache License 1.0",
-	"Apache-1.1":                           "Apache License 1.1",
-	"Apache-2.0":                           "Apache License 2.0",
-	"APAFML":                               "Adobe Postscript AFM License",
-	"APL-1.0":                              "Adaptive Public License 1.0",
-	"APSL-1.0":                             "Apple Public Source License 1.0",
-	"APSL-1.1":                             "Apple Public Source License 1.1",
-	"APSL-1.2":                             "Apple Public Source License 1.2",
-	"APSL-2.0":                             "Apple Public Source License 2.0",
-	"Artistic-1.0":                         "Artistic License 1.0",
-	"Artistic-1.0-cl8":                     "Artistic License 1.0 w/clause 8",
-	"Artistic-1.0-Perl":                    "Artistic License 1.0 (Perl)",
-	"Artistic-2.0":                         "Artistic License 2.0",
-	"Bahyph":                               "Bahyph License",
-	"Barr":                                 "Barr License",
-	"Beerware":                             "Beerware License",
-	"BitTorrent-1.0":                       "BitTorrent Open Source License v1.0",
-	"BitTorrent-1.1":                       "BitTorrent Open Source License v1.1",
-	"blessing":                             "SQLite Blessing",
-	"BlueOak-1.0.0":                        "Blue Oak Model License 1.0.0",
-	"Borceux":                              "Borceux license",
-	"BSD-1-Clause":                         "BSD 1-Clause License",
-	"BSD-2-Clause":                         "BSD 2-Clause 'Simplified' License",
-	"BSD-2-Clause-Patent":                  "BSD-2-Clause Plus Patent License",
-	"BSD-2-Clause-Views":                   "BSD 2-Clause with views sentence",
-	"BSD-3-Clause":                         "BSD 3-Clause 'New' or 'Revised' License",
-	"BSD-3-Clause-Attribution":             "BSD with attribution",
-	"BSD-3-Clause-Clear":                   "BSD 3-Clause Clear License",
-	"BSD-3-Clause-LBNL":                    "Lawrence Berkeley National Labs BSD variant license",
-	"BSD-3-Clause-Modification":            "BSD 3-Clause Modification",
-	"BSD-3-Clause-No-Military-License":     "BSD 3-Clause No Military License",
-	"BSD-3-Clause-No-Nuclear-License":      "BSD 3-Clause No Nuclear License",
-	"BSD-3-Clause-No-Nuclear-License-2014": "BSD 3-Clause No Nuclear License 2014",
-	"BSD-3-Clause-No-Nuclear-Warranty":     "BSD 3-Clause No Nuclear Warranty",
-	"BSD-3-Clause-Open-MPI":                "BSD 3-Clause Open MPI variant",
-	"BSD-4-Clause":                         "BSD 4-Clause 'Original' or 'Old' License",
-	"BSD-4-Clause-Shortened":               "BSD 4 Clause Shortened",
-	"BSD-4-Clause-UC":                      "BSD-4-Clause (University of California-Specific)",
-	"BSD-Protection":                       "BSD Protection License",
-	"BSD-Source-Code":                      "BSD Source Code Attribution",
-	"BSL-1.0":                              "Boost Software License 1.0",
-	"BUSL-1.1":                             "Business Source License 1.1",
-	"bzip2-1.0.5":                          "bzip2 and libbzip2 License v1.0.5",
-	"bzip2-1.0.6":                          "bzip2 and libbzip2 License v1.0.6",
-	"C-UDA-1.0":                            "Computational Use of Data Agreement v1.0",
-	"CAL-1.0":                              "Cryptographic Autonomy License 1.0",
-	"CAL-1.0-Combined-Work-Exception":      "Cryptographic Autonomy License 1.0 (Combined Work Exception)",
-	"Caldera":                              "Caldera License",
-	"CATOSL-1.1":                           "Computer Associates Trusted Open Source License 1.1",
-	"CC-BY-1.0":                            "Creative Commons Attribution 1.0 Generic",
-	"CC-BY-2.0":                            "Creative Commons Attribution 2.0 Generic",
-	"CC-BY-2.5":                            "Creative Commons Attribution 2.5 Generic",
-	"CC-BY-3.0":                            "Creative Commons Attribution 3.0 Unported",
-	"CC-BY-3.0-AT":                         "Creative Commons Attribution 3.0 Austria",
-	"CC-BY-3.0-US":                         "Creative Commons Attribution 3.0 United States",
-	"CC-BY-4.0":                            "Creative Commons Attribution 4.0 International",
-	"CC-BY-NC-1.0":                         "Creative Commons Attribution Non Commercial 1.0 Generic",
-	"CC-BY-NC-2.0":                         "Creative Commons Attribution Non Commercial 2.0 Generic",
-	"CC-BY-NC-2.5":                         "Creative Commons Attribution Non Commercial 2.5 Generic",
-	"CC-BY-NC-3.0":                         "Creative Commons Attribution Non Commercial 3.0 Unported",
-	"CC-BY-NC-4.0":                         "Creative Commons Attribution Non Commercial 4.0 International",
-	"CC-BY-NC-ND-1.0":                      "Creative Commons Attribution Non Commercial No Derivatives 1.0 Generic",
-	"CC-BY-NC-ND-2.0":                      "Creative Commons Attribution Non Commercial No Derivatives 2.0 Generic",
-	"CC-BY-NC-ND-2.5":                      "Creative Commons Attribution Non Commercial No Derivatives 2.5 Generic",
-	"CC-BY-NC-ND-3.0":                      "Creative Commons Attribution Non Commercial No Derivatives 3.0 Unported",
-	"CC-BY-NC-ND-3.0-IGO":                  "Creative Commons Attribution Non Commercial No Derivatives 3.0 IGO",
-	"CC-BY-NC-ND-4.0":                      "Creative Commons Attribution Non Commercial No Derivatives 4.0 International",
-	"CC-BY-NC-SA-1.0":                      "Creative Commons Attribution Non Commercial Share Alike 1.0 Generic",
-	"CC-BY-NC-SA-2.0":                      "Creative Commons Attribution Non Commercial Share Alike 2.0 Generic",
-	"CC-BY-NC-SA-2.5":                      "Creative Commons Attribution Non Commercial Share Alike 2.5 Generic",
-	"CC-BY-NC-SA-3.0":                      "Creative Commons Attribution Non Commercial Share Alike 3.0 Unported",
-	"CC-BY-NC-SA-4.0":                      "Creative Commons Attribution Non Commercial Share Alike 4.0 International",
-	"CC-BY-ND-1.0":                         "Creative Commons Attribution No Derivatives 1.0 Generic",
-	"CC-BY-ND-2.0":                         "Creative Commons Attribution No Derivatives 2.0 Generic",
-	"CC-BY-ND-2.5":                         "Creative Commons Attribution No Derivatives 2.5 Generic",
-	"CC-BY-ND-3.0":                         "Creative Commons Attribution No Derivatives 3.0 Unported",
-	"CC-BY-ND-4.0":                         "Creative Commons Attribution No Derivatives 4.0 International",
-	"CC-BY-SA-1.0":                         "Creative Commons Attribution Share Alike 1.0 Generic",
-	"CC-BY-SA-2.0":                         "Creative Commons Attribution Share Alike 2.0 Generic",
-	"CC-BY-SA-2.0-UK":                      "Creative Commons Attribution Share Alike 2.0 England and Wales",
-	"CC-BY-SA-2.1-JP":                      "Creative Commons Attribution Share Alike 2.1 Japan",
-	"CC-BY-SA-2.5":                         "Creative Commons Attribution Share Alike 2.5 Generic",
-	"CC-BY-SA-3.0":                         "Creative Commons Attribution Share Alike 3.0 Unported",
-	"CC-BY-SA-3.0-AT":                      "Creative Commons Attribution Share Alike 3.0 Austria",
-	"CC-BY-SA-4.0":                         "Creative Commons Attribution Share Alike 4.0 International",
-	"CC-PDDC":                              "Creative Commons Public Domain Dedication and Certification",
-	"CC0-1.0":                              "Creative Commons Zero v1.0 Universal",
-	"CDDL-1.0":                             "Common Development and Distribution License 1.0",
-	"CDDL-1.1":                             "Common Development and Distribution License 1.1",
-	"CDL-1.0":                              "Common Documentation License 1.0",
-	"CDLA-Permissive-1.0":                  "Community Data License Agreement Permissive 1.0",
-	"CDLA-Sharing-1.0":                     "Community Data License Agreement Sharing 1.0",
-	"CECILL-1.0":                           "CeCILL Free Software License Agreement v1.0",
-	"CECILL-1.1":                           "CeCILL Free Software License Agreement v1.1",
-	"CECILL-2.0":                           "CeCILL Free Software License Agreement v2.0",
-	"CECILL-2.1":                           "CeCILL Free Software License Agreement v2.1",
-	"CECILL-B":                             "CeCILL-B Free Software License Agreement",
-	"CECILL-C":                             "CeCILL-C Free Software License Agreement",
-	"CERN-OHL-1.1":                         "CERN Open Hardware Licence v1.1",
-	"CERN-OHL-1.2":                         "CERN Open Hardware Licence v1.2",
-	"CERN-OHL-P-2.0":                       "CERN Open Hardware Licence Version 2 - Permissive",
-	"CERN-OHL-S-2.0":                       "CERN Open Hardware Licence Version 2 - Strongly Reciprocal",
-	"CERN-OHL-W-2.0":                       "CERN Open Hardware Licence Version 2 - Weakly Reciprocal",
-	"ClArtistic":                           "Clarified Artistic License",
-	"CNRI-Jython":                          "CNRI Jython License",
-	"CNRI-Python":                          "CNRI Python License",
-	"CNRI-Python-GPL-Compatible":           "CNRI Python Open Source GPL Compatible License Agreement",
-	"Condor-1.1":                           "Condor Public License v1.1",
-	"copyleft-next-0.3.0":                  "copyleft-next 0.3.0",
-	"copyleft-next-0.3.1":                  "copyleft-next 0.3.1",
-	"CPAL-1.0":                             "Common Public Attribution License 1.0",
-	"CPL-1.0":                              "Common Public License 1.0",
-	"CPOL-1.02":                            "Code Project Open License 1.02",
-	"Crossword":                            "Crossword License",
-	"CrystalStacker":                       "CrystalStacker License",
-	"CUA-OPL-1.0":                          "CUA Office Public License v1.0",
-	"Cube":                                 "Cube License",
-	"curl":                                 "curl License",
-	"D-FSL-1.0":                            "Deutsche Freie Software Lizenz",
-	"diffmark":                             "diffmark license",
-	"DOC":                                  "DOC License",
-	"Dotseqn":                              "Dotseqn License",
-	"DRL-1.0":                              "Detection Rule License 1.0",
-	"DSDP":                                 "DSDP License",
-	"dvipdfm":                              "dvipdfm License",
-	"ECL-1.0":                              "Educational Community License v1.0",
-	"ECL-2.0":                              "Educational Community License v2.0",
-	"EFL-1.0":                              "Eiffel Forum License v1.0",
-	"EFL-2.0":                              "Eiffel Forum License v2.0",
-	"eGenix":                               "eGenix.com Public License 1.1.0",
-	"Entessa":                              "Entessa Public License v1.0",
-	"EPICS":                                "EPICS Open License",
-	"EPL-1.0":                              "Eclipse Public License 1.0",
-	"EPL-2.0":                              "Eclipse Public License 2.0",
-	"ErlPL-1.1":                            "Erlang Public License v1.1",
-	"etalab-2.0":                           "Etalab Open License 2.0",
-	"EUDatagrid":                           "EU DataGrid Software License",
-	"EUPL-1.0":                             "European Union Public License 1.0",
-	"EUPL-1.1":                             "European Union Public License 1.1",
-	"EUPL-1.2":                             "European Union Public License 1.2",
-	"Eurosym":                              "Eurosym License",
-	"Fair":                                 "Fair License",
-	"Frameworx-1.0":                        "Frameworx Open License 1.0",
-	"FreeBSD-DOC":                          "FreeBSD Documentation License",
-	"FreeImage":                            "FreeImage Public License v1.0",
-	"FSFAP":                                "FSF All Permissive License",
-	"FSFUL":                                "FSF Unlimited License",
-	"FSFULLR":                              "FSF Unlimited License (with License Retention)",
-	"FTL":                                  "Freetype Project License",
-	"GD":                                   "GD License",
-	"GFDL-1.1-invariants-only":             "GNU Free Documentation License v1.1 only - invariants",
-	"GFDL-1.1-invariants-or-later":         "GNU Free Documentation License v1.1 or later - invariants",
-	"GFDL-1.1-no-invariants-only":          "GNU Free Documentation License v1.1 only - no invariants",
-	"GFDL-1.1-no-invariants-or-later":      "GNU Free Documentation License v1.1 or later - no invariants",
-	"GFDL-1.1-only":                        "GNU Free Documentation License v1.1 only",
-	"GFDL-1.1-or-later":                    "GNU Free Documentation License v1.1 or later",
-	"GFDL-1.2-invariants-only":             "GNU Free Documentation License v1.2 only - invariants",
-	"GFDL-1.2-invariants-or-later":         "GNU Free Documentation License v1.2 or later - invariants",
-	"GFDL-1.2-no-invariants-only":          "GNU Free Documentation License v1.2 only - no invariants",
-	"GFDL-1.2-no-invariants-or-later":      "GNU Free Documentation License v1.2 or later - no invariants",
-	"GFDL-1.2-only":                        "GNU Free Documentation License v1.2 only",
-	"GFDL-1.2-or-later":                    "GNU Free Documentation License v1.2 or later",
-	"GFDL-1.3-invariants-only":             "GNU Free Documentation License v1.3 only - invariants",
-	"GFDL-1.3-invariants-or-later":         "GNU Free Documentation License v1.3 or later - invariants",
-	"GFDL-1.3-no-invariants-only":          "GNU Free Documentation License v1.3 only - no invariants",
-	"GFDL-1.3-no-invariants-or-later":      "GNU Free Documentation License v1.3 or later - no invariants",
-	"GFDL-1.3-only":                        "GNU Free Documentation License v1.3 only",
-	"GFDL-1.3-or-later":                    "GNU Free Documentation License v1.3 or later",
-	"Giftware":                             "Giftware License",
-	"GL2PS":                                "GL2PS License",
-	"Glide":                                "3dfx Glide License",
-	"Glulxe":                               "Glulxe License",
-	"GLWTPL":                               "Good Luck With That Public License",
-	"gnuplot":                              "gnuplot License",
-	"GPL-1.0-only":                         "GNU General Public License v1.0 only",
-	"GPL-1.0-or-later":                     "GNU General Public License v1.0 or later",
-	"GPL-2.0-only":                         "GNU General Public License v2.0 only",
-	"GPL-2.0-or-later":                     "GNU General Public License v2.0 or later",
-	"GPL-3.0-only":                         "GNU General Public License v3.0 only",
-	"GPL-3.0-or-later":                     "GNU General Public License v3.0 or later",
-	"gSOAP-1.3b":                           "gSOAP Public License v1.3b",
-	"HaskellReport":                        "Haskell Language Report License",
-	"Hippocratic-2.1":                      "Hippocratic License 2.1",
-	"HPND":                                 "Historical Permission Notice and Disclaimer",
-	"HPND-sell-variant":                    "Historical Permission Notice and Disclaimer - sell variant",
-	"HTMLTIDY":                             "HTML Tidy License",
-	"IBM-pibs":                             "IBM PowerPC Initialization and Boot Software",
-	"ICU":                                  "ICU License",
-	"IJG":                                  "Independent JPEG Group License",
-	"ImageMagick":                          "ImageMagick License",
-	"iMatix":                               "iMatix Standard Function Library Agreement",
-	"Imlib2":                               "Imlib2 License",
-	"Info-ZIP":                             "Info-ZIP License",
-	"Intel":                                "Intel Open Source License",
-	"Intel-ACPI":                           "Intel ACPI Software License Agreement",
-	"Interbase-1.0":                        "Interbase Public License v1.0",
-	"IPA":                                  "IPA Font License",
-	"IPL-1.0":                              "IBM Public License v1.0",
-	"ISC":                                  "ISC License",
-	"JasPer-2.0":                           "JasPer License",
-	"JPNIC":                                "Japan Network Information Center License",
-	"JSON":                                 "JSON License",
-	"LAL-1.2":                              "Licence Art Libre 1.2",
-	"LAL-1.3":                              "Licence Art Libre 1.3",
-	"Latex2e":                              "Latex2e License",
-	"Leptonica":                            "Leptonica License",
-	"LGPL-2.0-only":                        "GNU Library General Public License v2 only",
-	"LGPL-2.0-or-later":                    "GNU Library General Public License v2 or later",
-	"LGPL-2.1-only":                        "GNU Lesser General Public License v2.1 only",
-	"LGPL-2.1-or-later":                    "GNU Lesser General Public License v2.1 or later",
-	"LGPL-3.0-only":                        "GNU Lesser General Public License v3.0 only",
-	"LGPL-3.0-or-later":                    "GNU Lesser General Public License v3.0 or later",
-	"LGPLLR":                               "Lesser General Public License For Linguistic Resources",
-	"Libpng":                               "libpng License",
-	"libpng-2.0":                           "PNG Reference Library version 2",
-	"libselinux-1.0":                       "libselinux public domain notice",
-	"libtiff":                              "libtiff License",
-	"LiLiQ-P-1.1":                          "Licence Libre du Québec – Permissive version 1.1",
-	"LiLiQ-R-1.1":                          "Licence Libre du Québec – Réciprocité version 1.1",
-	"LiLiQ-Rplus-1.1":                      "Licence Libre du Québec – Réciprocité forte version 1.1",
-	"Linux-OpenIB":                         "Linux Kernel Variant of OpenIB.org license",
-	"LPL-1.0":                              "Lucent Public License Version 1.0",
-	"LPL-1.02":                             "Lucent Public License v1.02",
-	"LPPL-1.0":                             "LaTeX Project Public License v1.0",
-	"LPPL-1.1":                             "LaTeX Project Public License v1.1",
-	"LPPL-1.2":                             "LaTeX Project Public License v1.2",
-	"LPPL-1.3a":                            "LaTeX Project Public License v1.3a",
-	"LPPL-1.3c":                            "LaTeX Project Public License v1.3c",
-	"MakeIndex":                            "MakeIndex License",
-	"MirOS":                                "The MirOS Licence",
-	"MIT":                                  "MIT License",
-	"MIT-0":                                "MIT No Attribution",
-	"MIT-advertising":                      "Enlightenment License (e16)",
-	"MIT-CMU":                              "CMU License",
-	"MIT-enna":                             "enna License",
-	"MIT-feh":                              "feh License",
-	"MIT-Modern-Variant":                   "MIT License Modern Variant",
-	"MIT-open-group":                       "MIT Open Group variant",
-	"MITNFA":                               "MIT +no-false-attribs license",
-	"Motosoto":                             "Motosoto License",
-	"mpich2":                               "mpich2 License",
-	"MPL-1.0":                              "Mozilla Public License 1.0",
-	"MPL-1.1":                              "Mozilla Public License 1.1",
-	"MPL-2.0":                              "Mozilla Public License 2.0",
-	"MPL-2.0-no-copyleft-exception":        "Mozilla Public License 2.0 (no copyleft exception)",
-	"MS-PL":                                "Microsoft Public License",
-	"MS-RL":                                "Microsoft Reciprocal License",
-	"MTLL":                                 "Matrix Template Library License",
-	"MulanPSL-1.0":                         "Mulan Permissive Software License, Version 1",
-	"MulanPSL-2.0":                         "Mulan Permissive Software License, Version 2",
-	"Multics":                              "Multics License",
-	"Mup":                                  "Mup License",
-	"NAIST-2003":                           "Nara Institute of Science and Technology License (2003)",
-	"NASA-1.3":                             "NASA Open Source Agreement 1.3",
-	"Naumen":                               "Naumen Public License",
-	"NBPL-1.0":                             "Net Boolean Public License v1",
-	"NCGL-UK-2.0":                          "Non-Commercial Government Licence",
-	"NCSA":                                 "University of Illinois/NCSA Open Source License",
-	"Net-SNMP":                             "Net-SNMP License",
-	"NetCDF":                               "NetCDF license",
-	"Newsletr":                             "Newsletr License",
-	"NGPL":                                 "Nethack General Public License",
-	"NIST-PD":                              "NIST Public Domain Notice",
-	"NIST-PD-fallback":                     "NIST Public Domain Notice with license fallback",
-	"NLOD-1.0":                             "Norwegian Licence for Open Government Data",
-	"NLPL":                                 "No Limit Public License",
-	"Nokia":                                "Nokia Open Source License",
-	"NOSL":                                 "Netizen Open Source License",
-	"Noweb":                                "Noweb License",
-	"NPL-1.0":                              "Netscape Public License v1.0",
-	"NPL-1.1":                              "Netscape Public License v1.1",
-	"NPOSL-3.0":                            "Non-Profit Open Software License 3.0",
-	"NRL":                                  "NRL License",
-	"NTP":                                  "NTP License",
-	"NTP-0":                                "NTP No Attribution",
-	"O-UDA-1.0":                            "Open Use of Data Agreement v1.0",
-	"OCCT-PL":                              "Open CASCADE Technology Public License",
-	"OCLC-2.0":                             "OCLC Research Public License 2.0",
-	"ODbL-1.0":                             "Open Data Commons Open Database License v1.0",
-	"ODC-By-1.0":                           "Open Data Commons Attribution License v1.0",
-	"OFL-1.0":                              "SIL Open Font License 1.0",
-	"OFL-1.0-no-RFN":                       "SIL Open Font License 1.0 with no Reserved Font Name",
-	"OFL-1.0-RFN":                          "SIL Open Font License 1.0 with Reserved Font Name",
-	"OFL-1.1":                              "SIL Open Font License 1.1",
-	"OFL-1.1-no-RFN":                       "SIL Open Font License 1.1 with no Reserved Font Name",
-	"OFL-1.1-RFN":                          "SIL Open Font License 1.1 with Reserved Font Name",
-	"OGC-1.0":                              "OGC Software License, Version 1.0",
-	"OGDL-Taiwan-1.0":                      "Taiwan Open Government Data License, version 1.0",
-	"OGL-Canada-2.0":                       "Open Government Licence - Canada",
-	"OGL-UK-1.0":                           "Open Government Licence v1.0",
-	"OGL-UK-2.0":                           "Open Government Licence v2.0",
-	"OGL-UK-3.0":                           "Open Government Licence v3.0",
-	"OGTSL":                                "Open Group Test Suite License",
-	"OLDAP-1.1":                            "Open LDAP Public License v1.1",
-	"OLDAP-1.2":                            "Open LDAP Public License v1.2",
-	"OLDAP-1.3":                            "Open LDAP Public License v1.3",
-	"OLDAP-1.4":                            "Open LDAP Public License v1.4",
-	"OLDAP-2.0":                            "Open LDAP Public License v2.0 (or possibly 2.0A and 2.0B)",
-	"OLDAP-2.0.1":                          "Open LDAP Public License v2.0.1",
-	"OLDAP-2.1":                            "Open LDAP Public License v2.1",
-	"OLDAP-2.2":                            "Open LDAP Public License v2.2",
-	"OLDAP-2.2.1":                          "Open LDAP Public License v2.2.1",
-	"OLDAP-2.2.2":                          "Open LDAP Public License 2.2.2",
-	"OLDAP-2.3":                            "Open LDAP Public License v2.3",
-	"OLDAP-2.4":                            "Open LDAP Public License v2.4",
-	"OLDAP-2.5":                            "Open LDAP Public License v2.5",
-	"OLDAP-2.6":                            "Open LDAP Public License v2.6",
-	"OLDAP-2.7":                            "Open LDAP Public License v2.7",
-	"OLDAP-2.8":                            "Open LDAP Public License v2.8",
-	"OML":                                  "Open Market License",
-	"OpenSSL":                              "OpenSSL License",
-	"OPL-1.0":                              "Open Public License v1.0",
-	"OSET-PL-2.1":                          "OSET Public License version 2.1",
-	"OSL-1.0":                              "Open Software License 1.0",
-	"OSL-1.1":                              "Open Software License 1.1",
-	"OSL-2.0":                              "Open Software License 2.0",
-	"OSL-2.1":                              "Open Software License 2.1",
-	"OSL-3.0":                              "Open Software License 3.0",
-	"Parity-6.0.0":                         "The Parity Public License 6.0.0",
-	"Parity-7.0.0":                         "The Parity Public License 7.0.0",
-	"PDDL-1.0":                             "Open Data Commons Public Domain Dedication & License 1.0",
-	"PHP-3.0":                              "PHP License v3.0",
-	"PHP-3.01":                             "PHP License v3.01",
-	"Plexus":                               "Plexus Classworlds License",
-	"PolyForm-Noncommercial-1.0.0":         "PolyForm Noncommercial License 1.0.0",
-	"PolyForm-Small-Business-1.0.0":        "PolyForm Small Business License 1.0.0",
-	"PostgreSQL":                           "PostgreSQL License",
-	"PSF-2.0":                              "Python Software Foundation License 2.0",
-	"psfrag":                               "psfrag License",
-	"psutils":                              "psutils License",
-	"Python-2.0":                           "Python License 2.0",
-	"Qhull":                                "Qhull License",
-	"QPL-1.0":                              "Q Public License 1.0",
-	"Rdisc":                                "Rdisc License",
-	"RHeCos-1.1":                           "Red Hat eCos Public License v1.1",
-	"RPL-1.1":                              "Reciprocal Public License 1.1",
-	"RPL-1.5":                              "Reciprocal Public License 1.5",
-	"RPSL-1.0":                             "RealNetworks Public Source License v1.0",
-	"RSA-MD":                               "RSA Message-Digest License",
-	"RSCPL":                                "Ricoh Source Code Public License",
-	//"Ruby":                                 "Ruby License",
-	"SAX-PD":               "Sax Public Domain Notice",
-	"Saxpath":              "Saxpath License",
-	"SCEA":                 "SCEA Shared Source License",
-	"Sendmail":             "Sendmail License",
-	"Sendmail-8.23":        "Sendmail License 8.23",
-	"SGI-B-1.0":            "SGI Free Software License B v1.0",
-	"SGI-B-1.1":            "SGI Free Software License B v1.1",
-	"SGI-B-2.0":            "SGI Free Software License B v2.0",
-	"SHL-0.5":              "Solderpad Hardware License v0.5",
-	"SHL-0.51":             "Solderpad Hardware License, Version 0.51",
-	"SimPL-2.0":            "Simple Public License 2.0",
-	"SISSL":                "Sun Industry Standards Source License v1.1",
-	"SISSL-1.2":            "Sun Industry Standards Source License v1.2",
-	"Sleepycat":            "Sleepycat License",
-	"SMLNJ":                "Standard ML of New Jersey License",
-	"SMPPL":                "Secure Messaging Protocol Public License",
-	"SNIA":                 "SNIA Public License 1.1",
-	"Spencer-86":           "Spencer License 86",
-	"Spencer-94":           "Spencer License 94",
-	"Spencer-99":           "Spencer License 99",
-	"SPL-1.0":              "Sun Public License v1.0",
-	"SSH-OpenSSH":          "SSH OpenSSH license",
-	"SSH-short":            "SSH short notice",
-	"SSPL-1.0":             "Server Side Public License, v 1",
-	"SugarCRM-1.1.3":       "SugarCRM Public License v1.1.3",
-	"SWL":                  "Scheme Widget Library (SWL) Software License Agreement",
-	"TAPR-OHL-1.0":         "TAPR Open Hardware License v1.0",
-	"TCL":                  "TCL/TK License",
-	"TCP-wrappers":         "TCP Wrappers License",
-	"TMate":                "TMate Open Source License",
-	"TORQUE-1.1":           "TORQUE v2.5+ Software License v1.1",
-	"TOSL":                 "Trusster Open Source License",
-	"TU-Berlin-1.0":        "Technische Universitaet Berlin License 1.0",
-	"TU-Berlin-2.0":        "Technische Universitaet Berlin License 2.0",
-	"UCL-1.0":              "Upstream Compatibility License v1.0",
-	"Unicode-DFS-2015":     "Unicode License Agreement - Data Files and Software (2015)",
-	"Unicode-DFS-2016":     "Unicode License Agreement - Data Files and Software (2016)",
-	"Unicode-TOU":          "Unicode Terms of Use",
-	"Unlicense":            "The Unlicense",
-	"UPL-1.0":              "Universal Permissive License v1.0",
-	"Vim":                  "Vim License",
-	"VOSTROM":              "VOSTROM Public License for Open Source",
-	"VSL-1.0":              "Vovida Software License v1.0",
-	"W3C":                  "W3C Software Notice and License (2002-12-31)",
-	"W3C-19980720":         "W3C Software Notice and License (1998-07-20)",
-	"W3C-20150513":         "W3C Software Notice and Document License (2015-05-13)",
-	"Watcom-1.0":           "Sybase Open Watcom Public License 1.0",
-	"Wsuipa":               "Wsuipa License",
-	"WTFPL":                "Do What The F*ck You Want To Public License",
-	"X11":                  "X11 License",
-	"Xerox":                "Xerox License",
-	"XFree86-1.1":          "XFree86 License 1.1",
-	"xinetd":               "xinetd License",
-	"Xnet":                 "X.Net License",
-	"xpp":                  "XPP License",
-	"XSkat":                "XSkat License",
-	"YPL-1.0":              "Yahoo! Public License v1.0",
-	"YPL-1.1":              "Yahoo! Public License v1.1",
-	"Zed":                  "Zed License",
-	"Zend-2.0":             "Zend License v2.0",
-	"Zimbra-1.3":           "Zimbra Public License v1.3",
-	"Zimbra-1.4":           "Zimbra Public License v1.4",
-	"Zlib":                 "zlib License",
-	"zlib-acknowledgement": "zlib/libpng License with Acknowledgement",
-	"ZPL-1.1":              "Zope Public License 1.1",
-	"ZPL-2.0":              "Zope Public License 2.0",
-	"ZPL-2.1":              "Zope Public License 2.1",
+import (
+	"archive/tar"
+	"compress/gzip"
+	"embed"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// The data committed is the 3.17 list, whose texts are the ones of the release
+// bundled by go-license-detector. Its deprecated flags match the deprecated_
+// texts, but its OSI flags weren't taken from the release files: run go generate
+// to replace them with the released ones
+//
+//go:generate go run ./gen -version v3.17
+
+//go:embed data/licenses.json data/exceptions.json data/texts.tar.gz
+var data embed.FS
+
+// the prefix of the text files of the deprecated identifiers
+const deprecatedPrefix = "deprecated_"
+
+// License is a license of the SPDX license list
+type License struct {
+	ID            string   `json:"licenseId"`
+	Name          string   `json:"name"`
+	Reference     string   `json:"reference"`
+	IsDeprecated  bool     `json:"isDeprecatedLicenseId"`
+	IsOsiApproved bool     `json:"isOsiApproved"`
+	SeeAlso       []string `json:"seeAlso"`
+}
+
+// Exception is a license exception of the SPDX license list, used with WITH
+type Exception struct {
+	ID           string   `json:"licenseExceptionId"`
+	Name         string   `json:"name"`
+	Reference    string   `json:"reference"`
+	IsDeprecated bool     `json:"isDeprecatedLicenseId"`
+	SeeAlso      []string `json:"seeAlso"`
+}
+
+type list struct {
+	version    string
+	licenses   map[string]License
+	exceptions map[string]Exception
+}
+
+var (
+	listOnce sync.Once
+	embedded list
+
+	textsOnce sync.Once
+	texts     map[string]string
+)
+
+// loadList decodes the embedded list on its first use
+func loadList() list {
+	listOnce.Do(func() {
+		var licenses struct {
+			Version  string    `json:"licenseListVersion"`
+			Licenses []License `json:"licenses"`
+		}
+		mustDecode("data/licenses.json", &licenses)
+		var exceptions struct {
+			Exceptions []Exception `json:"exceptions"`
+		}
+		mustDecode("data/exceptions.json", &exceptions)
+
+		// the identifiers are matched whatever their case
+		embedded = list{
+			version:    licenses.Version,
+			licenses:   make(map[string]License, len(licenses.Licenses)),
+			exceptions: make(map[string]Exception, len(exceptions.Exceptions)),
+		}
+		for _, license := range licenses.Licenses {
+			embedded.licenses[strings.ToLower(license.ID)] = license
+		}
+		for _, exception := range exceptions.Exceptions {
+			embedded.exceptions[strings.ToLower(exception.ID)] = exception
+		}
+	})
+	return embedded
+}
+
+// loadTexts extracts the embedded texts on their first use
+func loadTexts() map[string]string {
+	textsOnce.Do(func() {
+		texts = map[string]string{}
+		f, err := data.Open("data/texts.tar.gz")
+		if err != nil {
+			panic(err)
+		}
+		defer f.Close()
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			panic(err)
+		}
+		archive := tar.NewReader(gz)
+		for {
+			header, err := archive.Next()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				panic(err)
+			}
+			content, err := io.ReadAll(archive)
+			if err != nil {
+				panic(err)
+			}
+			id := strings.TrimPrefix(strings.TrimSuffix(header.Name, ".txt"), deprecatedPrefix)
+			texts[strings.ToLower(id)] = string(content)
+		}
+	})
+	return texts
+}
+
+// mustDecode decodes an embedded JSON file, which the tests make sure is valid
+func mustDecode(name string, v interface{}) {
+	content, err := data.ReadFile(name)
+	if err != nil {
+		panic(err)
+	}
+	if err := json.Unmarshal(content, v); err != nil {
+		panic(err)
+	}
+}
+
+// Version returns the version of the embedded license list
+func Version() string {
+	return loadList().version
+}
+
+// Get returns the license of the identifier, whatever its case
+func Get(id string) (License, bool) {
+	license, ok := loadList().licenses[strings.ToLower(id)]
+	return license, ok
+}
+
+// GetException returns the license exception of the identifier, whatever its case
+func GetException(id string) (Exception, bool) {
+	exception, ok := loadList().exceptions[strings.ToLower(id)]
+	return exception, ok
+}
+
+// Exists reports whether the identifier, as cased, is a license of the list
+func Exists(id string) bool {
+	license, ok := Get(id)
+	return ok && license.ID == id
+}
+
+// Canonical returns the identifier of the license or exception as cased by the
+// list, MIT for mit
+func Canonical(id string) (string, bool) {
+	if license, ok := Get(id); ok {
+		return license.ID, true
+	}
+	if exception, ok := GetException(id); ok {
+		return exception.ID, true
+	}
+	return id, false
+}
+
+// IsDeprecated reports whether the identifier of the license or exception is deprecated,
+// as GPL-2.0 which is either GPL-2.0-only or GPL-2.0-or-later
+func IsDeprecated(id string) bool {
+	if license, ok := Get(id); ok {
+		return license.IsDeprecated
+	}
+	exception, ok := GetException(id)
+	return ok && exception.IsDeprecated
+}
+
+// IsOsiApproved reports whether the license is approved by the Open Source Initiative
+func IsOsiApproved(id string) bool {
+	license, ok := Get(id)
+	return ok && license.IsOsiApproved
+}
+
+// Text returns the text of the license or exception
+func Text(id string) (string, bool) {
+	text, ok := loadTexts()[strings.ToLower(id)]
+	return text, ok
+}
+
+// List returns the licenses of the list, sorted by identifier
+func List() []License {
+	licenses := make([]License, 0, len(loadList().licenses))
+	for _, license := range loadList().licenses {
+		licenses = append(licenses, license)
+	}
+	sort.Slice(licenses, func(i, j int) bool {
+		return licenses[i].ID < licenses[j].ID
+	})
+	return licenses
 }
@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package licenses
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestList(t *testing.T) {
+	assert.NotEmpty(t, Version())
+	licenses := List()
+	require.NotEmpty(t, licenses)
+	for i := 1; i < len(licenses); i++ {
+		assert.Less(t, licenses[i-1].ID, licenses[i].ID)
+	}
+}
+
+func TestLookups(t *testing.T) {
+	id, ok := Canonical("mit")
+	assert.True(t, ok)
+	assert.Equal(t, "MIT", id)
+	id, ok = Canonical("classpath-exception-2.0")
+	assert.True(t, ok)
+	assert.Equal(t, "Classpath-exception-2.0", id)
+	_, ok = Canonical("Custom")
+	assert.False(t, ok)
+
+	assert.True(t, Exists("Apache-2.0"))
+	assert.False(t, Exists("apache-2.0"))
+
+	assert.True(t, IsDeprecated("GPL-2.0"))
+	assert.False(t, IsDeprecated("GPL-2.0-only"))
+	assert.True(t, IsOsiApproved("Apache-2.0"))
+	assert.False(t, IsOsiApproved("CC-BY-4.0"))
+
+	exception, ok := GetException("LLVM-exception")
+	assert.True(t, ok)
+	assert.NotEmpty(t, exception.Name)
+}
+
+func TestText(t *testing.T) {
+	text, ok := Text("MIT")
+	require.True(t, ok)
+	assert.Contains(t, text, "Permission is hereby granted")
+	text, ok = Text("GPL-2.0")
+	require.True(t, ok)
+	assert.Contains(t, text, "GNU GENERAL PUBLIC LICENSE")
+	_, ok = Text("Classpath-exception-2.0")
+	assert.True(t, ok)
+}
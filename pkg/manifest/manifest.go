@@ -0,0 +1,248 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package manifest reads the direct dependencies the manifests of the ecosystems declare with
+// their scopes, as the devDependencies of a package.json, and propagates the scopes to the
+// dependencies they lead to. It serves both the plugins of pkg/modules and sbomgen, whose
+// parsers don't record the scopes
+package manifest
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pelletier/go-toml/v2"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/javagradle"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/javamaven"
+)
+
+// Dependency is a direct dependency declared in a manifest, with its scope
+type Dependency struct {
+	Name  string
+	Scope models.Scope
+}
+
+// Precedence orders the scopes, a package reachable through dependencies of several scopes
+// having the first of them
+var Precedence = []models.Scope{
+	models.ScopeRuntime,
+	models.ScopeProvided,
+	models.ScopeOptional,
+	models.ScopeBuild,
+	models.ScopeTest,
+	models.ScopeDev,
+}
+
+// Read returns the direct dependencies the manifest of the ecosystem, a plugin slug, declares
+// in dir, in the order of precedence of their scopes. The POMs are made effective with the
+// global settings file, and the Gradle builds are read from their lockfiles or else run
+func Read(ecosystem, dir, globalSettingFile string) []Dependency {
+	switch ecosystem {
+	case "npm", "yarn":
+		return PackageJSON(filepath.Join(dir, "package.json"))
+	case "composer":
+		return Composer(filepath.Join(dir, "composer.json"))
+	case "cargo":
+		return Cargo(filepath.Join(dir, "Cargo.toml"))
+	case "pipenv":
+		return Pipfile(filepath.Join(dir, "Pipfile"))
+	case "poetry":
+		return Poetry(filepath.Join(dir, "pyproject.toml"))
+	case "Java-Maven":
+		scopes, err := javamaven.DependencyScopes(dir, globalSettingFile)
+		if err != nil {
+			log.Warnf("Unable to read the dependency scopes of `%s`: %v", dir, err)
+		}
+		return sortedDependencies(scopes)
+	case "Java-Gradle":
+		scopes, err := javagradle.DependencyScopes(dir)
+		if err != nil {
+			log.Warnf("Unable to read the dependency scopes of `%s`: %v", dir, err)
+		}
+		return sortedDependencies(scopes)
+	}
+	return nil
+}
+
+// Index returns the scope of the dependencies by name, a dependency declared several times
+// keeping the first scope it is declared with
+func Index(declared []Dependency) map[string]models.Scope {
+	index := map[string]models.Scope{}
+	for _, d := range declared {
+		if _, ok := index[d.Name]; !ok {
+			index[d.Name] = d.Scope
+		}
+	}
+	return index
+}
+
+// Propagate returns the scope of every package reachable from the direct dependencies, given
+// the names of the dependencies of each package. A package reachable through dependencies of
+// several scopes has the first of them in Precedence, so a package reachable only through dev
+// dependencies is a dev one, while one a runtime dependency leads to stays a runtime one
+func Propagate(direct map[string]models.Scope, graph map[string][]string) map[string]models.Scope {
+	scopes := map[string]models.Scope{}
+	for _, scope := range Precedence {
+		queue := []string{}
+		for name, s := range direct {
+			if s == scope {
+				queue = append(queue, name)
+			}
+		}
+		sort.Strings(queue)
+		for len(queue) > 0 {
+			name := queue[0]
+			queue = queue[1:]
+			if _, ok := scopes[name]; ok {
+				continue
+			}
+			scopes[name] = scope
+			queue = append(queue, graph[name]...)
+		}
+	}
+	return scopes
+}
+
+// readManifest reads the manifest at path into v, the manifests missing or unreadable declaring nothing
+func readManifest(path string, unmarshal func([]byte, interface{}) error, v interface{}) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	if err := unmarshal(data, v); err != nil {
+		log.Warnf("Unable to read the dependency scopes of `%s`: %v", path, err)
+		return false
+	}
+	return true
+}
+
+func appendDependencies(declared []Dependency, deps map[string]interface{}, scope models.Scope) []Dependency {
+	names := make([]string, 0, len(deps))
+	for name := range deps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		declared = append(declared, Dependency{Name: name, Scope: scope})
+	}
+	return declared
+}
+
+func sortedDependencies(scopes map[string]models.Scope) []Dependency {
+	declared := make([]Dependency, 0, len(scopes))
+	for name, scope := range scopes {
+		declared = append(declared, Dependency{Name: name, Scope: scope})
+	}
+	sort.Slice(declared, func(i, j int) bool { return declared[i].Name < declared[j].Name })
+	return declared
+}
+
+// PackageJSON reads the dependencies of a package.json, a package required through several
+// fields keeping the scope of the first of them
+func PackageJSON(path string) []Dependency {
+	var manifest struct {
+		Dependencies         map[string]interface{} `json:"dependencies"`
+		PeerDependencies     map[string]interface{} `json:"peerDependencies"`
+		OptionalDependencies map[string]interface{} `json:"optionalDependencies"`
+		DevDependencies      map[string]interface{} `json:"devDependencies"`
+	}
+	if !readManifest(path, json.Unmarshal, &manifest) {
+		return nil
+	}
+	declared := appendDependencies(nil, manifest.Dependencies, models.ScopeRuntime)
+	declared = appendDependencies(declared, manifest.PeerDependencies, models.ScopeProvided)
+	declared = appendDependencies(declared, manifest.OptionalDependencies, models.ScopeOptional)
+	return appendDependencies(declared, manifest.DevDependencies, models.ScopeDev)
+}
+
+// Composer reads the require-dev packages of a composer.json as dev dependencies
+func Composer(path string) []Dependency {
+	var manifest struct {
+		Require    map[string]interface{} `json:"require"`
+		RequireDev map[string]interface{} `json:"require-dev"`
+	}
+	if !readManifest(path, json.Unmarshal, &manifest) {
+		return nil
+	}
+	declared := appendDependencies(nil, manifest.Require, models.ScopeRuntime)
+	return appendDependencies(declared, manifest.RequireDev, models.ScopeDev)
+}
+
+// Cargo reads the dev, build and optional dependencies of a Cargo.toml
+func Cargo(path string) []Dependency {
+	var manifest struct {
+		Dependencies      map[string]interface{} `toml:"dependencies"`
+		DevDependencies   map[string]interface{} `toml:"dev-dependencies"`
+		BuildDependencies map[string]interface{} `toml:"build-dependencies"`
+	}
+	if !readManifest(path, toml.Unmarshal, &manifest) {
+		return nil
+	}
+	runtime, optional := map[string]interface{}{}, map[string]interface{}{}
+	for name, dep := range manifest.Dependencies {
+		if table, ok := dep.(map[string]interface{}); ok && table["optional"] == true {
+			optional[name] = dep
+			continue
+		}
+		runtime[name] = dep
+	}
+	declared := appendDependencies(nil, runtime, models.ScopeRuntime)
+	declared = appendDependencies(declared, optional, models.ScopeOptional)
+	declared = appendDependencies(declared, manifest.BuildDependencies, models.ScopeBuild)
+	return appendDependencies(declared, manifest.DevDependencies, models.ScopeDev)
+}
+
+// Pipfile reads the [dev-packages] of a Pipfile as dev dependencies
+func Pipfile(path string) []Dependency {
+	var manifest struct {
+		Packages    map[string]interface{} `toml:"packages"`
+		DevPackages map[string]interface{} `toml:"dev-packages"`
+	}
+	if !readManifest(path, toml.Unmarshal, &manifest) {
+		return nil
+	}
+	declared := appendDependencies(nil, manifest.Packages, models.ScopeRuntime)
+	return appendDependencies(declared, manifest.DevPackages, models.ScopeDev)
+}
+
+// the Poetry group of the dependencies of the package itself
+const poetryMainGroup = "main"
+
+// Poetry reads the dependency groups of a pyproject.toml, the test group ones being test
+// dependencies and the other groups dev dependencies
+func Poetry(path string) []Dependency {
+	var manifest struct {
+		Tool struct {
+			Poetry struct {
+				Dependencies    map[string]interface{} `toml:"dependencies"`
+				DevDependencies map[string]interface{} `toml:"dev-dependencies"`
+				Group           map[string]struct {
+					Dependencies map[string]interface{} `toml:"dependencies"`
+				} `toml:"group"`
+			} `toml:"poetry"`
+		} `toml:"tool"`
+	}
+	if !readManifest(path, toml.Unmarshal, &manifest) {
+		return nil
+	}
+	poetry := manifest.Tool.Poetry
+	declared := appendDependencies(nil, poetry.Dependencies, models.ScopeRuntime)
+	declared = appendDependencies(declared, poetry.Group[poetryMainGroup].Dependencies, models.ScopeRuntime)
+	declared = appendDependencies(declared, poetry.Group["test"].Dependencies, models.ScopeTest)
+	declared = appendDependencies(declared, poetry.DevDependencies, models.ScopeDev)
+	groups := make([]string, 0, len(poetry.Group))
+	for group := range poetry.Group {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	for _, group := range groups {
+		if group != poetryMainGroup && group != "test" {
+			declared = appendDependencies(declared, poetry.Group[group].Dependencies, models.ScopeDev)
+		}
+	}
+	return declared
+}
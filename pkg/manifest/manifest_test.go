@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func writeManifest(t *testing.T, path, contents string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0644))
+}
+
+func TestRead(t *testing.T) {
+	// the parent POMs aren't looked up in the local repository of the user
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	writeManifest(t, filepath.Join(dir, "pom.xml"), `<project>
+  <groupId>com.acme</groupId>
+  <artifactId>parent</artifactId>
+  <version>1.0.0</version>
+  <packaging>pom</packaging>
+  <properties><test.scope>test</test.scope></properties>
+  <dependencyManagement>
+    <dependencies>
+      <dependency><groupId>javax.servlet</groupId><artifactId>servlet-api</artifactId><version>2.5</version><scope>provided</scope></dependency>
+    </dependencies>
+  </dependencyManagement>
+  <dependencies>
+    <dependency><groupId>junit</groupId><artifactId>junit</artifactId><version>4.13.2</version><scope>${test.scope}</scope></dependency>
+  </dependencies>
+</project>`)
+	app := filepath.Join(dir, "app")
+	writeManifest(t, filepath.Join(app, "pom.xml"), `<project>
+  <parent><groupId>com.acme</groupId><artifactId>parent</artifactId><version>1.0.0</version></parent>
+  <artifactId>app</artifactId>
+  <dependencies>
+    <dependency><groupId>javax.servlet</groupId><artifactId>servlet-api</artifactId></dependency>
+    <dependency><groupId>com.acme</groupId><artifactId>extras</artifactId><version>1.0.0</version><optional>true</optional></dependency>
+  </dependencies>
+</project>`)
+	writeManifest(t, filepath.Join(dir, "gradle.lockfile"), `com.google.guava:guava:32.1.2-jre=compileClasspath,runtimeClasspath
+junit:junit:4.13.2=testCompileClasspath,testRuntimeClasspath
+org.projectlombok:lombok:1.18.30=compileClasspath
+empty=
+`)
+	writeManifest(t, filepath.Join(dir, "build.gradle"), "")
+	writeManifest(t, filepath.Join(dir, "Cargo.toml"), `[dependencies]
+serde = "1.0"
+tokio = { version = "1", optional = true }
+[dev-dependencies]
+criterion = "0.5"
+[build-dependencies]
+cc = "1.0"`)
+	writeManifest(t, filepath.Join(dir, "pyproject.toml"), `[tool.poetry.dependencies]
+requests = "^2.31"
+[tool.poetry.group.test.dependencies]
+pytest = "^7.4"
+[tool.poetry.group.lint.dependencies]
+black = "^23.0"
+pytest = "^7.4"`)
+
+	for _, test := range []struct {
+		ecosystem string
+		dir       string
+		expected  map[string]models.Scope
+	}{
+		// the scopes are inherited from the parent, its dependency management and its properties
+		{"Java-Maven", app, map[string]models.Scope{
+			"junit:junit": models.ScopeTest, "javax.servlet:servlet-api": models.ScopeProvided, "com.acme:extras": models.ScopeOptional,
+		}},
+		{"Java-Gradle", dir, map[string]models.Scope{
+			"com.google.guava:guava": models.ScopeRuntime, "junit:junit": models.ScopeTest, "org.projectlombok:lombok": models.ScopeProvided,
+		}},
+		{"cargo", dir, map[string]models.Scope{
+			"serde": models.ScopeRuntime, "tokio": models.ScopeOptional, "criterion": models.ScopeDev, "cc": models.ScopeBuild,
+		}},
+		{"poetry", dir, map[string]models.Scope{
+			"requests": models.ScopeRuntime, "pytest": models.ScopeTest, "black": models.ScopeDev,
+		}},
+	} {
+		assert.Equal(t, test.expected, Index(Read(test.ecosystem, test.dir, "")), test.ecosystem)
+	}
+}
+
+func TestPackageJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "package.json")
+	writeManifest(t, path, `{
+  "dependencies": {"express": "^4.18.2"},
+  "peerDependencies": {"react": "^18.0.0"},
+  "devDependencies": {"jest": "^29.7.0", "express": "^4.18.2"}
+}`)
+
+	assert.Equal(t, []Dependency{
+		{Name: "express", Scope: models.ScopeRuntime},
+		{Name: "react", Scope: models.ScopeProvided},
+		{Name: "express", Scope: models.ScopeDev},
+		{Name: "jest", Scope: models.ScopeDev},
+	}, PackageJSON(path))
+	assert.Nil(t, PackageJSON(filepath.Join(t.TempDir(), "package.json")))
+}
+
+func TestPropagate(t *testing.T) {
+	direct := map[string]models.Scope{
+		"react":    models.ScopeRuntime,
+		"jest":     models.ScopeDev,
+		"fsevents": models.ScopeOptional,
+	}
+	graph := map[string][]string{
+		"react":    {"loose-envify"},
+		"jest":     {"jest-cli", "loose-envify"},
+		"jest-cli": {"chalk"},
+	}
+
+	assert.Equal(t, map[string]models.Scope{
+		"react":        models.ScopeRuntime,
+		"loose-envify": models.ScopeRuntime,
+		"fsevents":     models.ScopeOptional,
+		"jest":         models.ScopeDev,
+		"jest-cli":     models.ScopeDev,
+		"chalk":        models.ScopeDev,
+	}, Propagate(direct, graph))
+}
@@ -0,0 +1,39 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+// RootDistances returns the distance of each node of a dependency graph from the closest root,
+// -1 when no root reaches it. dependencies returns the indexes of the dependencies of a node
+func RootDistances(roots []bool, dependencies func(int) []int) []int {
+	distances := make([]int, len(roots))
+	queue := []int{}
+	for i := range roots {
+		distances[i] = -1
+		if roots[i] {
+			distances[i] = 0
+			queue = append(queue, i)
+		}
+	}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, j := range dependencies(current) {
+			if distances[j] == -1 {
+				distances[j] = distances[current] + 1
+				queue = append(queue, j)
+			}
+		}
+	}
+	return distances
+}
+
+// WithinDepth returns whether each node of a dependency graph is kept when the graph is pruned to
+// depth levels from its roots, depth 1 keeping the direct dependencies only. The nodes no root
+// reaches are kept
+func WithinDepth(roots []bool, dependencies func(int) []int, depth int) []bool {
+	kept := make([]bool, len(roots))
+	for i, distance := range RootDistances(roots, dependencies) {
+		kept[i] = distance <= depth
+	}
+	return kept
+}
@@ -50,7 +50,85 @@ type Module struct {
 	Copyright               string
 	PackageComment          string
 	Root                    bool
-	Modules                 map[string]*Module
+	Deployable              bool
+	Relationship            RelationshipType
+	Scope                   Scope
+	// Annotations are the comments the package of the module is annotated with
+	Annotations []string
+	Modules     map[string]*Module
+}
+
+// RelationshipType is the SPDX relationship a module has with the modules
+// listed in its Modules, DEPENDS_ON unless set otherwise on the listed module
+type RelationshipType string
+
+const (
+	DependsOn            RelationshipType = "DEPENDS_ON"
+	Contains             RelationshipType = "CONTAINS"
+	BuildDependencyOf    RelationshipType = "BUILD_DEPENDENCY_OF"
+	DevDependencyOf      RelationshipType = "DEV_DEPENDENCY_OF"
+	TestDependencyOf     RelationshipType = "TEST_DEPENDENCY_OF"
+	OptionalDependencyOf RelationshipType = "OPTIONAL_DEPENDENCY_OF"
+	ProvidedDependencyOf RelationshipType = "PROVIDED_DEPENDENCY_OF"
+)
+
+// Scope is the kind of dependency a module listed in the Modules of another
+// one is, a runtime dependency unless set otherwise
+type Scope string
+
+const (
+	ScopeRuntime  Scope = ""
+	ScopeDev      Scope = "dev"
+	ScopeTest     Scope = "test"
+	ScopeOptional Scope = "optional"
+	ScopeBuild    Scope = "build"
+	// dependencies expected to be provided by the environment or the
+	// dependent package, such as maven provided or npm peer dependencies
+	ScopeProvided Scope = "provided"
+)
+
+// Scopes lists the scopes dependencies can be excluded by
+var Scopes = []Scope{ScopeDev, ScopeTest, ScopeOptional, ScopeBuild, ScopeProvided}
+
+var scopeRelationships = map[Scope]RelationshipType{
+	ScopeDev:      DevDependencyOf,
+	ScopeTest:     TestDependencyOf,
+	ScopeOptional: OptionalDependencyOf,
+	ScopeBuild:    BuildDependencyOf,
+	ScopeProvided: ProvidedDependencyOf,
+}
+
+// ParseScope returns the scope named s
+func ParseScope(s string) (Scope, error) {
+	for _, scope := range Scopes {
+		if string(scope) == strings.ToLower(strings.TrimSpace(s)) {
+			return scope, nil
+		}
+	}
+	return ScopeRuntime, fmt.Errorf("unknown dependency scope %q", s)
+}
+
+// Reversed reports whether the relationship goes from the listed module to
+// the module listing it, as with the *_DEPENDENCY_OF relationships
+func (r RelationshipType) Reversed() bool {
+	return strings.HasSuffix(string(r), "_OF")
+}
+
+// GetRelationship returns the relationship of a module referenced from the Modules of another one,
+// the one of its scope unless set otherwise
+func (m *Module) GetRelationship() RelationshipType {
+	if m.Relationship != "" {
+		return m.Relationship
+	}
+	return ScopeRelationship(m.Scope)
+}
+
+// ScopeRelationship returns the relationship of a dependency of the scope, DEPENDS_ON for the runtime ones
+func ScopeRelationship(scope Scope) RelationshipType {
+	if relationship, ok := scopeRelationships[scope]; ok {
+		return relationship
+	}
+	return DependsOn
 }
 
 // SupplierContact ...
@@ -155,4 +233,8 @@ type OutputFormat int
 const (
 	OutputFormatSpdx OutputFormat = iota
 	OutputFormatJson
+	OutputFormatCsv
+	OutputFormatTsv
+	OutputFormatYaml
+	OutputFormatRdf
 )
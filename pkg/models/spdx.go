@@ -10,6 +10,7 @@ type Package struct {
 	SPDXID                  string            `json:"SPDXID,omitempty"`
 	PackageVersion          string            `json:"versionInfo,omitempty"`
 	PackageSupplier         string            `json:"supplier,omitempty"`
+	PackageOriginator       string            `json:"originator,omitempty"`
 	PackageDownloadLocation string            `json:"downloadLocation,omitempty"`
 	FilesAnalyzed           bool              `json:"filesAnalyzed"`
 	PackageChecksums        []PackageChecksum `json:"checksums"`
@@ -19,7 +20,14 @@ type Package struct {
 	PackageCopyrightText    string            `json:"copyrightText,omitempty"`
 	PackageLicenseComments  string            `json:"licenseComments,omitempty"`
 	PackageComment          string            `json:"comment,omitempty"`
+	Annotations             []Annotation      `json:"annotations,omitempty"`
 	RootPackage             bool              `json:"-"`
+	// the files analyzed, set along with the verification code and the licenses found in them
+	PackageVerificationCode *PackageVerificationCode `json:"packageVerificationCode,omitempty"`
+	LicenseInfoFromFiles    []string                 `json:"licenseInfoFromFiles,omitempty"`
+	HasFiles                []string                 `json:"hasFiles,omitempty"`
+	// Files are listed under the package in tag-value documents
+	Files []File `json:"-"`
 }
 
 // Document
@@ -31,10 +39,22 @@ type Document struct {
 	SPDXID                  string                   `json:"SPDXID,omitempty"`
 	DocumentName            string                   `json:"name,omitempty"`
 	DocumentNamespace       string                   `json:"documentNamespace,omitempty"`
+	ExternalDocumentRefs    []ExternalDocumentRef    `json:"externalDocumentRefs,omitempty"`
+	DocumentComment         string                   `json:"comment,omitempty"`
 	CreationInfo            CreationInfo             `json:"creationInfo,omitempty"`
 	Packages                []Package                `json:"packages,omitempty"`
 	Relationships           []Relationship           `json:"relationships,omitempty"`
 	ExtractedLicensingInfos []ExtractedLicensingInfo `json:"hasExtractedLicensingInfos,omitempty"`
+	Annotations             []Annotation             `json:"annotations,omitempty"`
+	Files                   []File                   `json:"files,omitempty"`
+}
+
+// ExternalDocumentRef refers to another SPDX document by its namespace, its elements being
+// related to as DocumentRef-<id>:SPDXRef-<element>, ExternalDocumentID being DocumentRef-<id>
+type ExternalDocumentRef struct {
+	ExternalDocumentID string          `json:"externalDocumentId"`
+	SPDXDocument       string          `json:"spdxDocument"`
+	Checksum           PackageChecksum `json:"checksum"`
 }
 
 // CreationInfo
@@ -66,6 +86,38 @@ type ExtractedLicensingInfo struct {
 	LicenseComment string `json:"comment,omitempty"`
 }
 
+// File
+// JSON tags annotated from official example (https://github.com/spdx/spdx-spec/blob/v2.2.2/examples/SPDXJSONExample-v2.2.spdx.json)
+// and official schema (https://github.com/spdx/spdx-spec/blob/v2.2.2/schemas/spdx-schema.json
+type File struct {
+	FileName           string            `json:"fileName"`
+	SPDXID             string            `json:"SPDXID"`
+	FileTypes          []string          `json:"fileTypes,omitempty"`
+	Checksums          []PackageChecksum `json:"checksums"`
+	LicenseConcluded   string            `json:"licenseConcluded"`
+	LicenseInfoInFiles []string          `json:"licenseInfoInFiles"`
+	CopyrightText      string            `json:"copyrightText"`
+}
+
+// PackageVerificationCode
+// JSON tags annotated from official example (https://github.com/spdx/spdx-spec/blob/v2.2.2/examples/SPDXJSONExample-v2.2.spdx.json)
+// and official schema (https://github.com/spdx/spdx-spec/blob/v2.2.2/schemas/spdx-schema.json
+type PackageVerificationCode struct {
+	Value         string   `json:"packageVerificationCodeValue"`
+	ExcludedFiles []string `json:"packageVerificationCodeExcludedFiles,omitempty"`
+}
+
+// Annotation
+// JSON tags annotated from official example (https://github.com/spdx/spdx-spec/blob/v2.2.2/examples/SPDXJSONExample-v2.2.spdx.json)
+// and official schema (https://github.com/spdx/spdx-spec/blob/v2.2.2/schemas/spdx-schema.json
+type Annotation struct {
+	Annotator      string `json:"annotator,omitempty"`
+	AnnotationDate string `json:"annotationDate,omitempty"`
+	AnnotationType string `json:"annotationType,omitempty"`
+	SPDXREF        string `json:"-"`
+	Comment        string `json:"comment,omitempty"`
+}
+
 // PackageChecksum
 // JSON tags annotated from official example (https://github.com/spdx/spdx-spec/blob/v2.2.2/examples/SPDXJSONExample-v2.2.spdx.json)
 // and official schema (https://github.com/spdx/spdx-spec/blob/v2.2.2/schemas/spdx-schema.json
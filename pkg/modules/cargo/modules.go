@@ -63,6 +63,7 @@ func addDepthModules(modules []models.Module, cargoPackages []CargoPackage) erro
 				Copyright:        subModule.Copyright,
 				PackageComment:   subModule.PackageComment,
 				Root:             subModule.Root,
+				Scope:            getDependencyScope(cargoDep),
 			}
 
 		}
@@ -72,6 +73,21 @@ func addDepthModules(modules []models.Module, cargoPackages []CargoPackage) erro
 	return nil
 }
 
+// getDependencyScope returns the scope of a dependency from its kind, which is
+// null for normal dependencies, "dev" or "build"
+func getDependencyScope(dep CargoPackageDependency) models.Scope {
+	switch dep.Kind {
+	case "dev":
+		return models.ScopeDev
+	case "build":
+		return models.ScopeBuild
+	}
+	if dep.Optional {
+		return models.ScopeOptional
+	}
+	return models.ScopeRuntime
+}
+
 func convertMetadataToModulesList(cargoPackages []CargoPackage) ([]models.Module, error) {
 
 	var collection []models.Module
@@ -109,12 +125,10 @@ func convertCargoPackageToModule(dep CargoPackage) models.Module {
 		Modules:                 map[string]*models.Module{},
 	}
 
-	licensePkg, err := helper.GetLicenses(localPath)
+	detection, err := helper.DetectLicenses(localPath, helper.LicenseThreshold)
 	if err == nil {
-		module.LicenseDeclared = helper.BuildLicenseDeclared(licensePkg.ID)
-		module.LicenseConcluded = helper.BuildLicenseConcluded(licensePkg.ID)
-		module.Copyright = helper.GetCopyright(licensePkg.ExtractedText)
-		module.CommentsLicense = licensePkg.Comments
+		helper.SetModuleLicenses(&module, detection)
+		module.Copyright = helper.GetCopyright(detection.Text())
 	} else if dep.License != "" {
 		module.LicenseDeclared = dep.License
 		module.LicenseConcluded = dep.License
@@ -212,12 +226,10 @@ func convertCargoPackageToRootModule(dep CargoPackage) models.Module {
 		PackageDownloadLocation: dep.Repository,
 	}
 
-	licensePkg, err := helper.GetLicenses(localPath)
+	detection, err := helper.DetectLicenses(localPath, helper.LicenseThreshold)
 	if err == nil {
-		module.LicenseDeclared = helper.BuildLicenseDeclared(licensePkg.ID)
-		module.LicenseConcluded = helper.BuildLicenseConcluded(licensePkg.ID)
-		module.Copyright = helper.GetCopyright(licensePkg.ExtractedText)
-		module.CommentsLicense = licensePkg.Comments
+		helper.SetModuleLicenses(&module, detection)
+		module.Copyright = helper.GetCopyright(detection.Text())
 	}
 
 	return module
@@ -87,6 +87,7 @@ func (m *composer) ListUsedModules(path string) ([]models.Module, error) {
 		return nil, errFailedToShowComposerTree
 	}
 
+	addRootComponentsToModule(treeList, modules)
 	for _, treeComponent := range treeList.Installed {
 		addTreeComponentsToModule(treeComponent, modules)
 	}
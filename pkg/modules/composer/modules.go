@@ -70,14 +70,13 @@ func convertProjectInfoToModule(project ComposerProjectInfo, path string) (model
 		},
 		PackageDownloadLocation: packageDownloadLocation,
 		Supplier:                supplier,
+		Modules:                 map[string]*models.Module{},
 	}
 
-	licensePkg, err := helper.GetLicenses(path)
+	detection, err := helper.DetectLicenses(path, helper.LicenseThreshold)
 	if err == nil {
-		module.LicenseDeclared = helper.BuildLicenseDeclared(licensePkg.ID)
-		module.LicenseConcluded = helper.BuildLicenseConcluded(licensePkg.ID)
-		module.Copyright = helper.GetCopyright(licensePkg.ExtractedText)
-		module.CommentsLicense = licensePkg.Comments
+		helper.SetModuleLicenses(&module, detection)
+		module.Copyright = helper.GetCopyright(detection.Text())
 	}
 
 	return module, nil
@@ -198,6 +197,19 @@ func addSubModuleToAModule(modules []models.Module, moduleIndex int, subModule m
 		Copyright:        subModule.Copyright,
 		PackageComment:   subModule.PackageComment,
 		Root:             subModule.Root,
+		Scope:            subModule.Scope,
+	}
+}
+
+// addRootComponentsToModule lists the packages required by the project in the Modules of the root module
+func addRootComponentsToModule(treeList ComposerTreeList, modules []models.Module) {
+	for _, treeComponent := range treeList.Installed {
+		for idx, module := range modules {
+			if !module.Root && module.Name == getName(treeComponent.Name) {
+				addSubModuleToAModule(modules, 0, modules[idx])
+				break
+			}
+		}
 	}
 }
 
@@ -267,9 +279,11 @@ func (m *composer) getModulesFromComposerLockFile(path string) ([]models.Module,
 		}
 	}
 
+	// packages-dev lists the packages only required by require-dev
 	if len(info.PackagesDev) > 0 {
 		for _, pckg := range info.PackagesDev {
 			mod := convertLockPackageToModule(pckg)
+			mod.Scope = models.ScopeDev
 			modules = append(modules, mod)
 		}
 	}
@@ -294,12 +308,10 @@ func convertLockPackageToModule(dep ComposerLockPackage) models.Module {
 		Modules:   map[string]*models.Module{},
 	}
 	path := getLocalPath(dep)
-	licensePkg, err := helper.GetLicenses(path)
+	detection, err := helper.DetectLicenses(path, helper.LicenseThreshold)
 	if err == nil {
-		module.LicenseDeclared = helper.BuildLicenseDeclared(licensePkg.ID)
-		module.LicenseConcluded = helper.BuildLicenseConcluded(licensePkg.ID)
-		module.Copyright = helper.GetCopyright(licensePkg.ExtractedText)
-		module.CommentsLicense = licensePkg.Comments
+		helper.SetModuleLicenses(&module, detection)
+		module.Copyright = helper.GetCopyright(detection.Text())
 	} else if len(dep.License) > 0 {
 		licenseValue := dep.License[0]
 		module.LicenseDeclared = licenseValue
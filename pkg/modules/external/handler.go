@@ -0,0 +1,144 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package external
+
+import (
+	"github.com/opensbom-generator/parsers/meta"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/externalplugin"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+type pkg struct {
+	plugin   *externalplugin.Plugin
+	metadata models.PluginMetadata
+}
+
+// Discover returns the external plugins of the PATH, the sbomgen-plugin-* executables
+func Discover() []models.IPlugin {
+	plugins := []models.IPlugin{}
+	for _, p := range externalplugin.Discover() {
+		plugins = append(plugins, New(p))
+	}
+	return plugins
+}
+
+// New creates a plugin running the external plugin
+func New(p *externalplugin.Plugin) models.IPlugin {
+	metadata := p.GetMetadata()
+	return &pkg{
+		plugin: p,
+		metadata: models.PluginMetadata{
+			Name:       metadata.Name,
+			Slug:       metadata.Slug,
+			Manifest:   metadata.Manifest,
+			ModulePath: metadata.ModulePath,
+		},
+	}
+}
+
+// GetVersion returns the version the external plugin answers
+func (m *pkg) GetVersion() (string, error) {
+	return m.plugin.GetVersion()
+}
+
+// GetMetadata returns the metadata the external plugin answered when discovered
+func (m *pkg) GetMetadata() models.PluginMetadata {
+	return m.metadata
+}
+
+// SetRootModule does nothing, the path being sent with every request
+func (m *pkg) SetRootModule(path string) error {
+	return m.plugin.SetRootModule(path)
+}
+
+// IsValid asks the external plugin whether the project at path is one of its ecosystem
+func (m *pkg) IsValid(path string) bool {
+	return m.plugin.IsValid(path)
+}
+
+// HasModulesInstalled does nothing, the external plugin reporting the missing modules when listing them
+func (m *pkg) HasModulesInstalled(path string) error {
+	return m.plugin.HasModulesInstalled(path)
+}
+
+// GetRootModule returns the root package the external plugin answers
+func (m *pkg) GetRootModule(path string) (*models.Module, error) {
+	root, err := m.plugin.GetRootModule(path)
+	if err != nil {
+		return nil, err
+	}
+	return toModule(root), nil
+}
+
+// ListUsedModules lists the packages the external plugin answers
+func (m *pkg) ListUsedModules(path string) ([]models.Module, error) {
+	return m.ListModulesWithDeps(path, "")
+}
+
+// ListModulesWithDeps lists the packages the external plugin answers, with their dependencies
+func (m *pkg) ListModulesWithDeps(path string, globalSettingFile string) ([]models.Module, error) {
+	packages, err := m.plugin.ListModulesWithDeps(path, globalSettingFile)
+	if err != nil {
+		return nil, err
+	}
+
+	modules := make([]models.Module, 0, len(packages))
+	for i := range packages {
+		modules = append(modules, *toModule(&packages[i]))
+	}
+	return modules, nil
+}
+
+// toModule converts the package of the external plugin to the module of the legacy formats
+func toModule(p *meta.Package) *models.Module {
+	module := &models.Module{
+		Version:   p.Version,
+		Name:      p.Name,
+		Path:      p.Path,
+		LocalPath: p.LocalPath,
+		Supplier: models.SupplierContact{
+			Type:  models.TypeContact(p.Supplier.Type),
+			Name:  p.Supplier.Name,
+			Email: p.Supplier.Email,
+		},
+		PackageURL:              p.PackageURL,
+		PackageHomePage:         p.PackageHomePage,
+		PackageDownloadLocation: p.PackageDownloadLocation,
+		LicenseConcluded:        p.LicenseConcluded,
+		LicenseDeclared:         p.LicenseDeclared,
+		CommentsLicense:         p.CommentsLicense,
+		Copyright:               p.Copyright,
+		PackageComment:          p.PackageComment,
+		Root:                    p.Root,
+		Modules:                 map[string]*models.Module{},
+	}
+	// the modules the executable gives no checksum have none
+	if p.Checksum.Value != "" || len(p.Checksum.Content) > 0 {
+		module.CheckSum = &models.CheckSum{
+			Algorithm: models.HashAlgorithm(p.Checksum.Algorithm),
+			Content:   p.Checksum.Content,
+			Value:     p.Checksum.Value,
+		}
+		if module.CheckSum.Algorithm == "" {
+			module.CheckSum.Algorithm = models.HashAlgoSHA1
+		}
+	}
+	// the modules without a supplier have an empty one
+	if p.Supplier.Name == externalplugin.NoAssertion {
+		module.Supplier = models.SupplierContact{}
+	}
+	for _, license := range p.OtherLicense {
+		module.OtherLicense = append(module.OtherLicense, &models.License{
+			ID:            license.ID,
+			Name:          license.Name,
+			ExtractedText: license.ExtractedText,
+			Comments:      license.Comments,
+			File:          license.File,
+		})
+	}
+	for name, dep := range p.Packages {
+		module.Modules[name] = toModule(dep)
+	}
+	return module
+}
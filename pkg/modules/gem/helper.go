@@ -304,16 +304,10 @@ func setChildModule(name string, parent, child *models.Module, layer map[string]
 // Sets license info from generic helper
 func setLicenseInfo(path string, module *models.Module) {
 
-	licensePkg, err := helper.GetLicenses(path)
+	detection, err := helper.DetectLicenses(path, helper.LicenseThreshold)
 	if err == nil {
-		module.LicenseDeclared = helper.BuildLicenseDeclared(licensePkg.ID)
-		module.LicenseConcluded = helper.BuildLicenseConcluded(licensePkg.ID)
-		if !helper.LicenseSPDXExists(licensePkg.ID) {
-			licensePkg.ID = fmt.Sprintf("LicenseRef-%s", licensePkg.ID)
-			module.OtherLicense = append(module.OtherLicense, licensePkg)
-		}
-		module.Copyright = helper.GetCopyright(licensePkg.ExtractedText)
-		module.CommentsLicense = licensePkg.Comments
+		helper.SetModuleLicenses(module, detection)
+		module.Copyright = helper.GetCopyright(detection.Text())
 		module.LocalPath = path
 	}
 
@@ -5,8 +5,11 @@ package gem
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"net/http"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/cache"
 )
 
 type (
@@ -89,23 +92,33 @@ func NewService(name string) (*GemService, error) {
 func (service *GemService) GetGem() (GemMetaVM, error) {
 
 	var metadata GemMetaVM
-	service.response, service.err = http.DefaultClient.Do(service.request)
+	var body []byte
+	body, service.err = cache.Default.Fetch("rubygems", service.request.URL.String(), cache.MetadataTTL, service.fetch)
 
 	if service.err != nil {
 		log.Printf("Failed to get gem from rubygems.org : %v\n", service.err)
 		return GemMetaVM{}, service.err
 	}
-	defer func() {
-		service.err = service.response.Body.Close()
-		if service.err != nil {
-			log.Printf("Failed to get gem from rubygems.org : %v\n", service.err)
-		}
-	}()
 
-	service.err = json.NewDecoder(service.response.Body).Decode(&metadata)
+	service.err = json.Unmarshal(body, &metadata)
 	if service.err != nil {
 		log.Printf("Failed to get gem from rubygems.org : %v\n", service.err)
 		return GemMetaVM{}, service.err
 	}
 	return metadata, nil
 }
+
+// fetch requests the gem from rubygems.org
+func (service *GemService) fetch() ([]byte, error) {
+	var err error
+	service.response, err = http.DefaultClient.Do(service.request)
+	if err != nil {
+		return nil, err
+	}
+	defer service.response.Body.Close()
+
+	if service.response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("gem %s: %s", service.name, service.response.Status)
+	}
+	return ioutil.ReadAll(service.response.Body)
+}
@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gomod
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// Build selects the packages of a build and the platform and build tags they're
+// compiled for, so that only the modules compiled into the build are listed
+type Build struct {
+	// Packages are the main packages of the build, ./... if none
+	Packages []string
+	GOOS     string
+	GOARCH   string
+	Tags     []string
+}
+
+// IsSet reports whether any of the build settings is set
+func (b Build) IsSet() bool {
+	return len(b.Packages) > 0 || b.GOOS != "" || b.GOARCH != "" || len(b.Tags) > 0
+}
+
+// env returns the environment of the go commands building for the platform
+func (b Build) env() []string {
+	env := []string{}
+	if b.GOOS != "" {
+		env = append(env, "GOOS="+b.GOOS)
+	}
+	if b.GOARCH != "" {
+		env = append(env, "GOARCH="+b.GOARCH)
+	}
+	return env
+}
+
+// args returns the arguments of go list selecting the build tags and the packages
+func (b Build) args() []string {
+	args := []string{}
+	if len(b.Tags) > 0 {
+		args = append(args, "-tags="+strings.Join(b.Tags, ","))
+	}
+	if len(b.Packages) == 0 {
+		return append(args, "./...")
+	}
+	return append(args, b.Packages...)
+}
+
+// SetBuild selects the build whose compiled modules are listed instead of the modules of every package
+func (m *mod) SetBuild(build Build) {
+	m.build = build
+}
+
+// listBuildModules lists the modules compiled into the build, linked by the imports of their packages
+func (m *mod) listBuildModules(path string) ([]models.Module, error) {
+	mainModule, err := m.GetRootModule(path)
+	if err != nil {
+		return nil, err
+	}
+
+	platform, err := m.getBuildPlatform(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.buildCmd(BuildModulesCmd, path, m.build.args()...); err != nil {
+		return nil, err
+	}
+
+	buffer := new(bytes.Buffer)
+	if err := m.command.Execute(buffer); err != nil {
+		return nil, err
+	}
+	defer buffer.Reset()
+
+	modules := []models.Module{}
+	if err := NewDecoder(buffer).ConvertJSONReaderToBuildModules(mainModule.Path, &modules); err != nil {
+		return nil, err
+	}
+
+	description := fmt.Sprintf("Modules compiled into the build of %s for %s", strings.Join(m.build.args(), " "), platform)
+	for i := range modules {
+		if modules[i].Root {
+			modules[i].Annotations = append([]string{description}, modules[i].Annotations...)
+		}
+	}
+
+	return modules, nil
+}
+
+// getBuildPlatform returns the GOOS/GOARCH the build is compiled for
+func (m *mod) getBuildPlatform(path string) (string, error) {
+	if err := m.buildCmd(BuildEnvCmd, path); err != nil {
+		return "", err
+	}
+
+	output, err := m.command.Output()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join(strings.Fields(output), "/"), nil
+}
@@ -13,6 +13,9 @@ var (
 	RootModuleCmd  command = "go list -mod readonly -json -m"
 	ModulesCmd     command = "go list -deps -json ./..."
 	GraphModuleCmd command = "go mod graph"
+	// the packages of the build are appended to BuildModulesCmd
+	BuildModulesCmd command = "go list -deps -json"
+	BuildEnvCmd     command = "go env GOOS GOARCH"
 )
 
 // Parse ...
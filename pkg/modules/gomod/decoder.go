@@ -10,6 +10,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/go-git/go-git/v5"
@@ -61,23 +62,7 @@ func (d *Decoder) ConvertPlainReaderToModules(modules []models.Module) error {
 			continue
 		}
 
-		modules[moduleIndex[moduleName]].Modules[depName] = &models.Module{
-			Name:             depModule.Name,
-			Version:          depModule.Version,
-			Path:             depModule.Path,
-			LocalPath:        depModule.LocalPath,
-			Supplier:         depModule.Supplier,
-			PackageURL:       depModule.PackageURL,
-			CheckSum:         depModule.CheckSum,
-			PackageHomePage:  depModule.PackageHomePage,
-			LicenseConcluded: depModule.LicenseConcluded,
-			LicenseDeclared:  depModule.LicenseDeclared,
-			CommentsLicense:  depModule.CommentsLicense,
-			OtherLicense:     depModule.OtherLicense,
-			Copyright:        depModule.Copyright,
-			PackageComment:   depModule.PackageComment,
-			Root:             depModule.Root,
-		}
+		modules[moduleIndex[moduleName]].Modules[depName] = linkModule(depModule)
 	}
 
 	return nil
@@ -123,6 +108,74 @@ func (d *Decoder) ConvertJSONReaderToModules(path string, modules *[]models.Modu
 	return nil
 }
 
+// ConvertJSONReaderToBuildModules converts the modules of the packages listed by go list -deps,
+// linking them by the imports of their packages and annotating them with the packages they contribute
+func (d *Decoder) ConvertJSONReaderToBuildModules(path string, modules *[]models.Module) error {
+	decoder := json.NewDecoder(d.reader)
+	packages := []JSONOutput{}
+	// the path of the module of each package, by import path
+	packageModules := map[string]string{}
+	for {
+		var j JSONOutput
+		if err := decoder.Decode(&j); err != nil {
+			if err == io.EOF {
+				break
+			}
+
+			return err
+		}
+
+		// packages of the standard library don't belong to any module
+		if j.Module == nil {
+			continue
+		}
+
+		packages = append(packages, j)
+		packageModules[j.ImportPath] = j.Module.Path
+	}
+
+	moduleIndex := map[string]int{}
+	contributed := map[string][]string{}
+	for _, j := range packages {
+		contributed[j.Module.Path] = append(contributed[j.Module.Path], j.ImportPath)
+		if _, ok := moduleIndex[j.Module.Path]; ok {
+			continue
+		}
+
+		md, err := buildModule(j.Module)
+		if err != nil {
+			return err
+		}
+
+		if j.Module.Path == path {
+			md.Root = true
+			md.PackageDownloadLocation = buildRootDownloadURL(md.LocalPath)
+		}
+		moduleIndex[j.Module.Path] = len(*modules)
+		*modules = append(*modules, *md)
+	}
+
+	for _, j := range packages {
+		module := (*modules)[moduleIndex[j.Module.Path]]
+		for _, imported := range j.Imports {
+			depPath, ok := packageModules[imported]
+			if !ok || depPath == j.Module.Path {
+				continue
+			}
+
+			depModule := (*modules)[moduleIndex[depPath]]
+			module.Modules[depModule.Name] = linkModule(depModule)
+		}
+	}
+
+	for modulePath, i := range moduleIndex {
+		sort.Strings(contributed[modulePath])
+		(*modules)[i].Annotations = append((*modules)[i].Annotations, fmt.Sprintf("Packages compiled into the build: %s", strings.Join(contributed[modulePath], ", ")))
+	}
+
+	return nil
+}
+
 // ConvertJSONReaderToSingleModule ...
 func (d *Decoder) ConvertJSONReaderToSingleModule(module *models.Module) error {
 	err := json.NewDecoder(d.reader).Decode(module)
@@ -151,22 +204,36 @@ func buildModule(m *Module) (*models.Module, error) {
 			Name: helper.BuildModuleName(m.Path, m.Replace.Path, m.Replace.Dir),
 		},
 	}
-	licensePkg, err := helper.GetLicenses(localDir)
+	detection, err := helper.DetectLicenses(localDir, helper.LicenseThreshold)
 	if err == nil {
-		module.LicenseDeclared = helper.BuildLicenseDeclared(licensePkg.ID)
-		module.LicenseConcluded = helper.BuildLicenseConcluded(licensePkg.ID)
-		module.Copyright = helper.GetCopyright(licensePkg.ExtractedText)
-		module.CommentsLicense = licensePkg.Comments
-		if !helper.LicenseSPDXExists(licensePkg.ID) {
-			licensePkg.ID = fmt.Sprintf("LicenseRef-%s", licensePkg.ID)
-			licensePkg.ExtractedText = fmt.Sprintf("<text>%s</text>", licensePkg.ExtractedText)
-			module.OtherLicense = append(module.OtherLicense, licensePkg)
-		}
+		helper.SetModuleLicenses(&module, detection)
+		module.Copyright = helper.GetCopyright(detection.Text())
 	}
 	module.Modules = map[string]*models.Module{}
 	return &module, nil
 }
 
+// linkModule returns the copy of module listed in the Modules of the modules depending on it
+func linkModule(module models.Module) *models.Module {
+	return &models.Module{
+		Name:             module.Name,
+		Version:          module.Version,
+		Path:             module.Path,
+		LocalPath:        module.LocalPath,
+		Supplier:         module.Supplier,
+		PackageURL:       module.PackageURL,
+		CheckSum:         module.CheckSum,
+		PackageHomePage:  module.PackageHomePage,
+		LicenseConcluded: module.LicenseConcluded,
+		LicenseDeclared:  module.LicenseDeclared,
+		CommentsLicense:  module.CommentsLicense,
+		OtherLicense:     module.OtherLicense,
+		Copyright:        module.Copyright,
+		PackageComment:   module.PackageComment,
+		Root:             module.Root,
+	}
+}
+
 func readMod(token string) ([]string, error) {
 	mods := strings.Fields(strings.TrimSpace(token))
 	if len(mods) != 2 {
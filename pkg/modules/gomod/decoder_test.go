@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gomod
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestConvertJSONReaderToBuildModules(t *testing.T) {
+	file, err := os.Open(filepath.Join("testdata", "build", "deps.json"))
+	require.NoError(t, err)
+	defer file.Close()
+
+	modules := []models.Module{}
+	require.NoError(t, NewDecoder(file).ConvertJSONReaderToBuildModules("example.com/app", &modules))
+
+	// the standard library is left out, the modules being listed in the order of their packages
+	require.Len(t, modules, 3)
+	text, fork, app := modules[0], modules[1], modules[2]
+
+	assert.Equal(t, "golang.org/x/text", text.Name)
+	assert.Equal(t, "v0.3.8", text.Version)
+	assert.False(t, text.Root)
+	assert.Empty(t, text.Modules)
+
+	// the replaced module is named after its replacement
+	assert.Equal(t, "github.com/acme/fork", fork.Name)
+	assert.Equal(t, "v1.0.0", fork.Version)
+	assert.Equal(t, "example.com/fork", fork.PackageURL)
+
+	assert.Equal(t, "example.com/app", app.Name)
+	assert.True(t, app.Root)
+	assert.Equal(t, []string{"Packages compiled into the build: example.com/app, example.com/app/internal/util"}, app.Annotations)
+
+	// the modules are linked by the imports of their packages, the indirect dependency
+	// being a dependency of the module importing it only
+	assert.Equal(t, []string{"github.com/acme/fork"}, moduleNames(app.Modules))
+	assert.Equal(t, []string{"golang.org/x/text"}, moduleNames(fork.Modules))
+	assert.Equal(t, "v0.3.8", fork.Modules["golang.org/x/text"].Version)
+}
+
+func moduleNames(modules map[string]*models.Module) []string {
+	names := []string{}
+	for name := range modules {
+		names = append(names, name)
+	}
+	return names
+}
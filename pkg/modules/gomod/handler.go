@@ -16,7 +16,7 @@ func New() *mod {
 		metadata: models.PluginMetadata{
 			Name:     "Go Modules",
 			Slug:     "go-mod",
-			Manifest: []string{"go.mod"},
+			Manifest: []string{"go.mod", workspaceFile},
 		},
 	}
 }
@@ -28,6 +28,7 @@ func (m *mod) GetMetadata() models.PluginMetadata {
 
 // SetRootModule ...
 func (m *mod) SetRootModule(path string) error {
+	m.path = path
 	module, err := m.getModule(path)
 	if err != nil {
 		return err
@@ -56,6 +57,10 @@ func (m *mod) HasModulesInstalled(path string) error {
 
 // GetVersion...
 func (m *mod) GetVersion() (string, error) {
+	if !hasGoCommand() && isVendored(m.path) {
+		return vendorReaderVersion, nil
+	}
+
 	if err := m.buildCmd(VersionCmd, "."); err != nil {
 		return "", err
 	}
@@ -79,32 +84,43 @@ func (m *mod) GetRootModule(path string) (*models.Module, error) {
 
 // ListUsedModules...
 func (m *mod) ListUsedModules(path string) ([]models.Module, error) {
-	if err := m.buildCmd(ModulesCmd, path); err != nil {
+	mainModule, err := m.GetRootModule(path)
+	if err != nil {
 		return nil, err
 	}
 
-	buffer := new(bytes.Buffer)
-	if err := m.command.Execute(buffer); err != nil {
-		return nil, err
+	return m.listUsedModules(path, mainModule.Path)
+}
+
+// ListModulesWithDeps ...
+func (m *mod) ListModulesWithDeps(path string, globalSettingFile string) ([]models.Module, error) {
+	if m.build.IsSet() {
+		return m.listBuildModules(path)
 	}
-	defer buffer.Reset()
 
-	mainModule, err := m.GetRootModule(path)
-	if err != nil {
-		return nil, err
+	// the vendor directory only lists the modules, without their graph, it is read when go isn't installed
+	if !hasGoCommand() && isVendored(path) {
+		mainModule, err := m.GetRootModule(path)
+		if err != nil {
+			return nil, err
+		}
+		return listVendoredModules(path, mainModule)
 	}
 
-	modules := []models.Module{}
-	if err := NewDecoder(buffer).ConvertJSONReaderToModules(mainModule.Path, &modules); err != nil {
-		return nil, err
+	if isWorkspace(path) {
+		return m.listWorkspaceModules(path)
 	}
 
-	return modules, nil
+	mainModule, err := m.GetRootModule(path)
+	if err != nil {
+		return nil, err
+	}
+	return m.listModules(path, mainModule.Path)
 }
 
-// ListModulesWithDeps ...
-func (m *mod) ListModulesWithDeps(path string, globalSettingFile string) ([]models.Module, error) {
-	modules, err := m.ListUsedModules(path)
+// listModules lists the modules of the packages of the main module at path, linked by the module graph
+func (m *mod) listModules(path string, mainModulePath string) ([]models.Module, error) {
+	modules, err := m.listUsedModules(path, mainModulePath)
 	if err != nil {
 		return nil, err
 	}
@@ -126,7 +142,31 @@ func (m *mod) ListModulesWithDeps(path string, globalSettingFile string) ([]mode
 	return modules, nil
 }
 
+// listUsedModules lists the modules of the packages of the main module at path
+func (m *mod) listUsedModules(path string, mainModulePath string) ([]models.Module, error) {
+	if err := m.buildCmd(ModulesCmd, path); err != nil {
+		return nil, err
+	}
+
+	buffer := new(bytes.Buffer)
+	if err := m.command.Execute(buffer); err != nil {
+		return nil, err
+	}
+	defer buffer.Reset()
+
+	modules := []models.Module{}
+	if err := NewDecoder(buffer).ConvertJSONReaderToModules(mainModulePath, &modules); err != nil {
+		return nil, err
+	}
+
+	return modules, nil
+}
+
 func (m *mod) getModule(path string) (models.Module, error) {
+	if !hasGoCommand() && (isVendored(path) || isWorkspace(path)) {
+		return readModulePath(path)
+	}
+
 	if err := m.buildCmd(RootModuleCmd, path); err != nil {
 		return models.Module{}, err
 	}
@@ -149,8 +189,8 @@ func (m *mod) getModule(path string) (models.Module, error) {
 	return module, nil
 }
 
-func (m *mod) buildCmd(cmd command, path string) error {
-	cmdArgs := cmd.Parse()
+func (m *mod) buildCmd(cmd command, path string, args ...string) error {
+	cmdArgs := append(cmd.Parse(), args...)
 	if cmdArgs[0] != "go" {
 		return errNoGoCommand
 	}
@@ -159,6 +199,7 @@ func (m *mod) buildCmd(cmd command, path string) error {
 		Name:      cmdArgs[0],
 		Args:      cmdArgs[1:],
 		Directory: path,
+		Env:       m.build.env(),
 	})
 
 	m.command = command
@@ -11,13 +11,16 @@ type mod struct {
 	metadata   models.PluginMetadata
 	rootModule *models.Module
 	command    *helper.Cmd
+	build      Build
+	path       string
 }
 
 type JSONOutput struct {
-	Dir        string  `json: "Dir,omitempty"`
-	ImportPath string  `json:"ImportPath,omitempty"`
-	Name       string  `json:"Name,omitempty"`
-	Module     *Module `json:"Module,omitempty"`
+	Dir        string   `json:"Dir,omitempty"`
+	ImportPath string   `json:"ImportPath,omitempty"`
+	Name       string   `json:"Name,omitempty"`
+	Module     *Module  `json:"Module,omitempty"`
+	Imports    []string `json:"Imports,omitempty"`
 }
 
 type Module struct {
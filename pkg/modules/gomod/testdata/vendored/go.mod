@@ -0,0 +1,12 @@
+module example.com/vendored
+
+go 1.20
+
+require (
+	example.com/fork v1.0.0
+	github.com/acme/log v1.2.0
+)
+
+require golang.org/x/text v0.3.8 // indirect
+
+replace example.com/fork => github.com/acme/fork v1.0.1
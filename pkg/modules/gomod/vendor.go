@@ -0,0 +1,233 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gomod
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+const (
+	vendorModulesFile = "modules.txt"
+	// reported as the version of go when the vendor directory is read without the go toolchain
+	vendorReaderVersion = "built-in vendor reader"
+)
+
+// vendoredModule is a module listed by vendor/modules.txt
+type vendoredModule struct {
+	path    string
+	version string
+	replace modReplace
+	// replaceVersion is the version of the replacement, empty if replaced by a directory
+	replaceVersion string
+	// explicit modules are required by the go.mod of the main module
+	explicit bool
+	packages []string
+}
+
+// isVendored reports whether the module at path vendors its dependencies
+func isVendored(path string) bool {
+	return helper.Exists(filepath.Join(path, vendorFolder, vendorModulesFile))
+}
+
+// hasGoCommand reports whether the go toolchain is installed
+func hasGoCommand() bool {
+	_, err := exec.LookPath("go")
+	return err == nil
+}
+
+// readModulePath reads the main module from the go.mod at path, or the first
+// member of the workspace at path, for when the go toolchain isn't installed
+func readModulePath(path string) (models.Module, error) {
+	if !helper.Exists(filepath.Join(path, "go.mod")) && isWorkspace(path) {
+		members, err := readWorkspaceModules(path)
+		if err != nil {
+			return models.Module{}, err
+		}
+		if len(members) == 0 {
+			return models.Module{}, errNoMainModule
+		}
+		return models.Module{Path: members[0].Path, LocalPath: members[0].Dir}, nil
+	}
+
+	modulePath, err := readGoModPath(path)
+	if err != nil {
+		return models.Module{}, err
+	}
+
+	return models.Module{Path: modulePath, LocalPath: path}, nil
+}
+
+// readGoModPath reads the module path of the go.mod at path
+func readGoModPath(path string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(path, "go.mod"))
+	if err != nil {
+		return "", err
+	}
+
+	modulePath := modfile.ModulePath(data)
+	if modulePath == "" {
+		return "", errFailedToConvertModules
+	}
+
+	return modulePath, nil
+}
+
+// listVendoredModules lists the modules vendored at path from vendor/modules.txt, without
+// the go toolchain. The main module, or the first member of a vendored workspace, contains
+// every vendored module, whose licenses are detected from their vendored copy
+func listVendoredModules(path string, mainModule *models.Module) ([]models.Module, error) {
+	file, err := os.Open(filepath.Join(path, vendorFolder, vendorModulesFile))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	vendored, workspace, err := parseVendorModules(file)
+	if err != nil {
+		return nil, err
+	}
+
+	// the members of a vendored workspace are sibling roots
+	roots := []*Module{{Path: mainModule.Path, Dir: path}}
+	if workspace {
+		if roots, err = readWorkspaceModules(path); err != nil {
+			return nil, err
+		}
+	}
+
+	modules := []models.Module{}
+	for _, r := range roots {
+		root, err := buildModule(r)
+		if err != nil {
+			return nil, err
+		}
+		root.Root = true
+		root.PackageDownloadLocation = buildRootDownloadURL(r.Dir)
+		modules = append(modules, *root)
+	}
+	if len(modules) == 0 {
+		return nil, errNoMainModule
+	}
+
+	for _, v := range vendored {
+		// modules without vendored packages are only listed for the consistency of go.mod
+		if len(v.packages) == 0 {
+			continue
+		}
+
+		replace := v.replace
+		if replace.Dir != "" && !filepath.IsAbs(replace.Dir) {
+			replace.Dir = filepath.Join(path, replace.Dir)
+		}
+		md, err := buildModule(&Module{
+			Path:    v.path,
+			Version: v.version,
+			Dir:     filepath.Join(path, vendorFolder, filepath.FromSlash(v.path)),
+			Replace: replace,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if md.Version == "" {
+			md.Version = v.replaceVersion
+		}
+
+		if v.explicit {
+			md.Annotations = append(md.Annotations, "Required by the go.mod of the main module")
+		}
+		md.Annotations = append(md.Annotations, fmt.Sprintf("Vendored packages: %s", strings.Join(v.packages, ", ")))
+		modules = append(modules, *md)
+
+		contained := linkModule(*md)
+		contained.Relationship = models.Contains
+		modules[0].Modules[md.Name] = contained
+	}
+
+	return modules, nil
+}
+
+// parseVendorModules parses vendor/modules.txt, which lists every module as
+//
+//	# path version [=> replacement [version]]
+//	## explicit; go 1.20
+//	package
+//
+// followed by the packages vendored from it. It reports whether the file was
+// written by go work vendor, which starts it with "## workspace"
+func parseVendorModules(r io.Reader) ([]vendoredModule, bool, error) {
+	modules := []vendoredModule{}
+	workspace := false
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "## "):
+			if len(modules) == 0 {
+				workspace = workspace || line == "## workspace"
+				continue
+			}
+			for _, marker := range strings.Split(strings.TrimPrefix(line, "## "), ";") {
+				if strings.TrimSpace(marker) == "explicit" {
+					modules[len(modules)-1].explicit = true
+				}
+			}
+		case strings.HasPrefix(line, "# "):
+			module, err := parseVendorModuleLine(strings.TrimPrefix(line, "# "))
+			if err != nil {
+				return nil, false, err
+			}
+			modules = append(modules, module)
+		default:
+			if len(modules) == 0 {
+				return nil, false, fmt.Errorf("package %s listed before any module in %s", line, vendorModulesFile)
+			}
+			modules[len(modules)-1].packages = append(modules[len(modules)-1].packages, line)
+		}
+	}
+
+	return modules, workspace, scanner.Err()
+}
+
+// parseVendorModuleLine parses "path version [=> replacement [version]]", the version of the
+// module being omitted when all of its versions are replaced
+func parseVendorModuleLine(line string) (vendoredModule, error) {
+	module := vendoredModule{}
+	split := strings.SplitN(line, "=>", 2)
+	fields := strings.Fields(split[0])
+	if len(fields) == 0 || len(fields) > 2 {
+		return module, fmt.Errorf("invalid module line %q in %s", line, vendorModulesFile)
+	}
+	module.path = fields[0]
+	if len(fields) == 2 {
+		module.version = fields[1]
+	}
+	if len(split) == 1 {
+		return module, nil
+	}
+
+	replacement := strings.Fields(split[1])
+	switch len(replacement) {
+	case 1:
+		// a directory replacement
+		module.replace = modReplace{Path: replacement[0], Dir: replacement[0]}
+	case 2:
+		module.replace = modReplace{Path: replacement[0]}
+		module.replaceVersion = replacement[1]
+	default:
+		return module, fmt.Errorf("invalid module line %q in %s", line, vendorModulesFile)
+	}
+	return module, nil
+}
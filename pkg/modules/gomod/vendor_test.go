@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gomod
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestParseVendorModules(t *testing.T) {
+	file, err := os.Open(filepath.Join("testdata", "vendored", vendorFolder, vendorModulesFile))
+	require.NoError(t, err)
+	defer file.Close()
+
+	modules, workspace, err := parseVendorModules(file)
+	require.NoError(t, err)
+	assert.False(t, workspace)
+	assert.Equal(t, []vendoredModule{
+		{
+			path:           "example.com/fork",
+			version:        "v1.0.0",
+			replace:        modReplace{Path: "github.com/acme/fork"},
+			replaceVersion: "v1.0.1",
+			explicit:       true,
+			packages:       []string{"example.com/fork"},
+		},
+		{path: "github.com/acme/log", version: "v1.2.0", explicit: true, packages: []string{"github.com/acme/log"}},
+		{path: "golang.org/x/text", version: "v0.3.8", packages: []string{"golang.org/x/text/width"}},
+		{path: "golang.org/x/tools", version: "v0.1.0", explicit: true},
+	}, modules)
+
+	// go work vendor starts the file with the workspace marker
+	_, workspace, err = parseVendorModules(strings.NewReader("## workspace\n# example.com/lib v1.0.0\n## explicit\nexample.com/lib\n"))
+	require.NoError(t, err)
+	assert.True(t, workspace)
+
+	_, _, err = parseVendorModules(strings.NewReader("example.com/lib\n# example.com/lib v1.0.0\n"))
+	assert.Error(t, err)
+}
+
+func TestParseVendorModuleLine(t *testing.T) {
+	for _, test := range []struct {
+		line     string
+		expected vendoredModule
+	}{
+		{"github.com/acme/log v1.2.0", vendoredModule{path: "github.com/acme/log", version: "v1.2.0"}},
+		{"example.com/fork v1.0.0 => github.com/acme/fork v1.0.1", vendoredModule{
+			path: "example.com/fork", version: "v1.0.0", replace: modReplace{Path: "github.com/acme/fork"}, replaceVersion: "v1.0.1",
+		}},
+		// all the versions replaced by a directory
+		{"example.com/local => ../local", vendoredModule{path: "example.com/local", replace: modReplace{Path: "../local", Dir: "../local"}}},
+	} {
+		module, err := parseVendorModuleLine(test.line)
+		require.NoError(t, err, test.line)
+		assert.Equal(t, test.expected, module, test.line)
+	}
+
+	for _, line := range []string{"", "a b c", "example.com/fork v1.0.0 => a b c"} {
+		_, err := parseVendorModuleLine(line)
+		assert.Error(t, err, line)
+	}
+}
+
+func TestListVendoredModules(t *testing.T) {
+	path := filepath.Join("testdata", "vendored")
+	modules, err := listVendoredModules(path, &models.Module{Path: "example.com/vendored"})
+	require.NoError(t, err)
+
+	// the modules without vendored packages are left out
+	require.Len(t, modules, 4)
+	root := modules[0]
+	assert.True(t, root.Root)
+	assert.Equal(t, "example.com/vendored", root.Name)
+	assert.Len(t, root.Modules, 3)
+
+	fork := modules[1]
+	assert.Equal(t, "github.com/acme/fork", fork.Name)
+	assert.Equal(t, "v1.0.0", fork.Version)
+	assert.Contains(t, fork.Annotations, "Required by the go.mod of the main module")
+	assert.Equal(t, models.Contains, root.Modules[fork.Name].Relationship)
+
+	log := modules[2]
+	assert.Equal(t, "github.com/acme/log", log.Name)
+	assert.Equal(t, "MIT", log.LicenseDeclared)
+
+	text := modules[3]
+	assert.Equal(t, "golang.org/x/text", text.Name)
+	assert.NotContains(t, text.Annotations, "Required by the go.mod of the main module")
+	assert.Contains(t, text.Annotations, "Vendored packages: golang.org/x/text/width")
+}
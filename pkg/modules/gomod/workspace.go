@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gomod
+
+import (
+	"os"
+	"path/filepath"
+
+	"golang.org/x/mod/modfile"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+const workspaceFile = "go.work"
+
+// isWorkspace reports whether path is the root of a go.work workspace
+func isWorkspace(path string) bool {
+	return helper.Exists(filepath.Join(path, workspaceFile))
+}
+
+// readWorkspaceModules returns the path and directory of the members of the workspace at path
+func readWorkspaceModules(path string) ([]*Module, error) {
+	data, err := os.ReadFile(filepath.Join(path, workspaceFile))
+	if err != nil {
+		return nil, err
+	}
+
+	work, err := modfile.ParseWork(workspaceFile, data, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	members := []*Module{}
+	for _, use := range work.Use {
+		dir := filepath.FromSlash(use.Path)
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(path, dir)
+		}
+
+		modulePath, err := readGoModPath(dir)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, &Module{Path: modulePath, Dir: dir})
+	}
+
+	return members, nil
+}
+
+// listWorkspaceModules lists the modules of every member of the workspace at path,
+// the members being sibling roots of the same document
+func (m *mod) listWorkspaceModules(path string) ([]models.Module, error) {
+	members, err := readWorkspaceModules(path)
+	if err != nil {
+		return nil, err
+	}
+
+	modules := []models.Module{}
+	moduleIndex := map[string]int{}
+	for _, member := range members {
+		memberModules, err := m.listModules(member.Dir, member.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		// the members depending on each other are listed by all of them
+		for _, module := range memberModules {
+			key := module.Name + "@" + module.Version
+			i, ok := moduleIndex[key]
+			if !ok {
+				moduleIndex[key] = len(modules)
+				modules = append(modules, module)
+				continue
+			}
+
+			modules[i].Root = modules[i].Root || module.Root
+			if module.Root {
+				modules[i].PackageDownloadLocation = module.PackageDownloadLocation
+			}
+			for name, dep := range module.Modules {
+				modules[i].Modules[name] = dep
+			}
+		}
+	}
+
+	// the members are listed first in the order of go.work, and referenced as the roots they are
+	roots := map[string]bool{}
+	sorted := make([]models.Module, 0, len(modules))
+	for _, member := range members {
+		for _, module := range modules {
+			if module.Root && module.Name == member.Path && !roots[module.Name] {
+				roots[module.Name] = true
+				sorted = append(sorted, module)
+			}
+		}
+	}
+	for _, module := range modules {
+		if !module.Root || !roots[module.Name] {
+			sorted = append(sorted, module)
+		}
+	}
+	for _, module := range sorted {
+		for name, dep := range module.Modules {
+			if roots[dep.Name] && !dep.Root {
+				root := *dep
+				root.Root = true
+				module.Modules[name] = &root
+			}
+		}
+	}
+
+	return sorted, nil
+}
@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gomod
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadWorkspaceModules(t *testing.T) {
+	path := filepath.Join("testdata", "workspace")
+	require.True(t, isWorkspace(path))
+	assert.False(t, isWorkspace(filepath.Join("testdata", "vendored")))
+
+	members, err := readWorkspaceModules(path)
+	require.NoError(t, err)
+	assert.Equal(t, []*Module{
+		{Path: "example.com/app", Dir: filepath.Join(path, "app")},
+		{Path: "example.com/lib", Dir: filepath.Join(path, "lib")},
+	}, members)
+
+	// the first member is the main module without the go toolchain
+	module, err := readModulePath(path)
+	require.NoError(t, err)
+	assert.Equal(t, "example.com/app", module.Path)
+}
+
+func TestListWorkspaceModules(t *testing.T) {
+	if !hasGoCommand() {
+		t.Skip("listing the members of a workspace requires go")
+	}
+	// the workspace has no dependency to download, and -mod is readonly in workspace mode
+	t.Setenv("GOFLAGS", "")
+	t.Setenv("GOPROXY", "off")
+
+	path, err := filepath.Abs(filepath.Join("testdata", "workspace"))
+	require.NoError(t, err)
+	modules, err := New().listWorkspaceModules(path)
+	require.NoError(t, err)
+
+	// the members are the sibling roots, in the order of go.work, listed once
+	require.Len(t, modules, 2)
+	app, lib := modules[0], modules[1]
+	assert.Equal(t, "example.com/app", app.Name)
+	assert.True(t, app.Root)
+	assert.Equal(t, "example.com/lib", lib.Name)
+	assert.True(t, lib.Root)
+
+	// app depends on lib, referenced as the root it is
+	require.Contains(t, app.Modules, "example.com/lib")
+	assert.True(t, app.Modules["example.com/lib"].Root)
+	assert.Empty(t, lib.Modules)
+}
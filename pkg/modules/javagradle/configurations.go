@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javagradle
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+const (
+	runtimeClasspath     = "runtimeClasspath"
+	compileClasspath     = "compileClasspath"
+	testCompileClasspath = "testCompileClasspath"
+	testRuntimeClasspath = "testRuntimeClasspath"
+	// buildEnvironment isn't a configuration, it selects the dependencies
+	// of the build script listed by the buildEnvironment task
+	buildEnvironment = "buildEnvironment"
+)
+
+// only the dependencies packaged with the project are resolved by default
+var defaultConfigurations = []string{runtimeClasspath}
+
+// the scope of the dependencies of each configuration. compileClasspath only
+// adds the compileOnly dependencies on top of the runtime ones, which are
+// provided by the environment at runtime
+var configurationScopes = map[string]models.Scope{
+	runtimeClasspath:     models.ScopeRuntime,
+	compileClasspath:     models.ScopeProvided,
+	testCompileClasspath: models.ScopeTest,
+	testRuntimeClasspath: models.ScopeTest,
+	buildEnvironment:     models.ScopeBuild,
+}
+
+// a dependency found in several configurations keeps the scope of the first
+// one of them in this order
+var configurationOrder = []string{
+	runtimeClasspath,
+	compileClasspath,
+	testRuntimeClasspath,
+	testCompileClasspath,
+	buildEnvironment,
+}
+
+// DependencyScopes returns the scope of the dependencies of the root project at path, by
+// group:artifact, read from its lockfiles or else from the build. A dependency of several
+// configurations has the scope of the first of them in configurationOrder
+func DependencyScopes(path string) (map[string]models.Scope, error) {
+	var source dependencySource = gradleTasks(path)
+	if locks, err := readLockedBuild(path); err != nil {
+		return nil, err
+	} else if locks != nil {
+		source = locks
+	}
+
+	scopes := map[string]models.Scope{}
+	for _, configuration := range configurationOrder {
+		di, err := source.dependencies(":", configuration)
+		if err != nil {
+			// not every project declares every configuration
+			continue
+		}
+		for _, dep := range di.root {
+			parts := strings.SplitN(dep, ":", 3)
+			if len(parts) < 2 {
+				continue
+			}
+			key := parts[0] + ":" + parts[1]
+			if _, ok := scopes[key]; !ok {
+				scopes[key] = scopeFor(configuration)
+			}
+		}
+	}
+	return scopes, nil
+}
+
+func scopeFor(configuration string) models.Scope {
+	if scope, ok := configurationScopes[configuration]; ok {
+		return scope
+	}
+	return models.ScopeRuntime
+}
+
+// sortConfigurations orders the configurations by precedence, the ones
+// unknown to configurationOrder are kept in between the classpaths of main
+// and the test ones in the order they were given
+func sortConfigurations(configurations []string) []string {
+	rank := func(configuration string) int {
+		for i, known := range configurationOrder {
+			if known == configuration {
+				if i < 2 {
+					return i
+				}
+				return i + 1
+			}
+		}
+		return 2
+	}
+
+	sorted := make([]string, 0, len(configurations))
+	seen := map[string]bool{}
+	for _, configuration := range configurations {
+		if configuration == "" || seen[configuration] {
+			continue
+		}
+		seen[configuration] = true
+		sorted = append(sorted, configuration)
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return rank(sorted[i]) < rank(sorted[j])
+	})
+	return sorted
+}
@@ -11,36 +11,42 @@ import (
 	"log"
 	"net/http"
 	"net/url"
+	"os"
 	"path"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/cache"
 )
 
 type depInfo struct {
-	root  []string
-	all   []string
-	graph map[string][]string
+	root     []string
+	all      []string
+	graph    map[string][]string
+	projects []string
 }
 
-// collect all non-transitive dependencies from all configuration (compile, test, runtime, etc)
-// perhaps this should be limited to just runtimeClasspath, but there's no real way to know
-// what the final packager is going to package into the bom, what a dilemma
-func getDependencies(dir string) (depInfo, error) {
-	return dependencies(dir, ":dependencies")
+// collect the dependencies of a single configuration (runtimeClasspath, testRuntimeClasspath, etc)
+// of the project at projectPath, ":" being the root project
+func getDependencies(dir string, projectPath string, configuration string) (depInfo, error) {
+	if configuration == buildEnvironment {
+		return getBuildDependencies(dir, projectPath)
+	}
+	return dependencies(dir, taskPath(projectPath, "dependencies"), "--configuration", configuration)
 }
 
 // collect all non-transitive dependencies from the build classpath, this is basically the dependencies
-// used to build the project. It's not clear to me that it's necessary to include these, but gradle plugins
-// can end up doing whatever they want to the final artifact. If we're trying to generate an sbom
-// *before* build.
-// Leave them out for now, but include them if we think we need to.
-func getBuildDependencies(dir string) (depInfo, error) {
-	return dependencies(dir, ":buildEnvironment")
+// used to build the project. Gradle plugins can end up doing whatever they want to the final artifact,
+// so these are reported as BUILD_DEPENDENCY_OF the project when the buildEnvironment configuration is
+// selected.
+func getBuildDependencies(dir string, projectPath string) (depInfo, error) {
+	return dependencies(dir, taskPath(projectPath, "buildEnvironment"))
 }
 
-func dependencies(dir string, command string) (depInfo, error) {
-	out, err := newGradleExec(dir).run(command, "-q").CombinedOutput()
+func dependencies(dir string, args ...string) (depInfo, error) {
+	out, err := newGradleExec(dir).run(append(args, "-q")...).CombinedOutput()
 	if err != nil {
 		log.Println(string(out))
 		return depInfo{}, err
@@ -48,6 +54,37 @@ func dependencies(dir string, command string) (depInfo, error) {
 	return parseDependencyOutput(out)
 }
 
+// the path of a task of the project at projectPath
+func taskPath(projectPath string, task string) string {
+	return strings.TrimSuffix(projectPath, ":") + ":" + task
+}
+
+// normalizeDependency returns the coordinates of the dependency resolved for a line of a dependency
+// tree, or an empty string when the line isn't a resolved dependency (constraints, unresolved
+// configurations, failures)
+func normalizeDependency(dep string) string {
+	for _, marker := range []string{" (c)", " (n)", " FAILED"} {
+		if strings.HasSuffix(dep, marker) {
+			return ""
+		}
+	}
+	dep = strings.TrimSuffix(dep, " (*)")
+
+	// conflict resolution or constraints, "group:artifact:requested -> selected"
+	if i := strings.Index(dep, " -> "); i >= 0 {
+		parts := strings.Split(dep[:i], ":")
+		if len(parts) >= 2 {
+			dep = parts[0] + ":" + parts[1] + ":" + strings.TrimSpace(dep[i+len(" -> "):])
+		}
+	}
+	return dep
+}
+
+// dependencies on other projects of a multi-project build are printed as "project :path"
+func isProjectDependency(dep string) bool {
+	return strings.HasPrefix(dep, "project ")
+}
+
 // root dependencies, transitive dependency graph
 func parseDependencyOutput(out []byte) (depInfo, error) {
 	br := bytes.NewReader(out)
@@ -57,6 +94,7 @@ func parseDependencyOutput(out []byte) (depInfo, error) {
 	dp := regexp.MustCompile(`^(([|]|[ ])[ ]{4})*([+]|[\\])---`)
 
 	rootDeps := map[string]bool{}
+	projectDeps := map[string]bool{}
 	// map of deps and their children
 	deps := make(map[string][]string)
 
@@ -72,7 +110,7 @@ func parseDependencyOutput(out []byte) (depInfo, error) {
 			if len(split) != 2 {
 				return depInfo{}, fmt.Errorf("Parse error %v on : %q", len(split), line)
 			}
-			current := split[1]
+			current := normalizeDependency(split[1])
 
 			depth := (strings.Index(line, "---") - 1) / 4
 			if len(parents) > depth {
@@ -81,8 +119,22 @@ func parseDependencyOutput(out []byte) (depInfo, error) {
 				parents = append(parents, last)
 			}
 			parents = parents[:depth]
+			last = current
+			if current == "" {
+				continue
+			}
+			if isProjectDependency(current) {
+				// the dependencies of other projects are collected from their own tree
+				if len(parents) == 0 {
+					projectDeps[strings.TrimPrefix(current, "project ")] = true
+				}
+				continue
+			}
 			if len(parents) > 0 {
 				cp := parents[len(parents)-1]
+				if cp == "" || isProjectDependency(cp) {
+					continue
+				}
 				deps[cp] = append(deps[cp], current)
 			} else {
 				rootDeps[current] = true
@@ -92,7 +144,6 @@ func parseDependencyOutput(out []byte) (depInfo, error) {
 			if _, ok := deps[current]; !ok {
 				deps[current] = []string{}
 			}
-			last = current
 		}
 	}
 	rootDepsList := make([]string, len(rootDeps))
@@ -109,10 +160,16 @@ func parseDependencyOutput(out []byte) (depInfo, error) {
 		i++
 	}
 
+	projectDepsList := make([]string, 0, len(projectDeps))
+	for k := range projectDeps {
+		projectDepsList = append(projectDepsList, k)
+	}
+
 	ret := depInfo{
-		root:  rootDepsList,
-		all:   allDeps,
-		graph: deps,
+		root:     rootDepsList,
+		all:      allDeps,
+		graph:    deps,
+		projects: projectDepsList,
 	}
 
 	return ret, nil
@@ -145,36 +202,58 @@ gradle.allprojects {
 }
 `
 
+// plugins applied with the plugins {} block are resolved from the plugin portal by default
+const gradlePluginPortal = "https://plugins.gradle.org/m2/"
+
 // TODO: this doesn't differentiate between "plugin" repos and "buildscript" repos,
 func getBuildRepositories(dir string) ([]string, error) {
-	return repositories(dir, initBuildRepos)
+	repos, err := repositories(dir, initBuildRepos)
+	if err != nil {
+		return nil, err
+	}
+	for _, repo := range repos {
+		if strings.TrimSuffix(repo, "/") == strings.TrimSuffix(gradlePluginPortal, "/") {
+			return repos, nil
+		}
+	}
+	return append(repos, gradlePluginPortal), nil
 }
 
-// inject an initscript to print out all repositories
+// inject an initscript to print out all repositories of all projects
 func repositories(dir string, initContents string) ([]string, error) {
-	initFile, err := ioutil.TempFile("", "*-spdx-init.gradle")
+	initPath, err := writeInitScript(initContents)
 	if err != nil {
 		return nil, err
 	}
-	_, err = initFile.Write([]byte(initContents))
+	defer os.Remove(initPath)
+
+	// not qualifying the task runs it in every project of the build
+	out, err := newGradleExec(dir).run("spdxPrintRepos", "--init-script", initPath, "-q").CombinedOutput()
 	if err != nil {
-		return nil, err
+		log.Println(string(out))
 	}
-	initPath, err := filepath.Abs(initFile.Name())
+	return parseRepoOutput(out)
+}
+
+func writeInitScript(initContents string) (string, error) {
+	initFile, err := ioutil.TempFile("", "*-spdx-init.gradle")
 	if err != nil {
-		return nil, err
+		return "", err
 	}
-	out, err := newGradleExec(dir).run(":spdxPrintRepos", "--init-script", initPath, "-q").CombinedOutput()
+	defer initFile.Close()
+
+	_, err = initFile.Write([]byte(initContents))
 	if err != nil {
-		log.Println(string(out))
+		return "", err
 	}
-	return parseRepoOutput(out)
+	return filepath.Abs(initFile.Name())
 }
 
 // ensure these are in the order they are printed, order determines where
 // dependencies are resolved from
 func parseRepoOutput(out []byte) ([]string, error) {
 	result := []string{}
+	seen := map[string]bool{}
 	br := bytes.NewReader(out)
 	sc := bufio.NewScanner(br)
 
@@ -185,7 +264,10 @@ func parseRepoOutput(out []byte) ([]string, error) {
 			if len(split) != 2 {
 				return nil, fmt.Errorf("Parse error on : %q", line)
 			}
-			result = append(result, split[1])
+			if !seen[split[1]] {
+				seen[split[1]] = true
+				result = append(result, split[1])
+			}
 		}
 	}
 	return result, nil
@@ -260,26 +342,74 @@ func findDownloadLocations(repos []string, deps []string) (map[string]string, er
 }
 
 func getSHA1(depURL string) (string, error) {
-	sb := make([]byte, 0, 40)
+	b, err := cache.Default.Fetch("maven", depURL+".sha1", cache.ArtifactTTL, func() ([]byte, error) {
+		sb := make([]byte, 0, 40)
 
-	r, err := http.Get(depURL + ".sha1")
-	if err != nil {
-		return "", err
-	}
+		r, err := http.Get(depURL + ".sha1")
+		if err != nil {
+			return nil, err
+		}
 
-	defer r.Body.Close()
-	if b, err := io.ReadAll(io.LimitReader(r.Body, int64(cap(sb)))); err != nil {
+		defer r.Body.Close()
+		if r.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("Could not get the checksum of %q: %s", depURL, r.Status)
+		}
+		return io.ReadAll(io.LimitReader(r.Body, int64(cap(sb))))
+	})
+	if err != nil {
 		return "", err
-	} else {
-		return string(b), nil
 	}
+	return string(b), nil
 }
 
+// remoteExists tells whether the repository has the dependency, the answers being cached
+// as the status codes of the HEAD requests. Only the definite answers, found or not found,
+// are cached, the other statuses failing the lookup
 func remoteExists(depURL string) bool {
-	r, err := http.Head(depURL)
+	status, err := cache.Default.Fetch("maven", "HEAD "+depURL, cache.ArtifactTTL, func() ([]byte, error) {
+		r, err := http.Head(depURL)
+		if err != nil {
+			return nil, err
+		}
+		r.Body.Close()
+		if r.StatusCode != http.StatusOK && r.StatusCode != http.StatusNotFound {
+			return nil, fmt.Errorf("unexpected status %d for %s", r.StatusCode, depURL)
+		}
+		return []byte(strconv.Itoa(r.StatusCode)), nil
+	})
 	if err != nil {
 		log.Print(err)
 		return false
 	}
-	return r.StatusCode == 200
+	return string(status) == "200"
+}
+
+// depSet keeps the order in which dependencies were first found
+type depSet struct {
+	list []string
+	seen map[string]bool
+}
+
+func newDepSet() *depSet {
+	return &depSet{seen: map[string]bool{}}
+}
+
+func (s *depSet) add(deps ...string) {
+	for _, dep := range deps {
+		if !s.seen[dep] {
+			s.seen[dep] = true
+			s.list = append(s.list, dep)
+		}
+	}
+}
+
+// without returns the dependencies of the set which aren't in other
+func (s *depSet) without(other *depSet) []string {
+	result := []string{}
+	for _, dep := range s.list {
+		if !other.seen[dep] {
+			result = append(result, dep)
+		}
+	}
+	return result
 }
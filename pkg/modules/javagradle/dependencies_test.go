@@ -153,3 +153,69 @@ func TestFindDownloadLocations(t *testing.T) {
 		t.Fatalf("\n got: %v\nwant: %v", locs, want)
 	}
 }
+
+const multiProjectDependencies = `
+runtimeClasspath - Runtime classpath of source set 'main'.
++--- project :core
+|    \--- com.google.guava:guava:31.1-jre
++--- org.slf4j:slf4j-api:1.7.36 -> 2.0.7
++--- com.google.guava:guava:31.1-jre (*)
++--- org.slf4j:slf4j-bom:2.0.7 (c)
+\--- com.fasterxml.jackson.core:jackson-databind -> 2.15.2
+     \--- com.fasterxml.jackson.core:jackson-core:2.15.2
+`
+
+func TestParseDependencyOutput_MultiProject(t *testing.T) {
+	di, err := parseDependencyOutput([]byte(multiProjectDependencies))
+	if err != nil {
+		t.Fatal(err)
+	}
+	{
+		want := map[string][]string{
+			"org.slf4j:slf4j-api:2.0.7":       {},
+			"com.google.guava:guava:31.1-jre": {},
+			"com.fasterxml.jackson.core:jackson-databind:2.15.2": {
+				"com.fasterxml.jackson.core:jackson-core:2.15.2",
+			},
+			"com.fasterxml.jackson.core:jackson-core:2.15.2": {},
+		}
+		if reflect.DeepEqual(di.graph, want) == false {
+			t.Fatalf("\n got: %q\nwant: %q", di.graph, want)
+		}
+	}
+	{
+		want := []string{
+			"com.fasterxml.jackson.core:jackson-databind:2.15.2",
+			"com.google.guava:guava:31.1-jre",
+			"org.slf4j:slf4j-api:2.0.7",
+		}
+		sorted := di.root
+		sort.Strings(sorted)
+		if reflect.DeepEqual(sorted, want) == false {
+			t.Fatalf("\n got: %q\nwant: %q", sorted, want)
+		}
+	}
+	if want := []string{":core"}; reflect.DeepEqual(di.projects, want) == false {
+		t.Fatalf("\n got: %q\nwant: %q", di.projects, want)
+	}
+}
+
+func TestTaskPath(t *testing.T) {
+	for projectPath, want := range map[string]string{
+		":":          ":dependencies",
+		":core":      ":core:dependencies",
+		":libs:core": ":libs:core:dependencies",
+	} {
+		if out := taskPath(projectPath, "dependencies"); out != want {
+			t.Fatalf("\n got: %v\nwant: %v", out, want)
+		}
+	}
+}
+
+func TestSortConfigurations(t *testing.T) {
+	out := sortConfigurations([]string{buildEnvironment, testRuntimeClasspath, "integrationTestRuntimeClasspath", runtimeClasspath, runtimeClasspath})
+	want := []string{runtimeClasspath, "integrationTestRuntimeClasspath", testRuntimeClasspath, buildEnvironment}
+	if reflect.DeepEqual(out, want) == false {
+		t.Fatalf("\n got: %q\nwant: %q", out, want)
+	}
+}
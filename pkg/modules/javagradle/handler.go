@@ -13,9 +13,10 @@ import (
 )
 
 type gradle struct {
-	metadata models.PluginMetadata
-	ge       gradleExec
-	basepath string
+	metadata       models.PluginMetadata
+	ge             gradleExec
+	basepath       string
+	configurations []string
 }
 
 func New() *gradle {
@@ -26,9 +27,19 @@ func New() *gradle {
 			Manifest:   []string{"build.gradle", "settings.gradle"},
 			ModulePath: []string{"."},
 		},
+		configurations: defaultConfigurations,
 	}
 }
 
+// SetConfigurations selects the configurations whose dependencies are listed,
+// runtimeClasspath only by default
+func (m *gradle) SetConfigurations(configurations []string) {
+	if len(configurations) == 0 {
+		configurations = defaultConfigurations
+	}
+	m.configurations = configurations
+}
+
 func (m *gradle) GetMetadata() models.PluginMetadata {
 	return m.metadata
 }
@@ -49,6 +60,9 @@ func (m *gradle) IsValid(path string) bool {
 }
 
 func (m *gradle) GetVersion() (string, error) {
+	if isLocked(m.basepath) {
+		return lockFileReaderVersion, nil
+	}
 	cmd := m.ge.run("--version")
 	out, err := cmd.Output()
 	if err != nil {
@@ -70,10 +84,30 @@ func (m *gradle) ListUsedModules(path string) ([]models.Module, error) {
 }
 
 func (m *gradle) ListModulesWithDeps(path string, globalSettingFile string) ([]models.Module, error) {
+	// reading the lockfiles is much faster than running the build
+	if locks, err := readLockedBuild(path); err != nil {
+		return nil, err
+	} else if locks != nil {
+		return listLockedModules(locks, sortConfigurations(m.configurations))
+	}
+
 	pi, err := getProjectInfo(path)
 	if err != nil {
 		return nil, err
 	}
+	subprojects, err := getSubprojects(path)
+	if err != nil {
+		return nil, err
+	}
+	all, err := getDependencyModules(generateRootModule(pi, path), subprojects, gradleTasks(path), sortConfigurations(m.configurations))
+	if err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// generate the module of the root project
+func generateRootModule(pi projectInfo, path string) models.Module {
 	rootModule := models.Module{
 		Name:    pi.name,
 		Version: pi.version,
@@ -98,67 +132,183 @@ func (m *gradle) ListModulesWithDeps(path string, globalSettingFile string) ([]m
 		}
 		rootModule.PackageDownloadLocation = origin
 	}
-	all, err := getDependencyModules(rootModule, path)
-	if err != nil {
-		return nil, err
-	}
-	return all, nil
+	return rootModule
 }
 
-func getDependencyModules(project models.Module, path string) ([]models.Module, error) {
+// dependencySource lists the dependencies of the projects of a build and the
+// repositories they are downloaded from
+type dependencySource interface {
+	dependencies(projectPath string, configuration string) (depInfo, error)
+	repositories() ([]string, error)
+	buildRepositories() ([]string, error)
+}
+
+// gradleTasks runs the build to list its dependencies and repositories
+type gradleTasks string
+
+func (dir gradleTasks) dependencies(projectPath string, configuration string) (depInfo, error) {
+	return getDependencies(string(dir), projectPath, configuration)
+}
+
+func (dir gradleTasks) repositories() ([]string, error) {
+	return getRepositories(string(dir))
+}
+
+func (dir gradleTasks) buildRepositories() ([]string, error) {
+	return getBuildRepositories(string(dir))
+}
+
+// projectDependencies are the dependencies of one configuration of a project
+type projectDependencies struct {
+	projectPath   string
+	configuration string
+	deps          depInfo
+}
+
+func getDependencyModules(project models.Module, subprojects []subproject, source dependencySource, configurations []string) ([]models.Module, error) {
 	modsMap := map[string]*models.Module{}
-	mods := []models.Module{project}
 
-	deps, err := getDependencies(path)
-	if err != nil {
-		return nil, err
-	}
-	repos, err := getRepositories(path)
-	if err != nil {
-		return nil, err
+	// the root project contains the subprojects included by settings.gradle
+	projectPaths := []string{":"}
+	projectMods := map[string]*models.Module{":": &project}
+	for _, sp := range subprojects {
+		mod := generateProjectModule(project, sp)
+		projectPaths = append(projectPaths, sp.path)
+		projectMods[sp.path] = &mod
+		contained := linkModule(mod, models.ScopeRuntime)
+		contained.Relationship = models.Contains
+		project.Modules[sp.path] = contained
 	}
-	depLoc, err := findDownloadLocations(repos, deps.all)
-	if err != nil {
-		return nil, err
+
+	resolved := []projectDependencies{}
+	deps := newDepSet()
+	buildDeps := newDepSet()
+	for _, projectPath := range projectPaths {
+		for _, configuration := range configurations {
+			di, err := source.dependencies(projectPath, configuration)
+			if err != nil {
+				// not every project declares every configuration, e.g. an aggregator without the java plugin
+				log.Printf("skipping configuration %s of project %s: %v", configuration, projectPath, err)
+				continue
+			}
+			resolved = append(resolved, projectDependencies{projectPath: projectPath, configuration: configuration, deps: di})
+			if configuration == buildEnvironment {
+				buildDeps.add(di.all...)
+			} else {
+				deps.add(di.all...)
+			}
+		}
 	}
 
-	for dep, remote := range depLoc {
-		mod, err := generateModule(dep, remote)
+	depLoc := map[string]string{}
+	if len(deps.list) > 0 {
+		repos, err := source.repositories()
 		if err != nil {
 			return nil, err
 		}
-		mods = append(mods, mod)
-		modsMap[dep] = &mod
+		if depLoc, err = findDownloadLocations(repos, deps.list); err != nil {
+			return nil, err
+		}
+	}
+	if buildOnly := buildDeps.without(deps); len(buildOnly) > 0 {
+		repos, err := source.buildRepositories()
+		if err != nil {
+			return nil, err
+		}
+		buildLoc, err := findDownloadLocations(repos, buildOnly)
+		if err != nil {
+			return nil, err
+		}
+		for dep, remote := range buildLoc {
+			depLoc[dep] = remote
+		}
 	}
 
-	// add all root dependencies to the project module
-	for _, rootDep := range deps.root {
-		if mod, ok := modsMap[rootDep]; !ok {
-			return nil, fmt.Errorf("Could not find module for %q", rootDep)
-		} else {
-			// apparently the key is just thrown away, so this just has to be something unique
-			project.Modules[rootDep] = mod
+	depMods := []*models.Module{}
+	for _, dep := range append(deps.list, buildDeps.without(deps)...) {
+		mod, err := generateModule(dep, depLoc[dep])
+		if err != nil {
+			return nil, err
 		}
+		depMods = append(depMods, &mod)
+		modsMap[dep] = &mod
 	}
 
-	// add transitive dependencies
-	for dep, tdeps := range deps.graph {
-		mod, ok := modsMap[dep]
-		if !ok {
-			return nil, fmt.Errorf("could not find module for %q", dep)
+	for _, r := range resolved {
+		projectMod := projectMods[r.projectPath]
+		scope := scopeFor(r.configuration)
+
+		// add all root dependencies to the project module, a dependency found in
+		// several configurations keeps the scope of the first one
+		for _, rootDep := range r.deps.root {
+			mod, ok := modsMap[rootDep]
+			if !ok {
+				return nil, fmt.Errorf("Could not find module for %q", rootDep)
+			}
+			if _, ok := projectMod.Modules[rootDep]; !ok {
+				// apparently the key is just thrown away, so this just has to be something unique
+				projectMod.Modules[rootDep] = linkModule(*mod, scope)
+			}
 		}
-		for _, tdep := range tdeps {
-			tmod, ok := modsMap[tdep]
+		for _, projectDep := range r.deps.projects {
+			mod, ok := projectMods[projectDep]
 			if !ok {
-				return nil, fmt.Errorf("could not find module for %q", tdep)
+				return nil, fmt.Errorf("could not find project %q", projectDep)
+			}
+			if _, ok := projectMod.Modules[projectDep]; !ok {
+				projectMod.Modules[projectDep] = linkModule(*mod, scope)
+			}
+		}
+
+		// add transitive dependencies
+		for dep, tdeps := range r.deps.graph {
+			mod, ok := modsMap[dep]
+			if !ok {
+				return nil, fmt.Errorf("could not find module for %q", dep)
+			}
+			for _, tdep := range tdeps {
+				tmod, ok := modsMap[tdep]
+				if !ok {
+					return nil, fmt.Errorf("could not find module for %q", tdep)
+				}
+				mod.Modules[tdep] = tmod
 			}
-			// apparently the key is just thrown away, so this just has to be something unique
-			mod.Modules[tdep] = tmod
 		}
 	}
+
+	mods := []models.Module{}
+	for _, projectPath := range projectPaths {
+		mods = append(mods, *projectMods[projectPath])
+	}
+	for _, mod := range depMods {
+		mods = append(mods, *mod)
+	}
 	return mods, nil
 }
 
+// linkModule returns the reference to a module to be listed in the Modules of another one
+func linkModule(module models.Module, scope models.Scope) *models.Module {
+	module.Modules = nil
+	module.Scope = scope
+	return &module
+}
+
+// generate the module of a subproject, which shares the sources of the root project
+func generateProjectModule(root models.Module, sp subproject) models.Module {
+	return models.Module{
+		Name:    sp.name,
+		Version: sp.version,
+		Supplier: models.SupplierContact{
+			Type: "Group Id",
+			Name: sp.group,
+		},
+		LocalPath:               sp.dir,
+		CheckSum:                root.CheckSum,
+		PackageDownloadLocation: root.PackageDownloadLocation,
+		Modules:                 make(map[string]*models.Module),
+	}
+}
+
 // generate gradle dependency module (non-root)
 func generateModule(name, depURL string) (models.Module, error) {
 	mod := models.Module{}
@@ -188,6 +338,11 @@ func generateModule(name, depURL string) (models.Module, error) {
 }
 
 func (m *gradle) HasModulesInstalled(path string) error {
+	// lockfiles are read without running gradle
+	if isLocked(path) {
+		return nil
+	}
+
 	// check if root has gradlew wrapper script
 	if hasGradlew(path) {
 		return nil
@@ -0,0 +1,392 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javagradle
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+const (
+	lockFile            = "gradle.lockfile"
+	buildscriptLockFile = "buildscript-gradle.lockfile"
+	versionCatalogFile  = "gradle/libs.versions.toml"
+	// the configuration of the build script classpath in buildscript-gradle.lockfile
+	buildscriptClasspath = "classpath"
+	// gradle's version of a project which doesn't set one
+	unspecifiedVersion = "unspecified"
+
+	// reported as the version of gradle when the lockfiles are read instead of running the build
+	lockFileReaderVersion = "built-in lockfile reader"
+
+	mavenCentral = "https://repo.maven.apache.org/maven2/"
+	googleMaven  = "https://dl.google.com/dl/android/maven2/"
+)
+
+var (
+	settingsFiles = []string{"settings.gradle", "settings.gradle.kts"}
+	buildFiles    = []string{"build.gradle", "build.gradle.kts"}
+
+	// include 'a', ':b:c' or include("a", "b"), possibly continued over several lines
+	includePattern         = regexp.MustCompile(`(?m)^\s*include\b\s*\(?((?:[^\n]*,[ \t]*\n)*[^\n]*)`)
+	quotedPattern          = regexp.MustCompile(`["']([^"']+)["']`)
+	rootProjectNamePattern = regexp.MustCompile(`(?m)^\s*rootProject\.name\s*=\s*["']([^"']+)["']`)
+	propertyPattern        = regexp.MustCompile(`(?m)^\s*(group|version)\s*=\s*["']([^"']+)["']`)
+	repositoryPattern      = regexp.MustCompile(`mavenCentral\(\)|google\(\)|gradlePluginPortal\(\)|maven\s*(?:\{[^}]*?url\s*=?|\(\s*(?:url\s*=)?)\s*(?:uri\(\s*)?["']([^"']+)["']`)
+)
+
+// lockedBuild is a build whose dependencies are locked by lockfiles, which are
+// read instead of running gradle
+type lockedBuild struct {
+	dir         string
+	root        projectInfo
+	subprojects []subproject
+	// locked coordinates by configuration, by project path
+	locks map[string]map[string][]string
+	// the libraries and the plugin markers of the version catalog
+	catalog versionCatalog
+	repos   []string
+}
+
+// readLockedBuild reads the lockfiles of the build at dir and its subprojects,
+// returns nil if none of them has any
+func readLockedBuild(dir string) (*lockedBuild, error) {
+	settings := readFirst(dir, settingsFiles)
+	b := &lockedBuild{
+		dir:   dir,
+		root:  readProjectInfo(dir, projectInfo{name: rootProjectName(dir, settings), version: unspecifiedVersion}),
+		locks: map[string]map[string][]string{},
+	}
+
+	buildContents := settings + readFirst(dir, buildFiles)
+	projectDirs := map[string]string{":": dir}
+	for _, projectPath := range parseSettingsIncludes(settings) {
+		projectDir := filepath.Join(dir, filepath.FromSlash(strings.ReplaceAll(strings.TrimPrefix(projectPath, ":"), ":", "/")))
+		name := projectPath[strings.LastIndex(projectPath, ":")+1:]
+		b.subprojects = append(b.subprojects, subproject{
+			path:        projectPath,
+			dir:         projectDir,
+			projectInfo: readProjectInfo(projectDir, projectInfo{name: name, group: b.root.group, version: b.root.version}),
+		})
+		projectDirs[projectPath] = projectDir
+		buildContents += readFirst(projectDir, buildFiles)
+	}
+
+	for projectPath, projectDir := range projectDirs {
+		locks, err := readProjectLocks(projectDir)
+		if err != nil {
+			return nil, err
+		}
+		if len(locks) > 0 {
+			b.locks[projectPath] = locks
+		}
+	}
+	if len(b.locks) == 0 {
+		return nil, nil
+	}
+
+	if helper.Exists(filepath.Join(dir, versionCatalogFile)) {
+		data, err := os.ReadFile(filepath.Join(dir, versionCatalogFile))
+		if err != nil {
+			return nil, err
+		}
+		if b.catalog, err = parseVersionCatalog(data); err != nil {
+			return nil, err
+		}
+	}
+
+	b.repos = parseRepositories(buildContents)
+	if len(b.repos) == 0 {
+		b.repos = []string{mavenCentral}
+	}
+	return b, nil
+}
+
+// isLocked reports whether the build at dir has lockfiles
+func isLocked(dir string) bool {
+	b, err := readLockedBuild(dir)
+	return err == nil && b != nil
+}
+
+// listLockedModules lists the modules of the configurations locked by the lockfiles of the build
+func listLockedModules(b *lockedBuild, configurations []string) ([]models.Module, error) {
+	return getDependencyModules(generateRootModule(b.root, b.dir), b.subprojects, b, configurations)
+}
+
+// lockfiles only list the locked coordinates, not the graph, so every one of
+// them is a dependency of the project. The version catalog is shared by the
+// whole build, so the configurations of the root project which aren't locked
+// fall back to the libraries or the plugins it declares
+func (b *lockedBuild) dependencies(projectPath string, configuration string) (depInfo, error) {
+	locked, ok := b.locks[projectPath][configuration]
+	if !ok && projectPath == ":" {
+		declared := b.catalog.libraries
+		if configuration == buildEnvironment {
+			declared = b.catalog.plugins
+		}
+		if len(declared) > 0 {
+			locked, ok = declared, true
+		}
+	}
+	if !ok {
+		return depInfo{}, fmt.Errorf("configuration %s is not locked", configuration)
+	}
+
+	di := depInfo{
+		root:  locked,
+		all:   locked,
+		graph: map[string][]string{},
+	}
+	for _, dep := range locked {
+		di.graph[dep] = []string{}
+	}
+	return di, nil
+}
+
+func (b *lockedBuild) repositories() ([]string, error) {
+	return b.repos, nil
+}
+
+func (b *lockedBuild) buildRepositories() ([]string, error) {
+	for _, repo := range b.repos {
+		if repo == gradlePluginPortal {
+			return b.repos, nil
+		}
+	}
+	return append(b.repos, gradlePluginPortal), nil
+}
+
+// readProjectLocks reads the coordinates locked for each configuration of the project at dir,
+// the ones of the build script classpath are listed as the buildEnvironment configuration
+func readProjectLocks(dir string) (map[string][]string, error) {
+	locks := map[string][]string{}
+	if data, err := os.ReadFile(filepath.Join(dir, lockFile)); err == nil {
+		locks = parseLockFile(data)
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	if data, err := os.ReadFile(filepath.Join(dir, buildscriptLockFile)); err == nil {
+		if classpath, ok := parseLockFile(data)[buildscriptClasspath]; ok {
+			locks[buildEnvironment] = classpath
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	return locks, nil
+}
+
+// parseLockFile returns the coordinates locked for each configuration,
+// lines are "group:artifact:version=configuration,configuration"
+func parseLockFile(data []byte) map[string][]string {
+	locks := map[string][]string{}
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		split := strings.SplitN(line, "=", 2)
+		if len(split) != 2 {
+			continue
+		}
+		for _, configuration := range strings.Split(split[1], ",") {
+			configuration = strings.TrimSpace(configuration)
+			if configuration == "" {
+				continue
+			}
+			// "empty" lists the locked configurations without dependencies
+			if split[0] == "empty" {
+				if _, ok := locks[configuration]; !ok {
+					locks[configuration] = []string{}
+				}
+				continue
+			}
+			locks[configuration] = append(locks[configuration], split[0])
+		}
+	}
+	return locks
+}
+
+// parseSettingsIncludes returns the path of the projects included by settings.gradle
+func parseSettingsIncludes(settings string) []string {
+	paths := []string{}
+	for _, include := range includePattern.FindAllStringSubmatch(settings, -1) {
+		for _, quoted := range quotedPattern.FindAllStringSubmatch(include[1], -1) {
+			paths = append(paths, ":"+strings.TrimPrefix(quoted[1], ":"))
+		}
+	}
+	return paths
+}
+
+func rootProjectName(dir, settings string) string {
+	if match := rootProjectNamePattern.FindStringSubmatch(settings); match != nil {
+		return match[1]
+	}
+	if abs, err := filepath.Abs(dir); err == nil {
+		return filepath.Base(abs)
+	}
+	return filepath.Base(dir)
+}
+
+// readProjectInfo reads the group and version set in the gradle.properties or
+// the build file of the project at dir, keeping the ones of pi otherwise
+func readProjectInfo(dir string, pi projectInfo) projectInfo {
+	set := func(key, value string) {
+		switch key {
+		case "group":
+			pi.group = value
+		case "version":
+			pi.version = value
+		}
+	}
+
+	for _, line := range strings.Split(readFirst(dir, []string{"gradle.properties"}), "\n") {
+		if split := strings.SplitN(line, "=", 2); len(split) == 2 {
+			set(strings.TrimSpace(split[0]), strings.TrimSpace(split[1]))
+		}
+	}
+	for _, match := range propertyPattern.FindAllStringSubmatch(readFirst(dir, buildFiles), -1) {
+		set(match[1], match[2])
+	}
+	return pi
+}
+
+// parseRepositories returns the repositories declared in build scripts in the order they're declared
+func parseRepositories(contents string) []string {
+	repos := []string{}
+	seen := map[string]bool{}
+	for _, match := range repositoryPattern.FindAllStringSubmatch(contents, -1) {
+		var repo string
+		switch {
+		case match[0] == "mavenCentral()":
+			repo = mavenCentral
+		case match[0] == "google()":
+			repo = googleMaven
+		case match[0] == "gradlePluginPortal()":
+			repo = gradlePluginPortal
+		default:
+			repo = match[1]
+		}
+		if !seen[repo] {
+			seen[repo] = true
+			repos = append(repos, repo)
+		}
+	}
+	return repos
+}
+
+// versionCatalog are the coordinates declared by gradle/libs.versions.toml
+type versionCatalog struct {
+	// "group:name:version" of the [libraries]
+	libraries []string
+	// "id:id.gradle.plugin:version" of the markers of the [plugins]
+	plugins []string
+}
+
+// parseVersionCatalog returns the coordinates of the libraries and of the plugin markers
+// declared in a version catalog, sorted by alias. The libraries without a version, which
+// a platform resolves, are left out
+func parseVersionCatalog(data []byte) (versionCatalog, error) {
+	catalog := struct {
+		Versions  map[string]interface{} `toml:"versions"`
+		Libraries map[string]interface{} `toml:"libraries"`
+		Plugins   map[string]interface{} `toml:"plugins"`
+	}{}
+	if err := toml.Unmarshal(data, &catalog); err != nil {
+		return versionCatalog{}, fmt.Errorf("failed to parse %s: %w", versionCatalogFile, err)
+	}
+
+	vc := versionCatalog{libraries: []string{}, plugins: []string{}}
+	for _, alias := range sortedAliases(catalog.Libraries) {
+		var module, version string
+		switch library := catalog.Libraries[alias].(type) {
+		case string:
+			// "group:name:version"
+			if split := strings.Split(library, ":"); len(split) == 3 {
+				module, version = split[0]+":"+split[1], split[2]
+			}
+		case map[string]interface{}:
+			module, _ = library["module"].(string)
+			if group, ok := library["group"].(string); ok {
+				if name, ok := library["name"].(string); ok {
+					module = group + ":" + name
+				}
+			}
+			version = catalogVersion(library["version"], catalog.Versions)
+		}
+		if strings.Count(module, ":") != 1 || version == "" {
+			continue
+		}
+		vc.libraries = append(vc.libraries, module+":"+version)
+	}
+
+	for _, alias := range sortedAliases(catalog.Plugins) {
+		var id, version string
+		switch plugin := catalog.Plugins[alias].(type) {
+		case string:
+			// "id:version"
+			split := strings.SplitN(plugin, ":", 2)
+			if len(split) == 2 {
+				id, version = split[0], split[1]
+			}
+		case map[string]interface{}:
+			id, _ = plugin["id"].(string)
+			version = catalogVersion(plugin["version"], catalog.Versions)
+		}
+		if id == "" || version == "" {
+			continue
+		}
+		vc.plugins = append(vc.plugins, id+":"+id+".gradle.plugin:"+version)
+	}
+	return vc, nil
+}
+
+func sortedAliases(entries map[string]interface{}) []string {
+	aliases := make([]string, 0, len(entries))
+	for alias := range entries {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	return aliases
+}
+
+// catalogVersion returns the version of a catalog entry, either a plain
+// version, a reference to the [versions] table or a rich version
+func catalogVersion(version interface{}, versions map[string]interface{}) string {
+	switch v := version.(type) {
+	case string:
+		return v
+	case map[string]interface{}:
+		if ref, ok := v["ref"].(string); ok {
+			return catalogVersion(versions[ref], nil)
+		}
+		for _, key := range []string{"strictly", "require", "prefer"} {
+			if s, ok := v[key].(string); ok {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// readFirst returns the contents of the first of files which exists in dir
+func readFirst(dir string, files []string) string {
+	for _, file := range files {
+		if data, err := os.ReadFile(filepath.Join(dir, file)); err == nil {
+			return string(data)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,196 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javagradle
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const (
+	testSettings = `rootProject.name = 'shop'
+include ':core',
+        ':web'
+`
+	testBuild = `
+allprojects {
+  group = 'com.me'
+  repositories {
+    mavenCentral()
+    maven { url 'https://repo.me.com/releases' }
+  }
+}
+`
+	testLockFile = `# This is a Gradle generated file for dependency locking.
+# Manual edits can break the build and are not advised.
+# This file is expected to be part of source control.
+com.google.guava:failureaccess:1.0.1=compileClasspath,runtimeClasspath
+com.google.guava:guava:31.1-jre=compileClasspath,runtimeClasspath
+junit:junit:4.13.2=testCompileClasspath,testRuntimeClasspath
+empty=annotationProcessor
+`
+	testBuildscriptLockFile = `com.diffplug.spotless:spotless-plugin-gradle:6.20.0=classpath
+empty=
+`
+	testVersionCatalog = `[versions]
+kotlin = "1.9.0"
+okhttp = "4.11.0"
+
+[libraries]
+okhttp = { module = "com.squareup.okhttp3:okhttp", version.ref = "okhttp" }
+kotlin-stdlib = { group = "org.jetbrains.kotlin", name = "kotlin-stdlib", version.ref = "kotlin" }
+slf4j = "org.slf4j:slf4j-api:2.0.7"
+okhttp-bom = { module = "com.squareup.okhttp3:okhttp-bom", version = { strictly = "4.11.0" } }
+# the version is resolved by the BOM
+okhttp-logging = { module = "com.squareup.okhttp3:logging-interceptor" }
+
+[plugins]
+kotlin-jvm = { id = "org.jetbrains.kotlin.jvm", version.ref = "kotlin" }
+spotless = "com.diffplug.spotless:6.20.0"
+versions = { id = "com.github.ben-manes.versions", version = { strictly = "0.47.0" } }
+`
+)
+
+func writeTestFile(t *testing.T, path, contents string) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadLockedBuild(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "settings.gradle"), testSettings)
+	writeTestFile(t, filepath.Join(dir, "build.gradle"), testBuild)
+	writeTestFile(t, filepath.Join(dir, "gradle.properties"), "version=2.1.0\n")
+	writeTestFile(t, filepath.Join(dir, "buildscript-gradle.lockfile"), testBuildscriptLockFile)
+	writeTestFile(t, filepath.Join(dir, "gradle", "libs.versions.toml"), testVersionCatalog)
+	writeTestFile(t, filepath.Join(dir, "core", "gradle.lockfile"), testLockFile)
+	writeTestFile(t, filepath.Join(dir, "web", "build.gradle"), "version = '3.0.0'\n")
+
+	b, err := readLockedBuild(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b == nil {
+		t.Fatal("Want locked build, got nil")
+	}
+
+	if want := (projectInfo{name: "shop", group: "com.me", version: "2.1.0"}); b.root != want {
+		t.Fatalf("\n got: %q\nwant: %q", b.root, want)
+	}
+	{
+		want := []subproject{
+			{path: ":core", dir: filepath.Join(dir, "core"), projectInfo: projectInfo{name: "core", group: "com.me", version: "2.1.0"}},
+			{path: ":web", dir: filepath.Join(dir, "web"), projectInfo: projectInfo{name: "web", group: "com.me", version: "3.0.0"}},
+		}
+		if reflect.DeepEqual(b.subprojects, want) == false {
+			t.Fatalf("\n got: %v\nwant: %v", b.subprojects, want)
+		}
+	}
+	{
+		want := []string{mavenCentral, "https://repo.me.com/releases"}
+		if reflect.DeepEqual(b.repos, want) == false {
+			t.Fatalf("\n got: %q\nwant: %q", b.repos, want)
+		}
+	}
+	{
+		di, err := b.dependencies(":core", runtimeClasspath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []string{"com.google.guava:failureaccess:1.0.1", "com.google.guava:guava:31.1-jre"}
+		if reflect.DeepEqual(di.root, want) == false {
+			t.Fatalf("\n got: %q\nwant: %q", di.root, want)
+		}
+	}
+	{
+		di, err := b.dependencies(":", buildEnvironment)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []string{"com.diffplug.spotless:spotless-plugin-gradle:6.20.0"}
+		if reflect.DeepEqual(di.root, want) == false {
+			t.Fatalf("\n got: %q\nwant: %q", di.root, want)
+		}
+	}
+	{
+		// the configurations of the root project which aren't locked are the libraries of the catalog
+		di, err := b.dependencies(":", runtimeClasspath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []string{
+			"org.jetbrains.kotlin:kotlin-stdlib:1.9.0",
+			"com.squareup.okhttp3:okhttp:4.11.0",
+			"com.squareup.okhttp3:okhttp-bom:4.11.0",
+			"org.slf4j:slf4j-api:2.0.7",
+		}
+		if reflect.DeepEqual(di.root, want) == false {
+			t.Fatalf("\n got: %q\nwant: %q", di.root, want)
+		}
+	}
+	if _, err := b.dependencies(":web", runtimeClasspath); err == nil {
+		t.Fatal("Want failure for a project without lockfile, got success")
+	}
+}
+
+func TestReadLockedBuild_NoLocks(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "build.gradle"), testBuild)
+
+	b, err := readLockedBuild(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b != nil {
+		t.Fatalf("Want nil, got %v", b)
+	}
+}
+
+func TestParseVersionCatalog(t *testing.T) {
+	catalog, err := parseVersionCatalog([]byte(testVersionCatalog))
+	if err != nil {
+		t.Fatal(err)
+	}
+	{
+		want := []string{
+			"org.jetbrains.kotlin:kotlin-stdlib:1.9.0",
+			"com.squareup.okhttp3:okhttp:4.11.0",
+			"com.squareup.okhttp3:okhttp-bom:4.11.0",
+			"org.slf4j:slf4j-api:2.0.7",
+		}
+		if reflect.DeepEqual(catalog.libraries, want) == false {
+			t.Fatalf("\n got: %q\nwant: %q", catalog.libraries, want)
+		}
+	}
+	want := []string{
+		"org.jetbrains.kotlin.jvm:org.jetbrains.kotlin.jvm.gradle.plugin:1.9.0",
+		"com.diffplug.spotless:com.diffplug.spotless.gradle.plugin:6.20.0",
+		"com.github.ben-manes.versions:com.github.ben-manes.versions.gradle.plugin:0.47.0",
+	}
+	if reflect.DeepEqual(catalog.plugins, want) == false {
+		t.Fatalf("\n got: %q\nwant: %q", catalog.plugins, want)
+	}
+}
+
+func TestParseRepositories(t *testing.T) {
+	repos := parseRepositories(`
+repositories {
+    google()
+    maven(url = uri("https://jitpack.io"))
+    maven("https://repo.me.com/kts")
+    gradlePluginPortal()
+    mavenCentral()
+    google()
+}
+`)
+	want := []string{googleMaven, "https://jitpack.io", "https://repo.me.com/kts", gradlePluginPortal, mavenCentral}
+	if reflect.DeepEqual(repos, want) == false {
+		t.Fatalf("\n got: %q\nwant: %q", repos, want)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
+	"log"
+	"os"
 	"os/exec"
 	"strings"
 )
@@ -16,6 +18,68 @@ type projectInfo struct {
 	version string
 }
 
+// subproject is a project included in a multi-project build by settings.gradle
+type subproject struct {
+	projectInfo
+	path string
+	dir  string
+}
+
+// prefix output with spdx-project as a parsing hint, the fields are tab separated
+// as the project path is itself colon separated
+var initProjects = `
+gradle.rootProject {
+  tasks.register('spdxPrintProjects') {
+    doLast {
+      subprojects.each { println "spdx-project:" + it.path + "\t" + it.name + "\t" + it.group + "\t" + it.version + "\t" + it.projectDir }
+    }
+  }
+}
+`
+
+// list all subprojects of the build, if any
+func getSubprojects(dir string) ([]subproject, error) {
+	initPath, err := writeInitScript(initProjects)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(initPath)
+
+	out, err := newGradleExec(dir).run(":spdxPrintProjects", "--init-script", initPath, "-q").CombinedOutput()
+	if err != nil {
+		log.Println(string(out))
+		return nil, err
+	}
+	return parseSubprojectsOutput(out)
+}
+
+func parseSubprojectsOutput(out []byte) ([]subproject, error) {
+	result := []subproject{}
+	br := bytes.NewReader(out)
+	sc := bufio.NewScanner(br)
+
+	for sc.Scan() {
+		line := sc.Text()
+		if !strings.HasPrefix(line, "spdx-project:") {
+			continue
+		}
+		split := strings.Split(strings.TrimPrefix(line, "spdx-project:"), "\t")
+		if len(split) != 5 {
+			return nil, fmt.Errorf("Parse error on : %q", line)
+		}
+		result = append(result, subproject{
+			path: split[0],
+			dir:  split[4],
+			projectInfo: projectInfo{
+				name:    split[1],
+				group:   split[2],
+				version: split[3],
+			},
+		})
+	}
+	return result, nil
+}
+
 // returns name, version
 func getProjectInfo(path string) (projectInfo, error) {
 	cmd := newGradleExec(path).run("properties", "-q")
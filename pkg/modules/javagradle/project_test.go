@@ -3,6 +3,7 @@
 package javagradle
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -58,3 +59,18 @@ func TestParseProjectInfo_Failures(t *testing.T) {
 		}
 	}
 }
+
+func TestParseSubprojectsOutput(t *testing.T) {
+	out := "> Configure project :\nspdx-project::core\tcore\tcom.me\t1.0.0\t/src/core\nspdx-project::libs:util\tutil\tcom.me\t1.0.0\t/src/libs/util\n"
+	subprojects, err := parseSubprojectsOutput([]byte(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []subproject{
+		{path: ":core", dir: "/src/core", projectInfo: projectInfo{name: "core", group: "com.me", version: "1.0.0"}},
+		{path: ":libs:util", dir: "/src/libs/util", projectInfo: projectInfo{name: "util", group: "com.me", version: "1.0.0"}},
+	}
+	if reflect.DeepEqual(subprojects, want) == false {
+		t.Fatalf("\n got: %v\nwant: %v", subprojects, want)
+	}
+}
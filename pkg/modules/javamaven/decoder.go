@@ -52,11 +52,15 @@ func stdOutCapture() func() (string, error) {
 	}
 }
 
-func getDependencyList() ([]string, error) {
+func getDependencyList(globalSettingFile string) ([]string, error) {
 	done := stdOutCapture()
 	var err error
 
-	cmd1 := exec.Command("mvn", "-o", "dependency:list")
+	args := []string{"-o", "dependency:list"}
+	if len(globalSettingFile) > 0 {
+		args = append(args, "-gs="+globalSettingFile)
+	}
+	cmd1 := exec.Command("mvn", args...)
 	cmd2 := exec.Command("grep", ":.*:.*:.*")
 	cmd3 := exec.Command("cut", "-d]", "-f2-")
 	cmd4 := exec.Command("sort", "-u")
@@ -82,13 +86,71 @@ func getDependencyList() ([]string, error) {
 	return s, err
 }
 
+// listDependencies returns every artifact the project resolves to, through
+// `mvn dependency:list` when Maven is installed and the built-in POM resolver otherwise.
+// res is the reactor already resolved by the built-in resolver, if any
+func listDependencies(fpath string, globalSettingFile string, res *resolution) ([]artifact, error) {
+	if !hasMaven() {
+		if res == nil {
+			var err error
+			if res, err = resolveReactor(fpath, globalSettingFile); err != nil {
+				return nil, err
+			}
+		}
+		return res.artifacts, nil
+	}
+
+	dependencyList, err := getDependencyList(globalSettingFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseDependencyList(dependencyList), nil
+}
+
+// parseDependencyList reads the `groupId:artifactId:type:version:scope` lines
+// printed by `mvn dependency:list`
+func parseDependencyList(dependencyList []string) []artifact {
+	artifacts := make([]artifact, 0)
+	for _, line := range dependencyList {
+		// If any errors captured in mvn dependency, ignore that
+		if strings.Contains(line, "Invalid module name") {
+			continue
+		}
+
+		// the coordinates may be followed by the java module name, and the
+		// output ends with the build status lines, which have no coordinates
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		parts := strings.Split(fields[0], ":")
+		if len(parts) < 4 {
+			continue
+		}
+
+		a := artifact{
+			GroupID:    parts[0],
+			ArtifactID: parts[1],
+			Version:    parts[3],
+		}
+		switch {
+		case len(parts) > 5: // groupId:artifactId:type:classifier:version:scope
+			a.Version, a.Scope = parts[4], parts[5]
+		case len(parts) > 4:
+			a.Scope = parts[4]
+		}
+		artifacts = append(artifacts, a)
+	}
+
+	return artifacts
+}
+
 func updateLicenseInformationToModule(mod *models.Module) {
-	licensePkg, err := helper.GetLicenses(".")
+	detection, err := helper.DetectLicenses(".", helper.LicenseThreshold)
 	if err == nil {
-		mod.LicenseDeclared = helper.BuildLicenseDeclared(licensePkg.ID)
-		mod.LicenseConcluded = helper.BuildLicenseConcluded(licensePkg.ID)
-		mod.Copyright = helper.GetCopyright(licensePkg.ExtractedText)
-		mod.CommentsLicense = licensePkg.Comments
+		helper.SetModuleLicenses(mod, detection)
+		mod.Copyright = helper.GetCopyright(detection.Text())
 	}
 }
 
@@ -267,20 +329,24 @@ func getModule(modules []models.Module, name string) (models.Module, error) {
 }
 
 // If parent pom.xml has modules information in it, go to individual modules pom.xml
-func convertPkgModulesToModule(existingModules []models.Module, fpath string, moduleName string, parentPom gopom.Project) ([]models.Module, error) {
+// Each module keeps its own dependencies, the modules of the reactor are linked
+// together by linkReactorModules once all of them are converted
+func convertPkgModulesToModule(existingModules []models.Module, reactorProject reactorProject, parentPom gopom.Project, reactor reactorIndex) []models.Module {
 	var modules []models.Module
-	filePath := fpath + "/" + moduleName
-	project, err := readAndLoadPomFile(filePath)
-	if err != nil {
-		return []models.Module{}, err
-	}
+	project := reactorProject.project
 
 	parentMod := convertProjectLevelPackageToModule(project)
 	parentMod.Root = false
+	parentMod.LocalPath = reactorProject.dir
+	parentMod.Deployable = isDeployable(project)
 	modules = append(modules, parentMod)
 
 	// Include dependecy from module pom.xml if it is not existing in ParentPom
 	for _, element := range project.Dependencies {
+		if reactor.contains(element.ArtifactID) {
+			continue
+		}
+
 		name := strings.Replace(strings.TrimSpace(element.ArtifactID), " ", "-", -1)
 		found1 := false
 		found := findInDependency(parentPom.Dependencies, name)
@@ -289,14 +355,14 @@ func convertPkgModulesToModule(existingModules []models.Module, fpath string, mo
 			if !found1 {
 				mod := createModule(element.GroupID, name, element.Version, project)
 				modules = append(modules, mod)
-				parentMod.Modules[mod.Name] = &mod
+				parentMod.Modules[mod.Name] = withScope(mod, dependencyScope(element))
 			}
 		}
 
 		if found || found1 {
 			module, err := getModule(existingModules, name)
 			if err == nil {
-				parentMod.Modules[name] = &module
+				parentMod.Modules[name] = withScope(module, dependencyScope(element))
 			}
 		}
 	}
@@ -311,21 +377,63 @@ func convertPkgModulesToModule(existingModules []models.Module, fpath string, mo
 			if !found1 {
 				mod := createModule(element.GroupID, name, element.Version, project)
 				modules = append(modules, mod)
-				parentMod.Modules[mod.Name] = &mod
+				parentMod.Modules[mod.Name] = withScope(mod, models.ScopeBuild)
 			}
 		}
 
 		if found || found1 {
 			module, err := getModule(existingModules, name)
 			if err == nil {
-				parentMod.Modules[name] = &module
+				parentMod.Modules[name] = withScope(module, models.ScopeBuild)
 			}
 		}
 	}
-	return modules, nil
+	return modules
+}
+
+// dependencyScope returns the scope of a dependency declared in a POM
+func dependencyScope(dep gopom.Dependency) models.Scope {
+	switch {
+	case dep.Scope == scopeTest:
+		return models.ScopeTest
+	case dep.Scope == scopeProvided, dep.Scope == scopeSystem:
+		return models.ScopeProvided
+	case dep.Optional == "true":
+		return models.ScopeOptional
+	}
+	return models.ScopeRuntime
+}
+
+// DependencyScopes returns the scope of the dependencies of the project at path, by
+// groupId:artifactId, as its effective POM declares them: inherited from its parents,
+// with the scopes of the dependency management and the properties interpolated
+func DependencyScopes(path string, globalSettingFile string) (map[string]models.Scope, error) {
+	project, err := readAndLoadPomFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pom, err := newPomResolver(globalSettingFile).effective(project, path)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := map[string]models.Scope{}
+	for _, dep := range pom.Dependencies {
+		if dep.Scope != scopeImport {
+			scopes[dep.GroupID+":"+dep.ArtifactID] = dependencyScope(dep)
+		}
+	}
+	return scopes, nil
+}
+
+// withScope returns the reference to a module listed with the given scope in the Modules of another one
+func withScope(mod models.Module, scope models.Scope) *models.Module {
+	mod.Scope = scope
+	return &mod
 }
 
-func convertPOMReaderToModules(fpath string, lookForDepenent bool) ([]models.Module, error) {
+func convertPOMReaderToModules(fpath string, lookForDepenent bool, globalSettingFile string, res *resolution) ([]models.Module, error) {
 	modules := make([]models.Module, 0)
 	project, err := readAndLoadPomFile(fpath)
 	if err != nil {
@@ -333,7 +441,9 @@ func convertPOMReaderToModules(fpath string, lookForDepenent bool) ([]models.Mod
 	}
 	parentMod := convertProjectLevelPackageToModule(project)
 	parentMod.Root = true
+	parentMod.LocalPath = fpath
 	modules = append(modules, parentMod)
+	reactor := readReactor(fpath, project)
 
 	// iterate over dependencyManagement
 	for _, dependencyManagement := range project.DependencyManagement.Dependencies {
@@ -346,7 +456,7 @@ func convertPOMReaderToModules(fpath string, lookForDepenent bool) ([]models.Mod
 	for _, dep := range project.Dependencies {
 		mod := createModule(dep.GroupID, dep.ArtifactID, dep.Version, project)
 		modules = append(modules, mod)
-		parentMod.Modules[mod.Name] = &mod
+		parentMod.Modules[mod.Name] = withScope(mod, dependencyScope(dep))
 	}
 
 	// iterate over Plugins
@@ -355,7 +465,7 @@ func convertPOMReaderToModules(fpath string, lookForDepenent bool) ([]models.Mod
 		if len(plugin.GroupID) == 0 {
 			mod := createModule(plugin.GroupID, plugin.ArtifactID, plugin.Version, project)
 			modules = append(modules, mod)
-			parentMod.Modules[mod.Name] = &mod
+			parentMod.Modules[mod.Name] = withScope(mod, models.ScopeBuild)
 		}
 	}
 
@@ -363,37 +473,33 @@ func convertPOMReaderToModules(fpath string, lookForDepenent bool) ([]models.Mod
 	for _, plugin := range project.Build.PluginManagement.Plugins {
 		mod := createModule(plugin.GroupID, plugin.ArtifactID, plugin.Version, project)
 		modules = append(modules, mod)
-		parentMod.Modules[mod.Name] = &mod
+		parentMod.Modules[mod.Name] = withScope(mod, models.ScopeBuild)
 	}
 
-	dependencyList, err := getDependencyList()
+	dependencyList, err := listDependencies(fpath, globalSettingFile, res)
 	if err != nil {
 		fmt.Println("error in getting mvn dependency list and parsing it")
 		return modules, err
 	}
 
 	// Add additional dependency from mvn dependency list to pom.xml dependency list
-	var i int
-	for i < len(dependencyList)-2 { // skip 1 empty line and Finished statement line
-		// If any errors captured in mvn dependency, ignore that
-		if strings.Contains(dependencyList[i], "Invalid module name") {
-			i++
-			continue
-		}
-		dependencyItem := strings.Split(dependencyList[i], ":")[1]
-
-		found := false
+	for _, dependencyItem := range dependencyList {
+		found := reactor.contains(dependencyItem.ArtifactID)
 		// iterate over dependencies
 		for _, dep := range project.Dependencies {
-			if dep.ArtifactID == dependencyItem {
+			if dep.ArtifactID == dependencyItem.ArtifactID {
 				found = true
+				// the scope may be inherited from the dependencyManagement of a parent
+				if mod, ok := parentMod.Modules[dep.ArtifactID]; ok && dep.Scope == "" {
+					mod.Scope = dependencyScope(gopom.Dependency{Scope: dependencyItem.Scope, Optional: dep.Optional})
+				}
 				break
 			}
 		}
 
 		if !found {
 			for _, dependencyManagement := range project.DependencyManagement.Dependencies {
-				if dependencyManagement.ArtifactID == dependencyItem {
+				if dependencyManagement.ArtifactID == dependencyItem.ArtifactID {
 					found = true
 					break
 				}
@@ -401,25 +507,18 @@ func convertPOMReaderToModules(fpath string, lookForDepenent bool) ([]models.Mod
 		}
 
 		if !found {
-			groupID := strings.Split(dependencyList[i], ":")[0]
-			version := strings.Split(dependencyList[i], ":")[3]
-			mod := createModule(strings.TrimSpace(groupID), dependencyItem, version, project)
+			mod := createModule(dependencyItem.GroupID, dependencyItem.ArtifactID, dependencyItem.Version, project)
 			modules = append(modules, mod)
-			parentMod.Modules[mod.Name] = &mod
+			parentMod.Modules[mod.Name] = withScope(mod, dependencyScope(gopom.Dependency{Scope: dependencyItem.Scope}))
 		}
-		i++
 	}
 
 	if lookForDepenent {
-		// iterate over Modules
-		for _, module := range project.Modules {
-			additionalModules, err := convertPkgModulesToModule(modules, fpath, module, project)
-			if err != nil {
-				// continue reading other module pom.xml file
-				continue
-			}
-			modules = append(modules, additionalModules...)
+		// iterate over the modules of the reactor, including the ones of nested aggregators
+		for _, reactorProject := range reactor {
+			modules = append(modules, convertPkgModulesToModule(modules, reactorProject, project, reactor)...)
 		}
+		linkReactorModules(modules, reactor)
 	}
 	return modules, nil
 }
@@ -538,6 +637,13 @@ func buildDependenciesGraph(modules []models.Module, tdList map[string][]string)
 					continue
 				}
 
+				// keep the scope of dependencies declared in the POM and the links of the reactor
+				var scope models.Scope
+				var relationship models.RelationshipType
+				if existing, ok := modules[moduleIndex[moduleName]].Modules[depName]; ok {
+					scope, relationship = existing.Scope, existing.Relationship
+				}
+
 				modules[moduleIndex[moduleName]].Modules[depName] = &models.Module{
 					Name:                    depModule.Name,
 					Version:                 depModule.Version,
@@ -555,6 +661,8 @@ func buildDependenciesGraph(modules []models.Module, tdList map[string][]string)
 					Copyright:               depModule.Copyright,
 					PackageComment:          depModule.PackageComment,
 					Root:                    depModule.Root,
+					Scope:                   scope,
+					Relationship:            relationship,
 				}
 			}
 		}
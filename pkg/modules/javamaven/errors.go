@@ -10,3 +10,4 @@ type errType error
 
 var errFailedToConvertModules errType = errors.New("failed to convert modules")
 var moduleNotFound errType = errors.New("module not found")
+var errPomCycle errType = errors.New("the POM is its own parent or imports itself")
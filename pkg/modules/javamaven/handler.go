@@ -14,6 +14,9 @@ import (
 	"github.com/spdx/spdx-sbom-generator/pkg/models"
 )
 
+// nativeResolverVersion is reported in place of the Maven version when mvn is not installed
+const nativeResolverVersion = "built-in POM resolver"
+
 type javamaven struct {
 	metadata   models.PluginMetadata
 	rootModule *models.Module
@@ -62,13 +65,14 @@ func (m *javamaven) IsValid(path string) bool {
 }
 
 // HasModulesInstalled ...
+// When mvn is not in the PATH the dependencies are resolved by the built-in POM
+// resolver from the local repository instead
 func (m *javamaven) HasModulesInstalled(path string) error {
 	// TODO: How to verify is java project is build
-	// Enforcing mvn path to be set in PATH variable
 	fname, err := exec.LookPath("mvn")
 	if err != nil {
-		log.Println(err)
-		return err
+		log.Printf("mvn not found, resolving dependencies from %s", localRepository(""))
+		return nil
 	}
 
 	_, err = filepath.Abs(fname)
@@ -82,6 +86,10 @@ func (m *javamaven) HasModulesInstalled(path string) error {
 
 // GetVersion...
 func (m *javamaven) GetVersion() (string, error) {
+	if !hasMaven() {
+		return nativeResolverVersion, nil
+	}
+
 	err := m.buildCmd(VersionCmd, ".")
 	if err != nil {
 		return "", err
@@ -106,7 +114,7 @@ func (m *javamaven) GetRootModule(path string) (*models.Module, error) {
 
 // ListUsedModules...
 func (m *javamaven) ListUsedModules(path string) ([]models.Module, error) {
-	modules, err := convertPOMReaderToModules(path, true)
+	modules, err := convertPOMReaderToModules(path, true, "", nil)
 
 	if err != nil {
 		log.Println(err)
@@ -118,11 +126,26 @@ func (m *javamaven) ListUsedModules(path string) ([]models.Module, error) {
 
 // ListModulesWithDeps ...
 func (m *javamaven) ListModulesWithDeps(path string, globalSettingFile string) ([]models.Module, error) {
-	modules, err := m.ListUsedModules(path)
+	// without mvn, the reactor is resolved once for both the modules and their graph
+	var res *resolution
+	if !hasMaven() {
+		var err error
+		if res, err = resolveReactor(path, globalSettingFile); err != nil {
+			return nil, err
+		}
+	}
+
+	modules, err := convertPOMReaderToModules(path, true, globalSettingFile, res)
 	if err != nil {
+		log.Println(err)
 		return nil, err
 	}
 
+	if res != nil {
+		buildDependenciesGraph(modules, res.graph)
+		return modules, nil
+	}
+
 	tdList, err := getTransitiveDependencyList(path, globalSettingFile)
 	if err != nil {
 		fmt.Println("error in getting mvn transitive dependency tree and parsing it")
@@ -135,7 +158,7 @@ func (m *javamaven) ListModulesWithDeps(path string, globalSettingFile string) (
 }
 
 func (m *javamaven) getModule(path string) (models.Module, error) {
-	modules, err := convertPOMReaderToModules(path, false)
+	modules, err := convertPOMReaderToModules(path, false, "", nil)
 
 	if err != nil {
 		log.Println(err)
@@ -163,6 +186,12 @@ func (m *javamaven) buildCmd(cmd command, path string) error {
 	return command.Build()
 }
 
+// hasMaven checks whether the mvn executable is available in the PATH
+func hasMaven() bool {
+	_, err := exec.LookPath("mvn")
+	return err == nil
+}
+
 func readCheckSum(content string) string {
 	h := sha1.New()
 	h.Write([]byte(content))
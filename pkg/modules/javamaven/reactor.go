@@ -0,0 +1,160 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vifraa/gopom"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// packaging types which don't produce an artifact that can be deployed on its own
+var nonDeployablePackaging = map[string]bool{
+	"pom": true,
+}
+
+// reactorProject is a module listed in the <modules> of an aggregator POM
+type reactorProject struct {
+	dir           string
+	aggregatorDir string
+	project       gopom.Project
+}
+
+func (r reactorProject) groupID() string {
+	if r.project.GroupID != "" {
+		return r.project.GroupID
+	}
+	return r.project.Parent.GroupID
+}
+
+// reactorIndex lists every module of a multi-module build
+type reactorIndex []reactorProject
+
+// readReactor reads the modules of the aggregator POM found at fpath and,
+// recursively, the modules of the nested aggregators
+func readReactor(fpath string, project gopom.Project) reactorIndex {
+	reactor := make(reactorIndex, 0)
+	for _, module := range project.Modules {
+		dir := filepath.Join(fpath, module)
+		moduleProject, err := readAndLoadPomFile(dir)
+		if err != nil {
+			// continue reading other module pom.xml file
+			continue
+		}
+
+		reactor = append(reactor, reactorProject{dir: dir, aggregatorDir: fpath, project: moduleProject})
+		reactor = append(reactor, readReactor(dir, moduleProject)...)
+	}
+
+	return reactor
+}
+
+func (r reactorIndex) contains(artifactID string) bool {
+	for _, reactorProject := range r {
+		if reactorProject.project.ArtifactID == artifactID {
+			return true
+		}
+	}
+	return false
+}
+
+func isDeployable(project gopom.Project) bool {
+	// jar is the default packaging
+	return !nonDeployablePackaging[project.Packaging]
+}
+
+// linkReactorModules relates every aggregator to the modules it lists with
+// CONTAINS, and the modules depending on each other with DEPENDS_ON
+func linkReactorModules(modules []models.Module, reactor reactorIndex) {
+	byDir := map[string]int{}
+	for i := range modules {
+		if modules[i].LocalPath != "" {
+			byDir[modules[i].LocalPath] = i
+		}
+	}
+
+	byArtifact := map[string]int{}
+	for _, reactorProject := range reactor {
+		if i, ok := byDir[reactorProject.dir]; ok {
+			byArtifact[reactorProject.project.ArtifactID] = i
+		}
+	}
+
+	for _, reactorProject := range reactor {
+		i, ok := byDir[reactorProject.dir]
+		if !ok {
+			continue
+		}
+
+		if aggregator, ok := byDir[reactorProject.aggregatorDir]; ok {
+			modules[aggregator].Modules[modules[i].Name] = linkModule(modules[i], models.Contains)
+		}
+
+		for _, dep := range reactorProject.project.Dependencies {
+			if j, ok := byArtifact[dep.ArtifactID]; ok {
+				modules[i].Modules[modules[j].Name] = linkModule(modules[j], models.DependsOn)
+			}
+		}
+	}
+}
+
+// linkModule returns the reference to a module to be listed in the Modules of another one
+func linkModule(module models.Module, relationship models.RelationshipType) *models.Module {
+	module.Modules = nil
+	module.Relationship = relationship
+	return &module
+}
+
+// resolveReactor resolves the project at path along with every module of its
+// reactor. The modules are read from their folder rather than from the local
+// repository, where they might not have been installed yet
+func resolveReactor(path, globalSettingFile string) (*resolution, error) {
+	project, err := readAndLoadPomFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	reactor := readReactor(path, project)
+	r := newPomResolver(globalSettingFile)
+	for _, reactorProject := range reactor {
+		r.reactor[reactorProject.groupID()+":"+reactorProject.project.ArtifactID] = reactorProject.dir
+	}
+
+	res, err := r.resolve(path)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, reactorProject := range reactor {
+		moduleRes, err := r.resolve(reactorProject.dir)
+		if err != nil {
+			log.Debugf("unable to resolve module %s: %v", reactorProject.dir, err)
+			continue
+		}
+
+		for from, deps := range moduleRes.graph {
+			for _, to := range deps {
+				res.addEdge(from, to)
+			}
+		}
+		res.artifacts = append(res.artifacts, moduleRes.artifacts...)
+	}
+
+	// keep the nearest version of every artifact and leave out the modules
+	// of the reactor, which aren't dependencies of the build
+	artifacts := make([]artifact, 0, len(res.artifacts))
+	seen := map[string]bool{}
+	for _, a := range res.artifacts {
+		if seen[a.key()] || r.reactor[a.key()] != "" {
+			continue
+		}
+		seen[a.key()] = true
+		artifacts = append(artifacts, a)
+	}
+	res.artifacts = artifacts
+
+	return res, nil
+}
@@ -0,0 +1,442 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vifraa/gopom"
+)
+
+const (
+	scopeCompile  = "compile"
+	scopeProvided = "provided"
+	scopeRuntime  = "runtime"
+	scopeTest     = "test"
+	scopeSystem   = "system"
+	scopeImport   = "import"
+
+	// maxInterpolationDepth guards against properties referencing each other in a loop
+	maxInterpolationDepth = 10
+)
+
+var propertyReference = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// artifact is a dependency coordinate selected by the resolver
+type artifact struct {
+	GroupID    string
+	ArtifactID string
+	Version    string
+	Scope      string
+}
+
+func (a artifact) key() string {
+	return a.GroupID + ":" + a.ArtifactID
+}
+
+// resolution is the outcome of resolving a project without invoking mvn. The
+// graph has the same shape as the one read from `mvn dependency:tree`, keyed
+// by artifactId
+type resolution struct {
+	root      artifact
+	artifacts []artifact
+	graph     map[string][]string
+}
+
+// effectivePom is a project with its parent hierarchy and imported BOMs merged
+// in and every property reference interpolated
+type effectivePom struct {
+	GroupID      string
+	ArtifactID   string
+	Version      string
+	Properties   map[string]string
+	Management   []gopom.Dependency
+	Dependencies []gopom.Dependency
+}
+
+func (e *effectivePom) managed(groupID, artifactID string) (gopom.Dependency, bool) {
+	for _, dep := range e.Management {
+		if dep.GroupID == groupID && dep.ArtifactID == artifactID && dep.Scope != scopeImport {
+			return dep, true
+		}
+	}
+	return gopom.Dependency{}, false
+}
+
+// pomResolver resolves Maven dependency graphs from the POM files found next to
+// the project and in the local repository, so neither a JDK nor mvn is required
+type pomResolver struct {
+	repository string
+	reactor    map[string]string
+	cache      map[string]*effectivePom
+	// visiting are the POMs being made effective, by GAV, a POM being its own
+	// ancestor or importing itself through BOMs otherwise recursing forever
+	visiting map[string]bool
+}
+
+type mavenSettings struct {
+	LocalRepository string `xml:"localRepository"`
+}
+
+func newPomResolver(globalSettingFile string) *pomResolver {
+	return &pomResolver{
+		repository: localRepository(globalSettingFile),
+		reactor:    map[string]string{},
+		cache:      map[string]*effectivePom{},
+		visiting:   map[string]bool{},
+	}
+}
+
+// localRepository returns the repository configured in the Maven settings,
+// falling back to the default ~/.m2/repository
+func localRepository(globalSettingFile string) string {
+	home, _ := os.UserHomeDir()
+	settingFiles := []string{filepath.Join(home, ".m2", "settings.xml")}
+	if globalSettingFile != "" {
+		settingFiles = append([]string{globalSettingFile}, settingFiles...)
+	}
+
+	for _, settingFile := range settingFiles {
+		content, err := os.ReadFile(settingFile)
+		if err != nil {
+			continue
+		}
+
+		var settings mavenSettings
+		if err := xml.Unmarshal(content, &settings); err == nil && settings.LocalRepository != "" {
+			return strings.Replace(settings.LocalRepository, "${user.home}", home, 1)
+		}
+	}
+
+	return filepath.Join(home, ".m2", "repository")
+}
+
+// resolve walks the dependencies of the project at path breadth first so the
+// nearest declaration of an artifact wins, as it does in Maven
+func (r *pomResolver) resolve(path string) (*resolution, error) {
+	project, err := readAndLoadPomFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := r.effective(project, path)
+	if err != nil {
+		return nil, err
+	}
+
+	type node struct {
+		dependency gopom.Dependency
+		parent     string
+		scope      string
+		exclusions []gopom.Exclusion
+	}
+
+	res := &resolution{
+		root:  artifact{GroupID: root.GroupID, ArtifactID: root.ArtifactID, Version: root.Version},
+		graph: map[string][]string{},
+	}
+	queue := make([]node, 0)
+	for _, dep := range root.Dependencies {
+		if dep.Scope == scopeImport {
+			continue
+		}
+		queue = append(queue, node{dependency: dep, parent: root.ArtifactID, scope: defaultScope(dep.Scope), exclusions: dep.Exclusions})
+	}
+
+	selected := map[string]bool{root.GroupID + ":" + root.ArtifactID: true}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+
+		dep := n.dependency
+		version := dep.Version
+		if managed, ok := root.managed(dep.GroupID, dep.ArtifactID); ok && managed.Version != "" {
+			version = managed.Version
+		}
+
+		a := artifact{GroupID: dep.GroupID, ArtifactID: dep.ArtifactID, Version: version, Scope: n.scope}
+		res.addEdge(n.parent, a.ArtifactID)
+		if selected[a.key()] {
+			continue
+		}
+		selected[a.key()] = true
+		res.artifacts = append(res.artifacts, a)
+
+		if version == "" || n.scope == scopeSystem {
+			continue
+		}
+		pom, err := r.load(a.GroupID, a.ArtifactID, a.Version)
+		if err != nil {
+			log.Debugf("unable to read the POM of %s:%s, its dependencies are skipped: %v", a.key(), a.Version, err)
+			continue
+		}
+
+		for _, child := range pom.Dependencies {
+			if child.Scope == scopeTest || child.Scope == scopeProvided || child.Scope == scopeImport || child.Optional == "true" {
+				continue
+			}
+			if isExcluded(n.exclusions, child) {
+				continue
+			}
+			if child.Version == "" {
+				if managed, ok := pom.managed(child.GroupID, child.ArtifactID); ok {
+					child.Version = managed.Version
+				}
+			}
+			queue = append(queue, node{
+				dependency: child,
+				parent:     a.ArtifactID,
+				scope:      transitiveScope(n.scope, defaultScope(child.Scope)),
+				exclusions: append(append([]gopom.Exclusion{}, n.exclusions...), child.Exclusions...),
+			})
+		}
+	}
+
+	return res, nil
+}
+
+func (r *resolution) addEdge(from, to string) {
+	for _, existing := range r.graph[from] {
+		if existing == to {
+			return
+		}
+	}
+	r.graph[from] = append(r.graph[from], to)
+}
+
+// load reads a POM from the modules of the reactor or from the local repository
+func (r *pomResolver) load(groupID, artifactID, version string) (*effectivePom, error) {
+	gav := fmt.Sprintf("%s:%s:%s", groupID, artifactID, version)
+	if pom, ok := r.cache[gav]; ok {
+		return pom, nil
+	}
+
+	dir, ok := r.reactor[groupID+":"+artifactID]
+	pomFile := filepath.Join(dir, "pom.xml")
+	if !ok {
+		dir = filepath.Join(r.repository, filepath.FromSlash(strings.ReplaceAll(groupID, ".", "/")), artifactID, version)
+		pomFile = filepath.Join(dir, fmt.Sprintf("%s-%s.pom", artifactID, version))
+	}
+
+	project, err := gopom.Parse(pomFile)
+	if err != nil {
+		return nil, err
+	}
+
+	pom, err := r.effective(*project, dir)
+	if err != nil {
+		return nil, err
+	}
+	r.cache[gav] = pom
+
+	return pom, nil
+}
+
+// effective merges the parent hierarchy into the project found at dir, imports
+// the BOMs it references and interpolates the property references
+func (r *pomResolver) effective(project gopom.Project, dir string) (*effectivePom, error) {
+	gav := projectGAV(project)
+	if r.visiting[gav] {
+		return nil, fmt.Errorf("%w: %s", errPomCycle, gav)
+	}
+	r.visiting[gav] = true
+	defer delete(r.visiting, gav)
+
+	pom := &effectivePom{
+		GroupID:    project.GroupID,
+		ArtifactID: project.ArtifactID,
+		Version:    project.Version,
+		Properties: map[string]string{},
+	}
+
+	var parent *effectivePom
+	if project.Parent.ArtifactID != "" {
+		parent = r.parent(project.Parent, dir)
+		if pom.GroupID == "" {
+			pom.GroupID = project.Parent.GroupID
+		}
+		if pom.Version == "" {
+			pom.Version = project.Parent.Version
+		}
+	}
+
+	if parent != nil {
+		for k, v := range parent.Properties {
+			pom.Properties[k] = v
+		}
+	}
+	for k, v := range project.Properties.Entries {
+		pom.Properties[k] = v
+	}
+	pom.Properties["project.groupId"] = pom.GroupID
+	pom.Properties["project.artifactId"] = pom.ArtifactID
+	pom.Properties["project.version"] = pom.Version
+	pom.Properties["project.parent.groupId"] = project.Parent.GroupID
+	pom.Properties["project.parent.version"] = project.Parent.Version
+	pom.Properties["pom.groupId"] = pom.GroupID
+	pom.Properties["pom.version"] = pom.Version
+	pom.Version = pom.interpolate(pom.Version)
+
+	// explicit declarations take precedence over the imported BOMs, which
+	// take precedence over what is inherited from the parent
+	imports := make([]gopom.Dependency, 0)
+	for _, dep := range project.DependencyManagement.Dependencies {
+		dep = pom.interpolateDependency(dep)
+		pom.Management = append(pom.Management, dep)
+		if dep.Scope == scopeImport && dep.Type == "pom" {
+			imports = append(imports, dep)
+		}
+	}
+	for _, dep := range imports {
+		bom, err := r.load(dep.GroupID, dep.ArtifactID, dep.Version)
+		if err != nil {
+			log.Debugf("unable to import BOM %s:%s:%s: %v", dep.GroupID, dep.ArtifactID, dep.Version, err)
+			continue
+		}
+		pom.Management = append(pom.Management, bom.Management...)
+	}
+	if parent != nil {
+		pom.Management = append(pom.Management, parent.Management...)
+	}
+
+	declared := map[string]bool{}
+	for _, dep := range project.Dependencies {
+		dep = pom.interpolateDependency(dep)
+		declared[dep.GroupID+":"+dep.ArtifactID] = true
+		pom.Dependencies = append(pom.Dependencies, pom.applyManagement(dep))
+	}
+	if parent != nil {
+		for _, dep := range parent.Dependencies {
+			if !declared[dep.GroupID+":"+dep.ArtifactID] {
+				pom.Dependencies = append(pom.Dependencies, pom.applyManagement(dep))
+			}
+		}
+	}
+
+	return pom, nil
+}
+
+// projectGAV returns the coordinates of the project, inherited from its parent if unset
+func projectGAV(project gopom.Project) string {
+	groupID, version := project.GroupID, project.Version
+	if groupID == "" {
+		groupID = project.Parent.GroupID
+	}
+	if version == "" {
+		version = project.Parent.Version
+	}
+	return fmt.Sprintf("%s:%s:%s", groupID, project.ArtifactID, version)
+}
+
+// parent loads the parent POM from its relative path when it matches the
+// declared coordinates, otherwise from the local repository
+func (r *pomResolver) parent(parent gopom.Parent, dir string) *effectivePom {
+	relativePath := parent.RelativePath
+	if relativePath == "" {
+		relativePath = ".."
+	}
+	parentDir := filepath.Join(dir, relativePath)
+	if strings.HasSuffix(relativePath, ".xml") {
+		parentDir = filepath.Dir(parentDir)
+	}
+
+	if project, err := readAndLoadPomFile(parentDir); err == nil && project.ArtifactID == parent.ArtifactID {
+		if pom, err := r.effective(project, parentDir); err == nil {
+			return pom
+		}
+	}
+
+	pom, err := r.load(parent.GroupID, parent.ArtifactID, parent.Version)
+	if err != nil {
+		log.Debugf("unable to read parent POM %s:%s:%s: %v", parent.GroupID, parent.ArtifactID, parent.Version, err)
+		return nil
+	}
+
+	return pom
+}
+
+// applyManagement fills in the version, scope and exclusions set in the dependencyManagement
+func (e *effectivePom) applyManagement(dep gopom.Dependency) gopom.Dependency {
+	managed, ok := e.managed(dep.GroupID, dep.ArtifactID)
+	if !ok {
+		return dep
+	}
+
+	if dep.Version == "" {
+		dep.Version = managed.Version
+	}
+	if dep.Scope == "" {
+		dep.Scope = managed.Scope
+	}
+	dep.Exclusions = append(dep.Exclusions, managed.Exclusions...)
+
+	return dep
+}
+
+func (e *effectivePom) interpolateDependency(dep gopom.Dependency) gopom.Dependency {
+	dep.GroupID = e.interpolate(dep.GroupID)
+	dep.ArtifactID = e.interpolate(dep.ArtifactID)
+	dep.Version = e.interpolate(dep.Version)
+	dep.Scope = e.interpolate(dep.Scope)
+	dep.Optional = e.interpolate(dep.Optional)
+
+	return dep
+}
+
+// interpolate replaces the ${...} references with the project properties,
+// references which can't be resolved are left untouched
+func (e *effectivePom) interpolate(value string) string {
+	for i := 0; i < maxInterpolationDepth && strings.Contains(value, "${"); i++ {
+		replaced := propertyReference.ReplaceAllStringFunc(value, func(ref string) string {
+			if v, ok := e.Properties[ref[2:len(ref)-1]]; ok {
+				return v
+			}
+			return ref
+		})
+		if replaced == value {
+			break
+		}
+		value = replaced
+	}
+
+	return strings.TrimSpace(value)
+}
+
+func isExcluded(exclusions []gopom.Exclusion, dep gopom.Dependency) bool {
+	for _, exclusion := range exclusions {
+		if (exclusion.GroupID == "*" || exclusion.GroupID == dep.GroupID) &&
+			(exclusion.ArtifactID == "*" || exclusion.ArtifactID == dep.ArtifactID) {
+			return true
+		}
+	}
+	return false
+}
+
+func defaultScope(scope string) string {
+	if scope == "" {
+		return scopeCompile
+	}
+	return scope
+}
+
+// transitiveScope applies the Maven scope propagation table
+// https://maven.apache.org/guides/introduction/introduction-to-dependency-mechanism.html#dependency-scope
+func transitiveScope(parent, child string) string {
+	switch parent {
+	case scopeCompile:
+		return child
+	case scopeRuntime:
+		if child == scopeCompile {
+			return scopeRuntime
+		}
+		return child
+	default:
+		return parent
+	}
+}
@@ -0,0 +1,346 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+const parentPom = `<project>
+  <groupId>org.example</groupId>
+  <artifactId>parent</artifactId>
+  <version>1.0.0</version>
+  <packaging>pom</packaging>
+  <properties>
+    <guava.version>31.1-jre</guava.version>
+  </properties>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>com.google.guava</groupId>
+        <artifactId>guava</artifactId>
+        <version>${guava.version}</version>
+      </dependency>
+      <dependency>
+        <groupId>org.example</groupId>
+        <artifactId>bom</artifactId>
+        <version>2.0.0</version>
+        <type>pom</type>
+        <scope>import</scope>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+</project>`
+
+const appPom = `<project>
+  <parent>
+    <groupId>org.example</groupId>
+    <artifactId>parent</artifactId>
+    <version>1.0.0</version>
+  </parent>
+  <artifactId>app</artifactId>
+  <dependencies>
+    <dependency>
+      <groupId>com.google.guava</groupId>
+      <artifactId>guava</artifactId>
+      <exclusions>
+        <exclusion>
+          <groupId>com.google.code.findbugs</groupId>
+          <artifactId>jsr305</artifactId>
+        </exclusion>
+      </exclusions>
+    </dependency>
+    <dependency>
+      <groupId>org.slf4j</groupId>
+      <artifactId>slf4j-api</artifactId>
+    </dependency>
+    <dependency>
+      <groupId>junit</groupId>
+      <artifactId>junit</artifactId>
+      <version>4.13.2</version>
+      <scope>test</scope>
+    </dependency>
+  </dependencies>
+</project>`
+
+const bomPom = `<project>
+  <groupId>org.example</groupId>
+  <artifactId>bom</artifactId>
+  <version>2.0.0</version>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>org.slf4j</groupId>
+        <artifactId>slf4j-api</artifactId>
+        <version>2.0.7</version>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+</project>`
+
+const guavaPom = `<project>
+  <groupId>com.google.guava</groupId>
+  <artifactId>guava</artifactId>
+  <version>31.1-jre</version>
+  <dependencies>
+    <dependency>
+      <groupId>com.google.guava</groupId>
+      <artifactId>failureaccess</artifactId>
+      <version>1.0.1</version>
+    </dependency>
+    <dependency>
+      <groupId>com.google.code.findbugs</groupId>
+      <artifactId>jsr305</artifactId>
+      <version>3.0.2</version>
+    </dependency>
+    <dependency>
+      <groupId>org.slf4j</groupId>
+      <artifactId>slf4j-api</artifactId>
+      <version>1.7.36</version>
+    </dependency>
+    <dependency>
+      <groupId>org.checkerframework</groupId>
+      <artifactId>checker-qual</artifactId>
+      <version>3.12.0</version>
+      <optional>true</optional>
+    </dependency>
+  </dependencies>
+</project>`
+
+func TestResolve(t *testing.T) {
+	project := t.TempDir()
+	repository := t.TempDir()
+
+	writeFile(t, filepath.Join(project, "pom.xml"), parentPom)
+	writeFile(t, filepath.Join(project, "app", "pom.xml"), appPom)
+	writeFile(t, filepath.Join(repository, "org", "example", "bom", "2.0.0", "bom-2.0.0.pom"), bomPom)
+	writeFile(t, filepath.Join(repository, "com", "google", "guava", "guava", "31.1-jre", "guava-31.1-jre.pom"), guavaPom)
+
+	r := &pomResolver{repository: repository, reactor: map[string]string{}, cache: map[string]*effectivePom{}, visiting: map[string]bool{}}
+	res, err := r.resolve(filepath.Join(project, "app"))
+	require.NoError(t, err)
+
+	assert.Equal(t, artifact{GroupID: "org.example", ArtifactID: "app", Version: "1.0.0"}, res.root)
+	assert.Equal(t, []artifact{
+		{GroupID: "com.google.guava", ArtifactID: "guava", Version: "31.1-jre", Scope: scopeCompile},
+		{GroupID: "org.slf4j", ArtifactID: "slf4j-api", Version: "2.0.7", Scope: scopeCompile},
+		{GroupID: "junit", ArtifactID: "junit", Version: "4.13.2", Scope: scopeTest},
+		{GroupID: "com.google.guava", ArtifactID: "failureaccess", Version: "1.0.1", Scope: scopeCompile},
+	}, res.artifacts)
+	assert.Equal(t, []string{"guava", "slf4j-api", "junit"}, res.graph["app"])
+	assert.Equal(t, []string{"failureaccess", "slf4j-api"}, res.graph["guava"])
+}
+
+// loopPom is its own parent, and imports bom-a which imports bom-b which imports bom-a
+const loopPom = `<project>
+  <parent>
+    <groupId>org.example</groupId>
+    <artifactId>loop</artifactId>
+    <version>1.0.0</version>
+    <relativePath>pom.xml</relativePath>
+  </parent>
+  <artifactId>loop</artifactId>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>org.example</groupId>
+        <artifactId>bom-a</artifactId>
+        <version>1.0.0</version>
+        <type>pom</type>
+        <scope>import</scope>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+  <dependencies>
+    <dependency>
+      <groupId>org.slf4j</groupId>
+      <artifactId>slf4j-api</artifactId>
+    </dependency>
+    <dependency>
+      <groupId>junit</groupId>
+      <artifactId>junit</artifactId>
+    </dependency>
+  </dependencies>
+</project>`
+
+func importingBom(artifactID, imported, groupID, managed, version string) string {
+	return `<project>
+  <groupId>org.example</groupId>
+  <artifactId>` + artifactID + `</artifactId>
+  <version>1.0.0</version>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>org.example</groupId>
+        <artifactId>` + imported + `</artifactId>
+        <version>1.0.0</version>
+        <type>pom</type>
+        <scope>import</scope>
+      </dependency>
+      <dependency>
+        <groupId>` + groupID + `</groupId>
+        <artifactId>` + managed + `</artifactId>
+        <version>` + version + `</version>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+</project>`
+}
+
+func TestResolveCycles(t *testing.T) {
+	project := t.TempDir()
+	repository := t.TempDir()
+
+	writeFile(t, filepath.Join(project, "pom.xml"), loopPom)
+	writeFile(t, filepath.Join(repository, "org", "example", "bom-a", "1.0.0", "bom-a-1.0.0.pom"), importingBom("bom-a", "bom-b", "junit", "junit", "4.13.2"))
+	writeFile(t, filepath.Join(repository, "org", "example", "bom-b", "1.0.0", "bom-b-1.0.0.pom"), importingBom("bom-b", "bom-a", "org.slf4j", "slf4j-api", "2.0.7"))
+
+	// the cycles are broken where they close, the rest of the POMs being resolved
+	r := newPomResolver("")
+	r.repository = repository
+	res, err := r.resolve(project)
+	require.NoError(t, err)
+	assert.Equal(t, []artifact{
+		{GroupID: "org.slf4j", ArtifactID: "slf4j-api", Version: "2.0.7", Scope: scopeCompile},
+		{GroupID: "junit", ArtifactID: "junit", Version: "4.13.2", Scope: scopeCompile},
+	}, res.artifacts)
+	assert.Empty(t, r.visiting)
+
+	// a POM made effective again while it is being made effective is an error
+	r = newPomResolver("")
+	r.repository = repository
+	r.visiting["org.example:bom-b:1.0.0"] = true
+	_, err = r.load("org.example", "bom-b", "1.0.0")
+	assert.ErrorIs(t, err, errPomCycle)
+}
+
+func TestParseDependencyList(t *testing.T) {
+	artifacts := parseDependencyList([]string{
+		"   org.slf4j:slf4j-api:jar:2.0.7:compile -- module org.slf4j",
+		"   junit:junit:jar:4.13.2:test",
+		"",
+		"Finished at: 2023-06-27T20:29:07Z",
+	})
+
+	assert.Equal(t, []artifact{
+		{GroupID: "org.slf4j", ArtifactID: "slf4j-api", Version: "2.0.7", Scope: scopeCompile},
+		{GroupID: "junit", ArtifactID: "junit", Version: "4.13.2", Scope: scopeTest},
+	}, artifacts)
+}
+
+func writeFile(t *testing.T, path, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+const aggregatorPom = `<project>
+  <groupId>org.example</groupId>
+  <artifactId>aggregator</artifactId>
+  <version>1.0.0</version>
+  <packaging>pom</packaging>
+  <modules>
+    <module>core</module>
+    <module>web</module>
+  </modules>
+</project>`
+
+const corePom = `<project>
+  <parent>
+    <groupId>org.example</groupId>
+    <artifactId>aggregator</artifactId>
+    <version>1.0.0</version>
+  </parent>
+  <artifactId>core</artifactId>
+</project>`
+
+const webPom = `<project>
+  <parent>
+    <groupId>org.example</groupId>
+    <artifactId>aggregator</artifactId>
+    <version>1.0.0</version>
+  </parent>
+  <artifactId>web</artifactId>
+  <packaging>war</packaging>
+  <dependencies>
+    <dependency>
+      <groupId>org.example</groupId>
+      <artifactId>core</artifactId>
+      <version>${project.version}</version>
+    </dependency>
+  </dependencies>
+</project>`
+
+func TestReactorModules(t *testing.T) {
+	project := t.TempDir()
+	writeFile(t, filepath.Join(project, "pom.xml"), aggregatorPom)
+	writeFile(t, filepath.Join(project, "core", "pom.xml"), corePom)
+	writeFile(t, filepath.Join(project, "web", "pom.xml"), webPom)
+
+	root, err := readAndLoadPomFile(project)
+	require.NoError(t, err)
+	reactor := readReactor(project, root)
+	require.Len(t, reactor, 2)
+
+	modules := []models.Module{convertProjectLevelPackageToModule(root)}
+	modules[0].LocalPath = project
+	for _, reactorProject := range reactor {
+		modules = append(modules, convertPkgModulesToModule(modules, reactorProject, root, reactor)...)
+	}
+	linkReactorModules(modules, reactor)
+	require.Len(t, modules, 3)
+
+	assert.Equal(t, models.Contains, modules[0].Modules["core"].GetRelationship())
+	assert.Equal(t, models.Contains, modules[0].Modules["web"].GetRelationship())
+	assert.Equal(t, models.DependsOn, modules[2].Modules["core"].GetRelationship())
+	assert.Len(t, modules[1].Modules, 0)
+	assert.True(t, modules[2].Deployable)
+}
+
+const guavaAppPom = `<project>
+  <groupId>org.example</groupId>
+  <artifactId>app</artifactId>
+  <version>1.0.0</version>
+  <dependencies>
+    <dependency>
+      <groupId>com.google.guava</groupId>
+      <artifactId>guava</artifactId>
+      <version>31.1-jre</version>
+    </dependency>
+  </dependencies>
+</project>`
+
+func TestListModulesWithGlobalSettings(t *testing.T) {
+	if hasMaven() {
+		t.Skip("the built-in POM resolver is used when mvn isn't installed")
+	}
+	// the repository is the one of the global settings, not ~/.m2/repository
+	t.Setenv("HOME", t.TempDir())
+	project := t.TempDir()
+	repository := t.TempDir()
+	settings := filepath.Join(t.TempDir(), "settings.xml")
+
+	writeFile(t, filepath.Join(project, "pom.xml"), guavaAppPom)
+	writeFile(t, filepath.Join(repository, "com", "google", "guava", "guava", "31.1-jre", "guava-31.1-jre.pom"), guavaPom)
+	writeFile(t, settings, "<settings><localRepository>"+repository+"</localRepository></settings>")
+
+	modules, err := New().ListModulesWithDeps(project, settings)
+	require.NoError(t, err)
+
+	names := []string{}
+	for _, module := range modules {
+		names = append(names, module.Name)
+	}
+	assert.Equal(t, []string{"app", "guava", "failureaccess", "jsr305", "slf4j-api"}, names)
+
+	// the graph is the one of the same resolution
+	assert.Contains(t, modules[0].Modules, "guava")
+	assert.Contains(t, modules[1].Modules, "failureaccess")
+	assert.Contains(t, modules[1].Modules, "slf4j-api")
+}
@@ -4,11 +4,14 @@ package modules
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/spdx/spdx-sbom-generator/pkg/modules/javagradle"
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
 	"github.com/spdx/spdx-sbom-generator/pkg/models"
 	"github.com/spdx/spdx-sbom-generator/pkg/modules/cargo"
 	"github.com/spdx/spdx-sbom-generator/pkg/modules/composer"
@@ -30,6 +33,10 @@ var (
 
 var registeredPlugins []models.IPlugin
 
+// pluginSlugs are the slugs of the registered plugins, the pip plugin being the one of pipenv, poetry
+// or pyenv depending on the project
+var pluginSlugs = []string{"cargo", "composer", "go-mod", "bundler", "npm", "Java-Gradle", "Java-Maven", "nuget", "yarn", "pipenv", "poetry", "pyenv", "swift"}
+
 func init() {
 	registeredPlugins = append(registeredPlugins,
 		cargo.New(),
@@ -46,6 +53,40 @@ func init() {
 	)
 }
 
+// RegisterPlugins adds the plugins to the built-in ones, as the external plugins, unless a plugin
+// has their slug already
+func RegisterPlugins(plugins ...models.IPlugin) {
+	for _, plugin := range plugins {
+		slug := plugin.GetMetadata().Slug
+		if pluginSlug(slug) != "" {
+			log.Warnf("Skipping the %s plugin, a plugin has the slug already", slug)
+			continue
+		}
+		registeredPlugins = append(registeredPlugins, plugin)
+		pluginSlugs = append(pluginSlugs, slug)
+	}
+}
+
+// CheckPluginSlugs checks that the names are slugs of the registered plugins, regardless of the case and the dashes
+func CheckPluginSlugs(names []string) error {
+	for _, name := range names {
+		if pluginSlug(name) == "" {
+			return fmt.Errorf("unknown plugin %q, expected one of %s", name, strings.Join(pluginSlugs, ", "))
+		}
+	}
+	return nil
+}
+
+// pluginSlug returns the slug of the registered plugins the name is, empty if none
+func pluginSlug(name string) string {
+	for _, slug := range pluginSlugs {
+		if helper.SameSlug(name, slug) {
+			return slug
+		}
+	}
+	return ""
+}
+
 // Manager ...
 type Manager struct {
 	Config  Config
@@ -55,8 +96,37 @@ type Manager struct {
 
 // Config ...
 type Config struct {
-	Path              string
-	GlobalSettingFile string
+	Path                 string
+	GlobalSettingFile    string
+	GradleConfigurations []string
+	GoBuild              gomod.Build
+	// EnablePlugins are the slugs of the only plugins run, all of them if empty, and DisablePlugins
+	// the slugs of the plugins not run
+	EnablePlugins  []string
+	DisablePlugins []string
+}
+
+// pluginEnabled tells whether the plugin of the slug runs
+func (c *Config) pluginEnabled(slug string) bool {
+	contains := func(names []string) bool {
+		for _, name := range names {
+			if helper.SameSlug(name, slug) {
+				return true
+			}
+		}
+		return false
+	}
+	return (len(c.EnablePlugins) == 0 || contains(c.EnablePlugins)) && !contains(c.DisablePlugins)
+}
+
+// configurationSelector is implemented by plugins listing the dependencies of selected configurations only
+type configurationSelector interface {
+	SetConfigurations(configurations []string)
+}
+
+// buildSelector is implemented by plugins listing the modules compiled into a selected build only
+type buildSelector interface {
+	SetBuild(build gomod.Build)
 }
 
 // New ...
@@ -65,9 +135,20 @@ func New(cfg Config) ([]*Manager, error) {
 	var managerSlice []*Manager
 	for _, plugin := range registeredPlugins {
 		if plugin.IsValid(cfg.Path) {
+			// the slug of the pip plugin is known once valid
+			if slug := plugin.GetMetadata().Slug; !cfg.pluginEnabled(slug) {
+				log.Infof("Skipping the %s plugin, disabled by the options", slug)
+				continue
+			}
 			if err := plugin.SetRootModule(cfg.Path); err != nil {
 				return nil, err
 			}
+			if selector, ok := plugin.(configurationSelector); ok {
+				selector.SetConfigurations(cfg.GradleConfigurations)
+			}
+			if selector, ok := plugin.(buildSelector); ok {
+				selector.SetBuild(cfg.GoBuild)
+			}
 
 			usePlugin = plugin
 			if usePlugin == nil {
@@ -129,16 +129,11 @@ func (m *npm) GetRootModule(path string) (*models.Module, error) {
 	mod.Modules = map[string]*models.Module{}
 
 	mod.Copyright = getCopyright(path)
-	modLic, err := helper.GetLicenses(path)
+	detection, err := helper.DetectLicenses(path, helper.LicenseThreshold)
 	if err != nil {
 		return mod, nil
 	}
-	mod.LicenseDeclared = helper.BuildLicenseDeclared(modLic.ID)
-	mod.LicenseConcluded = helper.BuildLicenseConcluded(modLic.ID)
-	mod.CommentsLicense = modLic.Comments
-	if !helper.LicenseSPDXExists(modLic.ID) {
-		mod.OtherLicense = append(mod.OtherLicense, modLic)
-	}
+	helper.SetModuleLicenses(mod, detection)
 
 	return mod, nil
 }
@@ -255,17 +250,12 @@ func (m *npm) buildDependencies(path string, deps map[string]interface{}) ([]mod
 				}
 			}
 
-			modLic, err := helper.GetLicenses(filepath.Join(path, m.metadata.ModulePath[0], key))
+			detection, err := helper.DetectLicenses(filepath.Join(path, m.metadata.ModulePath[0], key), helper.LicenseThreshold)
 			if err != nil {
 				modules = append(modules, mod)
 				continue
 			}
-			mod.LicenseDeclared = helper.BuildLicenseDeclared(modLic.ID)
-			mod.LicenseConcluded = helper.BuildLicenseConcluded(modLic.ID)
-			mod.CommentsLicense = modLic.Comments
-			if !helper.LicenseSPDXExists(modLic.ID) {
-				mod.OtherLicense = append(mod.OtherLicense, modLic)
-			}
+			helper.SetModuleLicenses(&mod, detection)
 
 			modules = append(modules, mod)
 
@@ -316,8 +306,10 @@ func getPackageDependencies(modDeps map[string]interface{}, t string) map[string
 	for k, v := range modDeps {
 		name := strings.TrimPrefix(k, "@")
 		version := ""
+		scope := models.ScopeRuntime
 		if t == "dependencies" {
 			version = strings.TrimPrefix(v.(map[string]interface{})["version"].(string), "^")
+			scope = getPackageScope(v.(map[string]interface{}))
 		}
 		if t == "requires" {
 			version = strings.TrimPrefix(v.(string), "^")
@@ -326,11 +318,27 @@ func getPackageDependencies(modDeps map[string]interface{}, t string) map[string
 			Name:     name,
 			Version:  version,
 			CheckSum: &models.CheckSum{Content: []byte(fmt.Sprintf("%s-%s", name, version))},
+			Scope:    scope,
 		}
 	}
 	return m
 }
 
+// getPackageScope returns the scope of a lock file entry, which flags the
+// packages only required by dev, optional or peer dependencies. The devOptional
+// ones are only installed for development, as the optional dependencies of dev ones
+func getPackageScope(entry map[string]interface{}) models.Scope {
+	switch {
+	case entry["dev"] == true, entry["devOptional"] == true:
+		return models.ScopeDev
+	case entry["optional"] == true:
+		return models.ScopeOptional
+	case entry["peer"] == true:
+		return models.ScopeProvided
+	}
+	return models.ScopeRuntime
+}
+
 func getPackageHomepage(path string) string {
 	r := reader.New(path)
 	pkResult, err := r.ReadJson()
@@ -169,3 +169,11 @@ func getPath() string {
 
 	return path
 }
+
+func TestGetPackageScope(t *testing.T) {
+	assert.Equal(t, models.ScopeRuntime, getPackageScope(map[string]interface{}{"version": "1.0.0"}))
+	assert.Equal(t, models.ScopeDev, getPackageScope(map[string]interface{}{"version": "1.0.0", "dev": true}))
+	assert.Equal(t, models.ScopeDev, getPackageScope(map[string]interface{}{"version": "1.0.0", "devOptional": true}))
+	assert.Equal(t, models.ScopeOptional, getPackageScope(map[string]interface{}{"version": "1.0.0", "optional": true}))
+	assert.Equal(t, models.ScopeProvided, getPackageScope(map[string]interface{}{"version": "1.0.0", "peer": true}))
+}
@@ -5,6 +5,7 @@ package nuget
 import (
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -13,6 +14,7 @@ import (
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/spdx/spdx-sbom-generator/pkg/cache"
 	"github.com/spdx/spdx-sbom-generator/pkg/helper"
 	"github.com/spdx/spdx-sbom-generator/pkg/models"
 )
@@ -450,19 +452,9 @@ func getNugetSpec(name string, version string) (*NugetSpec, error) {
 	}
 	nugetUrlPrefix := fmt.Sprintf("%s%s/%s/%s", nugetBaseUrl, name, version, name)
 	nuspecUrl := fmt.Sprintf("%s%s", nugetUrlPrefix, specExt)
-	resp, err := getHttpResponseWithHeaders(nuspecUrl, map[string]string{"content-type": "application/xml"})
-	if err != nil {
-		return nil, err
-	}
-
-	defer func() {
-		closeErr := resp.Body.Close()
-		if closeErr != nil {
-			log.Error(fmt.Sprintf("%#v", err))
-		}
-	}()
-
-	body, err := ioutil.ReadAll(resp.Body)
+	body, err := cache.Default.Fetch("nuget", nuspecUrl, cache.ArtifactTTL, func() ([]byte, error) {
+		return getHttpBody(nuspecUrl, map[string]string{"content-type": "application/xml"})
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -507,28 +499,28 @@ func getHashCheckSum(name string, version string) (*models.CheckSum, error) {
 	}
 	nugetUrlPrefix := fmt.Sprintf("%s%s/%s/%s", nugetBaseUrl, name, version, name)
 	nuPkgUrl := fmt.Sprintf("%s.%s%s", nugetUrlPrefix, version, pkgExt)
-	resp, err := getHttpResponseWithHeaders(nuPkgUrl, map[string]string{"content-type": "application/xml"})
-	if err != nil {
-		return nil, err
-	}
-	defer func() {
-		closeErr := resp.Body.Close()
-		if closeErr != nil {
-			log.Error(fmt.Sprintf("%#v", err))
+	// the digest of the package is cached rather than the package itself
+	digest, err := cache.Default.Fetch("nuget", "sha256 "+nuPkgUrl, cache.ArtifactTTL, func() ([]byte, error) {
+		body, err := getHttpBody(nuPkgUrl, map[string]string{"content-type": "application/xml"})
+		if err != nil {
+			return nil, err
 		}
-	}()
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if body != nil {
+		checkSum := models.CheckSum{Algorithm: models.HashAlgoSHA256, Content: body}
+		return []byte(checkSum.String()), nil
+	})
+	// the packages missing from nuget.org, as the ones of private feeds, have no checksum content
+	if errors.Is(err, errUnexpectedStatus) {
 		return &models.CheckSum{
 			Algorithm: models.HashAlgoSHA256,
-			Content:   fileData,
 		}, nil
 	}
-	return nil, nil
+	if err != nil {
+		return nil, err
+	}
+	return &models.CheckSum{
+		Algorithm: models.HashAlgoSHA256,
+		Value:     string(digest),
+	}, nil
 }
 
 // extractLicence from the licenceMetaData
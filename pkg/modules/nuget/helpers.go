@@ -3,7 +3,9 @@
 package nuget
 
 import (
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"strings"
 	"time"
@@ -11,7 +13,11 @@ import (
 	"github.com/go-git/go-git/v5"
 )
 
-func getHttpResponseWithHeaders(url string, headers map[string]string) (*http.Response, error) {
+var errUnexpectedStatus = errors.New("unexpected status")
+
+// getHttpBody returns the body of the response of the url, the responses other than 200 OK
+// being errUnexpectedStatus errors
+func getHttpBody(url string, headers map[string]string) ([]byte, error) {
 	var netClient = &http.Client{
 		Timeout: time.Second * 30,
 	}
@@ -28,8 +34,12 @@ func getHttpResponseWithHeaders(url string, headers map[string]string) (*http.Re
 	if err != nil {
 		return nil, err
 	}
+	defer response.Body.Close()
 
-	return response, err
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w %s of %s", errUnexpectedStatus, response.Status, url)
+	}
+	return ioutil.ReadAll(response.Body)
 }
 
 func buildRootPackageURL(localPath string) string {
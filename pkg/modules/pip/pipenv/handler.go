@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/manifest"
 	"github.com/spdx/spdx-sbom-generator/pkg/models"
 	"github.com/spdx/spdx-sbom-generator/pkg/modules/pip/worker"
 )
@@ -129,6 +130,7 @@ func (m *pipenv) ListModulesWithDeps(path string, globalSettingFile string) ([]m
 	if err := worker.BuildDependencyGraph(&m.allModules, &m.metainfo); err != nil {
 		return nil, err
 	}
+	worker.AddScopedDependencies(&m.allModules, manifest.Index(manifest.Pipfile(filepath.Join(path, manifestFile))))
 	return modules, err
 }
 
@@ -3,7 +3,6 @@
 package worker
 
 import (
-	"fmt"
 	"regexp"
 	"strings"
 
@@ -186,17 +185,10 @@ func (d *MetadataDecoder) BuildModule(metadata Metadata) models.Module {
 	}
 
 	// Prepare licenses
-	licensePkg, err := helper.GetLicenses(metadata.DistInfoPath)
+	detection, err := helper.DetectLicenses(metadata.DistInfoPath, helper.LicenseThreshold)
 	if err == nil {
-		module.LicenseDeclared = helper.BuildLicenseDeclared(licensePkg.ID)
-		module.LicenseConcluded = helper.BuildLicenseConcluded(licensePkg.ID)
-		module.Copyright = helper.GetCopyright(licensePkg.ExtractedText)
-		module.CommentsLicense = licensePkg.Comments
-		if !helper.LicenseSPDXExists(licensePkg.ID) {
-			licensePkg.ID = fmt.Sprintf("LicenseRef-%s", licensePkg.ID)
-			licensePkg.ExtractedText = fmt.Sprintf("<text>%s</text>", licensePkg.ExtractedText)
-			module.OtherLicense = append(module.OtherLicense, licensePkg)
-		}
+		helper.SetModuleLicenses(&module, detection)
+		module.Copyright = helper.GetCopyright(detection.Text())
 	}
 
 	// Prepare dependency module
@@ -263,3 +255,59 @@ func BuildDependencyGraph(modules *[]models.Module, pkgsMetadata *map[string]Met
 
 	return nil
 }
+
+// AddScopedDependencies lists the dependencies declared outside of the package metadata, such as dev
+// dependencies, in the Modules of the root module with their scope. The runtime ones are listed from
+// the package metadata already
+func AddScopedDependencies(modules *[]models.Module, scopes map[string]models.Scope) {
+	rootIndex := -1
+	moduleMap := map[string]models.Module{}
+	for i, module := range *modules {
+		if module.Root {
+			rootIndex = i
+		}
+		moduleMap[normalizePackageName(module.Name)] = module
+	}
+	if rootIndex < 0 {
+		return
+	}
+
+	root := (*modules)[rootIndex]
+	for name, scope := range scopes {
+		if scope == models.ScopeRuntime {
+			continue
+		}
+		depModule, ok := moduleMap[normalizePackageName(name)]
+		if !ok || depModule.Root {
+			continue
+		}
+		// packages required by the root package itself keep their runtime scope
+		if _, ok := root.Modules[depModule.Name]; ok {
+			continue
+		}
+		root.Modules[depModule.Name] = &models.Module{
+			Version:          depModule.Version,
+			Name:             depModule.Name,
+			Path:             depModule.Path,
+			LocalPath:        depModule.LocalPath,
+			Supplier:         depModule.Supplier,
+			PackageURL:       depModule.PackageURL,
+			CheckSum:         depModule.CheckSum,
+			PackageHomePage:  depModule.PackageHomePage,
+			LicenseConcluded: depModule.LicenseConcluded,
+			LicenseDeclared:  depModule.LicenseDeclared,
+			CommentsLicense:  depModule.CommentsLicense,
+			OtherLicense:     depModule.OtherLicense,
+			Copyright:        depModule.Copyright,
+			PackageComment:   depModule.PackageComment,
+			Scope:            scope,
+		}
+	}
+}
+
+var packageNameSeparators = regexp.MustCompile(`[-_.]+`)
+
+// normalizePackageName returns the normalized form of a package name, as defined by PEP 503
+func normalizePackageName(name string) string {
+	return packageNameSeparators.ReplaceAllString(strings.ToLower(name), "-")
+}
@@ -10,6 +10,7 @@ import (
 	"reflect"
 	"strings"
 
+	"github.com/spdx/spdx-sbom-generator/pkg/cache"
 	"github.com/spdx/spdx-sbom-generator/pkg/models"
 )
 
@@ -81,7 +82,7 @@ var HashAlgoPickOrder []models.HashAlgorithm = []models.HashAlgorithm{
 	models.HashAlgoMD2,
 }
 
-func makeGetRequest(packageJsonUrl string) (*http.Response, error) {
+func makeGetRequest(packageJsonUrl string) ([]byte, error) {
 	url := "https://" + packageJsonUrl
 
 	request, _ := http.NewRequest("GET", url, nil)
@@ -92,24 +93,24 @@ func makeGetRequest(packageJsonUrl string) (*http.Response, error) {
 	if err != nil {
 		return nil, err
 	}
+	defer response.Body.Close()
 
 	if response.StatusCode != http.StatusOK {
 		return nil, errorPypiCouldNotFetchPkgData
 	}
 
-	return response, err
+	return ioutil.ReadAll(response.Body)
 }
 
 func GetPackageDataFromPyPi(packageJsonUrl string) (PypiPackageData, error) {
 	packageInfo := PypiPackageData{}
 
-	response, err := makeGetRequest(packageJsonUrl)
+	jsondata, err := cache.Default.Fetch("pypi", packageJsonUrl, cache.MetadataTTL, func() ([]byte, error) {
+		return makeGetRequest(packageJsonUrl)
+	})
 	if err != nil {
 		return packageInfo, err
 	}
-	defer response.Body.Close()
-
-	jsondata, _ := ioutil.ReadAll(response.Body)
 
 	err = json.Unmarshal(jsondata, &packageInfo)
 	if err != nil {
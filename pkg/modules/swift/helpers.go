@@ -4,7 +4,6 @@ package swift
 
 import (
 	"bufio"
-	"fmt"
 	"os/exec"
 	"strings"
 
@@ -49,19 +48,13 @@ func (dep SwiftPackageDependency) Module() *models.Module {
 }
 
 func setLicense(mod *models.Module, path string) error {
-	licensePkg, err := helper.GetLicenses(path)
+	detection, err := helper.DetectLicenses(path, helper.LicenseThreshold)
 	if err != nil {
 		return err
 	}
 
-	mod.LicenseDeclared = helper.BuildLicenseDeclared(licensePkg.ID)
-	mod.LicenseConcluded = helper.BuildLicenseConcluded(licensePkg.ID)
-	if !helper.LicenseSPDXExists(licensePkg.ID) {
-		licensePkg.ID = fmt.Sprintf("LicenseRef-%s", licensePkg.ID)
-		mod.OtherLicense = append(mod.OtherLicense, licensePkg)
-	}
-	mod.Copyright = helper.GetCopyright(licensePkg.ExtractedText)
-	mod.CommentsLicense = licensePkg.Comments
+	helper.SetModuleLicenses(mod, detection)
+	mod.Copyright = helper.GetCopyright(detection.Text())
 
 	return nil
 }
@@ -0,0 +1,410 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yarn
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+const (
+	berryMetadataKey  = "__metadata"
+	pnpFile           = ".pnp.cjs"
+	berryCacheFolder  = ".yarn/cache"
+	berryGlobalCache  = ".yarn/berry/cache"
+	protocolNpm       = "npm:"
+	protocolWorkspace = "workspace:"
+	protocolPatch     = "patch:"
+)
+
+var (
+	pnpPackageName     = regexp.MustCompile(`^\s*\["((?:@[^"/]+/)?[^"@]+)",\s*\[\\?$`)
+	pnpPackageRef      = regexp.MustCompile(`^\s*\["([^"]+)",\s*\{\\?$`)
+	pnpPackageLocation = regexp.MustCompile(`"packageLocation":\s*"([^"]+)"`)
+)
+
+// berryPackage is a single entry of a Yarn Berry (v2+) lockfile. Its checksum is the
+// one of the archive Yarn repacks in its cache, not of the tarball of the registry,
+// so it isn't reported as the checksum of the package
+type berryPackage struct {
+	Version      string            `yaml:"version"`
+	Resolution   string            `yaml:"resolution"`
+	Dependencies map[string]string `yaml:"dependencies"`
+	Checksum     string            `yaml:"checksum"`
+	LanguageName string            `yaml:"languageName"`
+	LinkType     string            `yaml:"linkType"`
+}
+
+// berryLockFile indexes the lockfile entries by every descriptor that resolves to them
+type berryLockFile struct {
+	packages    []*berryPackage
+	descriptors map[string]*berryPackage
+}
+
+// isBerryLockFile checks whether the lockfile was written by Yarn 2 or newer,
+// which, unlike Yarn 1, is YAML and begins with a __metadata entry
+func isBerryLockFile(path string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if strings.HasPrefix(scanner.Text(), berryMetadataKey+":") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func readBerryLockFile(path string) (*berryLockFile, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := map[string]*berryPackage{}
+	if err := yaml.Unmarshal(content, &entries); err != nil {
+		return nil, err
+	}
+
+	lock := &berryLockFile{descriptors: map[string]*berryPackage{}}
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		if key != berryMetadataKey {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		pkg := entries[key]
+		lock.packages = append(lock.packages, pkg)
+		for _, descriptor := range strings.Split(key, ",") {
+			lock.descriptors[strings.TrimSpace(descriptor)] = pkg
+		}
+	}
+
+	return lock, nil
+}
+
+// resolve returns the lockfile entry a dependency range was locked to. Yarn 3
+// omits the default npm: protocol from dependency ranges while Yarn 4 keeps it
+func (l *berryLockFile) resolve(name, rng string) *berryPackage {
+	if pkg, ok := l.descriptors[fmt.Sprintf("%s@%s", name, rng)]; ok {
+		return pkg
+	}
+
+	return l.descriptors[fmt.Sprintf("%s@%s%s", name, protocolNpm, rng)]
+}
+
+// root returns the entry of the top level workspace
+func (l *berryLockFile) root() *berryPackage {
+	for _, pkg := range l.packages {
+		if _, reference := splitDescriptor(pkg.Resolution); reference == protocolWorkspace+"." {
+			return pkg
+		}
+	}
+
+	return nil
+}
+
+// splitDescriptor splits a locator such as `@babel/core@npm:7.22.5` into
+// its name and reference
+func splitDescriptor(descriptor string) (string, string) {
+	if len(descriptor) == 0 {
+		return "", ""
+	}
+	i := strings.Index(descriptor[1:], "@")
+	if i < 0 {
+		return descriptor, ""
+	}
+
+	return descriptor[:i+1], descriptor[i+2:]
+}
+
+// patchedReference returns the reference of the package a patch: locator applies
+// to, along with the source of the patch
+// e.g. patch:resolve@npm%3A1.22.1#~builtin<compat/resolve>::version=1.22.1&hash=07638b
+func patchedReference(reference string) (string, string) {
+	patch := strings.TrimPrefix(reference, protocolPatch)
+	source := ""
+	if i := strings.Index(patch, "#"); i >= 0 {
+		patch, source = patch[:i], patch[i+1:]
+	}
+	if i := strings.Index(source, "::"); i >= 0 {
+		source = source[:i]
+	}
+
+	if unescaped, err := url.QueryUnescape(patch); err == nil {
+		patch = unescaped
+	}
+	_, inner := splitDescriptor(patch)
+
+	return inner, source
+}
+
+// readPnpLocations maps every `name@reference` locator declared in the Plug'n'Play
+// runtime state to the location of its files
+func readPnpLocations(path string) map[string]string {
+	locations := map[string]string{}
+	file, err := os.Open(path)
+	if err != nil {
+		return locations
+	}
+	defer file.Close()
+
+	var name, reference string
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		text := scanner.Text()
+		if match := pnpPackageName.FindStringSubmatch(text); match != nil {
+			name = match[1]
+			continue
+		}
+		if match := pnpPackageRef.FindStringSubmatch(text); match != nil {
+			reference = match[1]
+			continue
+		}
+		if match := pnpPackageLocation.FindStringSubmatch(text); match != nil && name != "" {
+			locations[fmt.Sprintf("%s@%s", name, reference)] = match[1]
+		}
+	}
+
+	return locations
+}
+
+// berryPackageFS opens the files of a package which can either live in an unpacked
+// folder or within a zip archive of the Yarn cache
+func berryPackageFS(path, location string) (fs.FS, error) {
+	if i := strings.Index(location, ".zip/"); i >= 0 {
+		archive := location[:i+len(".zip")]
+		if !filepath.IsAbs(archive) {
+			archive = filepath.Join(path, archive)
+		}
+
+		return zipPackageFS(archive, location[i+len(".zip/"):])
+	}
+
+	if !filepath.IsAbs(location) {
+		location = filepath.Join(path, location)
+	}
+	if !helper.Exists(location) {
+		return nil, fs.ErrNotExist
+	}
+
+	return os.DirFS(location), nil
+}
+
+func zipPackageFS(archive, folder string) (fs.FS, error) {
+	content, err := os.ReadFile(archive)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.Sub(r, strings.TrimSuffix(folder, "/"))
+}
+
+// locateBerryPackage finds the files of a locked package, looking at node_modules
+// when the node-modules linker is used, then at the Plug'n'Play runtime state and
+// finally at the project and global caches
+func (m *yarn) locateBerryPackage(path, name, reference string, pnp map[string]string) (fs.FS, error) {
+	if strings.HasPrefix(reference, protocolWorkspace) {
+		return berryPackageFS(path, strings.TrimPrefix(reference, protocolWorkspace))
+	}
+
+	if fsys, err := berryPackageFS(path, filepath.Join(m.metadata.ModulePath[0], name)); err == nil {
+		return fsys, nil
+	}
+
+	if location, ok := pnp[fmt.Sprintf("%s@%s", name, reference)]; ok {
+		return berryPackageFS(path, location)
+	}
+
+	if strings.HasPrefix(reference, protocolPatch) {
+		reference, _ = patchedReference(reference)
+	}
+	if !strings.HasPrefix(reference, protocolNpm) {
+		return nil, fs.ErrNotExist
+	}
+
+	slug := strings.ReplaceAll(strings.TrimPrefix(name, "@"), "/", "-")
+	pattern := fmt.Sprintf("%s-npm-%s-*.zip", slug, strings.TrimPrefix(reference, protocolNpm))
+	folders := []string{filepath.Join(path, berryCacheFolder)}
+	if home, err := os.UserHomeDir(); err == nil {
+		folders = append(folders, filepath.Join(home, berryGlobalCache))
+	}
+	for _, folder := range folders {
+		archives, err := filepath.Glob(filepath.Join(folder, pattern))
+		if err != nil || len(archives) == 0 {
+			continue
+		}
+
+		return zipPackageFS(archives[0], filepath.ToSlash(filepath.Join("node_modules", name)))
+	}
+
+	return nil, fs.ErrNotExist
+}
+
+// buildBerryDependencies converts the Yarn Berry lockfile into modules. Unlike
+// the classic lockfile, every dependency range is locked to a single entry,
+// so the dependency graph is kept rather than flattened under the root module
+func (m *yarn) buildBerryDependencies(path string, lock *berryLockFile) ([]models.Module, error) {
+	modules := make([]models.Module, 0)
+	de, err := m.GetRootModule(path)
+	if err != nil {
+		return modules, err
+	}
+	h := fmt.Sprintf("%x", sha256.Sum256([]byte(fmt.Sprintf("%s-%s", de.Name, de.Version))))
+	de.CheckSum = &models.CheckSum{
+		Algorithm: "SHA256",
+		Value:     h,
+	}
+	de.Supplier.Name = de.Name
+	if de.PackageDownloadLocation == "" {
+		de.PackageDownloadLocation = de.Name
+	}
+
+	root := lock.root()
+	if root != nil {
+		// the lockfile merges the dev dependencies of workspaces into their dependencies
+		de.Modules = lock.dependencyModules(root)
+		scopes := m.readManifestScopes(path)
+		for name, dep := range de.Modules {
+			dep.Scope = scopes[name]
+		}
+	}
+	modules = append(modules, *de)
+
+	pnp := readPnpLocations(filepath.Join(path, pnpFile))
+	for _, pkg := range lock.packages {
+		if pkg == root {
+			continue
+		}
+
+		name, reference := splitDescriptor(pkg.Resolution)
+		var mod models.Module
+		mod.Name = name
+		mod.Version = pkg.Version
+		mod.Supplier.Name = mod.Name
+		mod.Modules = lock.dependencyModules(pkg)
+
+		switch {
+		case strings.HasPrefix(reference, protocolWorkspace):
+			mod.LocalPath = filepath.Join(path, strings.TrimPrefix(reference, protocolWorkspace))
+		case strings.HasPrefix(reference, protocolPatch):
+			inner, source := patchedReference(reference)
+			mod.PackageDownloadLocation = berryDownloadLocation(name, inner, pkg.Version)
+			mod.PackageComment = fmt.Sprintf("patched with %s", source)
+		default:
+			mod.PackageDownloadLocation = berryDownloadLocation(name, reference, pkg.Version)
+		}
+
+		fsys, err := m.locateBerryPackage(path, name, reference, pnp)
+		if err != nil {
+			modules = append(modules, mod)
+			continue
+		}
+		mod.PackageURL = getBerryPackageHomepage(fsys)
+		mod.Copyright = getBerryCopyright(fsys)
+
+		detection, err := helper.DetectLicensesFromFS(fsys, helper.LicenseThreshold)
+		if err != nil {
+			modules = append(modules, mod)
+			continue
+		}
+		helper.SetModuleLicenses(&mod, detection)
+		modules = append(modules, mod)
+	}
+
+	return modules, nil
+}
+
+// dependencyModules returns the locked dependencies of a lockfile entry
+func (l *berryLockFile) dependencyModules(pkg *berryPackage) map[string]*models.Module {
+	deps := map[string]*models.Module{}
+	for name, rng := range pkg.Dependencies {
+		dep := l.resolve(name, rng)
+		if dep == nil {
+			continue
+		}
+		deps[name] = &models.Module{
+			Name:     name,
+			Version:  dep.Version,
+			CheckSum: &models.CheckSum{Content: []byte(fmt.Sprintf("%s-%s", name, dep.Version))},
+		}
+	}
+
+	return deps
+}
+
+func berryDownloadLocation(name, reference, version string) string {
+	if strings.HasPrefix(reference, protocolNpm) {
+		base := name[strings.LastIndex(name, "/")+1:]
+		return fmt.Sprintf("%s/%s/-/%s-%s.tgz", yarnRegistry, name, base, version)
+	}
+
+	if i := strings.Index(reference, "#"); i > 0 {
+		reference = reference[:i]
+	}
+	if rg.MatchString(reference) {
+		return reference
+	}
+
+	return fmt.Sprintf("https://www.yarnpkg.com/package/%s", name)
+}
+
+func getBerryPackageHomepage(fsys fs.FS) string {
+	content, err := fs.ReadFile(fsys, "package.json")
+	if err != nil {
+		return ""
+	}
+
+	var manifest struct {
+		Homepage string `json:"homepage"`
+	}
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return ""
+	}
+
+	return helper.RemoveURLProtocol(manifest.Homepage)
+}
+
+func getBerryCopyright(fsys fs.FS) string {
+	licenses, err := fs.Glob(fsys, "LICENSE*")
+	if err != nil || len(licenses) == 0 {
+		return ""
+	}
+
+	content, err := fs.ReadFile(fsys, licenses[0])
+	if err != nil {
+		return ""
+	}
+
+	return helper.GetCopyright(string(content))
+}
@@ -0,0 +1,184 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yarn
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+const berryLockFixture = `# This file is generated by running "yarn install" inside your project.
+# Manual changes might be lost - proceed with caution!
+
+__metadata:
+  version: 6
+  cacheKey: 8
+
+"berry-app@workspace:.":
+  version: 0.0.0-use.local
+  resolution: "berry-app@workspace:."
+  dependencies:
+    "@scope/utils": "workspace:^"
+    loose-envify: ^1.4.0
+    resolve: "patch:resolve@npm%3A^1.22.1#~builtin<compat/resolve>"
+  languageName: unknown
+  linkType: soft
+
+"@scope/utils@workspace:^, @scope/utils@workspace:packages/utils":
+  version: 0.0.0-use.local
+  resolution: "@scope/utils@workspace:packages/utils"
+  languageName: unknown
+  linkType: soft
+
+"js-tokens@npm:^3.0.0 || ^4.0.0":
+  version: 4.0.0
+  resolution: "js-tokens@npm:4.0.0"
+  checksum: 8a95213a5a77deb6cbe94d86340e8d9ace2b93bc367790b260101d2f36a2eaf4e4e22d9fa9cf459b38af3a32fb4190e638024cf82ec95ef708680e405ea7
+  languageName: node
+  linkType: hard
+
+"loose-envify@npm:^1.4.0":
+  version: 1.4.0
+  resolution: "loose-envify@npm:1.4.0"
+  dependencies:
+    js-tokens: ^3.0.0 || ^4.0.0
+  bin:
+    loose-envify: cli.js
+  checksum: 6517e24e0cad87ec9888f500c5b5947032cdfe6ef65e1c1936a0c48a524b81e65542c9c3edc91c97d5bddc806ee2a985dbc79be89215d613b1de5db6d1cfe6f4
+  languageName: node
+  linkType: hard
+
+"resolve@patch:resolve@npm%3A^1.22.1#~builtin<compat/resolve>":
+  version: 1.22.1
+  resolution: "resolve@patch:resolve@npm%3A1.22.1#~builtin<compat/resolve>::version=1.22.1&hash=07638b"
+  checksum: 5656f4d0bedcf8eb52685c1abdf8fbe73a1603bb1160a24d716e27a57f6cecbe2432ff9c89c2bd57542c3a7b9d14b1882b73bfe2e9d7849c9a4c0b8b39f02b8b
+  languageName: node
+  linkType: hard
+`
+
+func TestIsBerryLockFile(t *testing.T) {
+	path := writeBerryProject(t)
+
+	assert.True(t, isBerryLockFile(filepath.Join(path, lockFile)))
+	assert.False(t, isBerryLockFile(filepath.Join(path, "package.json")))
+}
+
+func TestSplitDescriptor(t *testing.T) {
+	name, reference := splitDescriptor("@babel/core@npm:7.22.5")
+	assert.Equal(t, "@babel/core", name)
+	assert.Equal(t, "npm:7.22.5", reference)
+
+	name, reference = splitDescriptor("")
+	assert.Empty(t, name)
+	assert.Empty(t, reference)
+
+	reference, source := patchedReference("patch:resolve@npm%3A1.22.1#~builtin<compat/resolve>::version=1.22.1&hash=07638b")
+	assert.Equal(t, "npm:1.22.1", reference)
+	assert.Equal(t, "~builtin<compat/resolve>", source)
+}
+
+func TestListBerryModules(t *testing.T) {
+	path := writeBerryProject(t)
+	n := New()
+
+	assert.NoError(t, n.HasModulesInstalled(path))
+
+	mods, err := n.ListModulesWithDeps(path, "")
+	require.NoError(t, err)
+	require.Len(t, mods, 5)
+
+	root := mods[0]
+	assert.Equal(t, "berry-app", root.Name)
+	assert.Len(t, root.Modules, 3)
+	assert.Equal(t, "1.4.0", root.Modules["loose-envify"].Version)
+	assert.Equal(t, "1.22.1", root.Modules["resolve"].Version)
+	assert.Equal(t, models.ScopeRuntime, root.Modules["loose-envify"].Scope)
+	assert.Equal(t, models.ScopeDev, root.Modules["resolve"].Scope)
+
+	count := 0
+	for _, mod := range mods[1:] {
+		switch mod.Name {
+		case "@scope/utils":
+			assert.Equal(t, filepath.Join(path, "packages", "utils"), mod.LocalPath)
+			assert.Nil(t, mod.CheckSum)
+			count++
+		case "js-tokens":
+			assert.Equal(t, "4.0.0", mod.Version)
+			assert.Equal(t, "https://registry.yarnpkg.com/js-tokens/-/js-tokens-4.0.0.tgz", mod.PackageDownloadLocation)
+			assert.Equal(t, "github.com/lydell/js-tokens", mod.PackageURL)
+			assert.Equal(t, "Copyright (c) 2014, 2015, 2016, 2017, 2018 Simon Lydell", mod.Copyright)
+			assert.Equal(t, "MIT", mod.LicenseDeclared)
+			count++
+		case "loose-envify":
+			assert.Equal(t, "4.0.0", mod.Modules["js-tokens"].Version)
+			assert.Nil(t, mod.CheckSum)
+			count++
+		case "resolve":
+			assert.Equal(t, "https://registry.yarnpkg.com/resolve/-/resolve-1.22.1.tgz", mod.PackageDownloadLocation)
+			assert.Equal(t, "patched with ~builtin<compat/resolve>", mod.PackageComment)
+			count++
+		}
+	}
+
+	assert.Equal(t, 4, count)
+}
+
+// writeBerryProject lays out a Plug'n'Play project whose only cached package is js-tokens
+func writeBerryProject(t *testing.T) string {
+	path := t.TempDir()
+	manifest := `{"name": "berry-app", "version": "1.0.0", "packageManager": "yarn@3.6.1",
+		"dependencies": {"@scope/utils": "workspace:^", "loose-envify": "^1.4.0"}, "devDependencies": {"resolve": "^1.22.1"}}`
+	require.NoError(t, os.WriteFile(filepath.Join(path, "package.json"), []byte(manifest), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(path, lockFile), []byte(berryLockFixture), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(path, "packages", "utils"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(path, berryCacheFolder), 0755))
+
+	archive, err := os.Create(filepath.Join(path, berryCacheFolder, "js-tokens-npm-4.0.0-0ac852e9e2-8a95213a5a.zip"))
+	require.NoError(t, err)
+	defer archive.Close()
+
+	w := zip.NewWriter(archive)
+	files := map[string]string{
+		"node_modules/js-tokens/package.json": `{"name": "js-tokens", "version": "4.0.0", "homepage": "https://github.com/lydell/js-tokens"}`,
+		"node_modules/js-tokens/LICENSE":      mitLicense,
+	}
+	for name, content := range files {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+
+	return path
+}
+
+const mitLicense = `The MIT License (MIT)
+
+Copyright (c) 2014, 2015, 2016, 2017, 2018 Simon Lydell
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+`
@@ -14,6 +14,7 @@ import (
 	"strings"
 
 	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/manifest"
 	"github.com/spdx/spdx-sbom-generator/pkg/models"
 	"github.com/spdx/spdx-sbom-generator/pkg/reader"
 )
@@ -58,7 +59,18 @@ func (m *yarn) IsValid(path string) bool {
 }
 
 // HasModulesInstalled checks if modules of manifest file already installed
+// Yarn Berry projects running in Plug'n'Play mode have no node_modules folder,
+// the install state is kept in .pnp.cjs and the .yarn/cache archives instead
 func (m *yarn) HasModulesInstalled(path string) error {
+	if isBerryLockFile(filepath.Join(path, lockFile)) {
+		for _, p := range append([]string{pnpFile, berryCacheFolder}, m.metadata.ModulePath...) {
+			if helper.Exists(filepath.Join(path, p)) {
+				return nil
+			}
+		}
+		return errDependenciesNotFound
+	}
+
 	for _, p := range m.metadata.ModulePath {
 		if !helper.Exists(filepath.Join(path, p)) {
 			return errDependenciesNotFound
@@ -130,16 +142,11 @@ func (m *yarn) GetRootModule(path string) (*models.Module, error) {
 	}
 	mod.Modules = map[string]*models.Module{}
 	mod.Copyright = getCopyright(path)
-	modLic, err := helper.GetLicenses(path)
+	detection, err := helper.DetectLicenses(path, helper.LicenseThreshold)
 	if err != nil {
 		return mod, nil
 	}
-	mod.LicenseDeclared = helper.BuildLicenseDeclared(modLic.ID)
-	mod.LicenseConcluded = helper.BuildLicenseConcluded(modLic.ID)
-	mod.CommentsLicense = modLic.Comments
-	if !helper.LicenseSPDXExists(modLic.ID) {
-		mod.OtherLicense = append(mod.OtherLicense, modLic)
-	}
+	helper.SetModuleLicenses(mod, detection)
 	return mod, nil
 }
 
@@ -165,6 +172,15 @@ func (m *yarn) ListUsedModules(path string) ([]models.Module, error) {
 
 // ListModulesWithDeps return all info of installed modules
 func (m *yarn) ListModulesWithDeps(path string, globalSettingFile string) ([]models.Module, error) {
+	if isBerryLockFile(filepath.Join(path, lockFile)) {
+		lock, err := readBerryLockFile(filepath.Join(path, lockFile))
+		if err != nil {
+			return nil, err
+		}
+
+		return m.buildBerryDependencies(path, lock)
+	}
+
 	deps, err := readLockFile(filepath.Join(path, lockFile))
 	allDeps := appendNestedDependencies(deps)
 	if err != nil {
@@ -190,6 +206,15 @@ func (m *yarn) buildDependencies(path string, deps []dependency) ([]models.Modul
 		de.PackageDownloadLocation = de.Name
 	}
 	modules = append(modules, *de)
+
+	// every package is listed under the root module, so each one gets the
+	// scope of the direct dependencies it is required through
+	direct := map[string]models.Scope{}
+	for name, scope := range m.readManifestScopes(path) {
+		direct[strings.TrimPrefix(name, "@")] = scope
+	}
+	scopes := manifest.Propagate(direct, dependencyGraph(deps))
+
 	for _, d := range deps {
 		var mod models.Module
 		mod.Name = d.Name
@@ -198,18 +223,13 @@ func (m *yarn) buildDependencies(path string, deps []dependency) ([]models.Modul
 			Name:     d.Name,
 			Version:  mod.Version,
 			CheckSum: &models.CheckSum{Content: []byte(fmt.Sprintf("%s-%s", d.Name, mod.Version))},
+			Scope:    scopes[d.Name],
 		}
 		if len(d.Dependencies) != 0 {
 			mod.Modules = map[string]*models.Module{}
 			for _, depD := range d.Dependencies {
-				ar := strings.Split(strings.TrimSpace(depD), " ")
-				name := strings.TrimPrefix(strings.TrimSuffix(strings.TrimPrefix(ar[0], "\""), "\""), "@")
-				if name == "optionalDependencies:" {
-					continue
-				}
-
-				version := strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(ar[1]), "\""), "\"")
-				if extractVersion(version) == "*" {
+				name, version, ok := parseDependencyLine(depD)
+				if !ok {
 					continue
 				}
 				mod.Modules[name] = &models.Module{
@@ -243,17 +263,12 @@ func (m *yarn) buildDependencies(path string, deps []dependency) ([]models.Modul
 			mod.Copyright = helper.GetCopyright(s)
 		}
 
-		modLic, err := helper.GetLicenses(filepath.Join(path, m.metadata.ModulePath[0], d.PkPath))
+		detection, err := helper.DetectLicenses(filepath.Join(path, m.metadata.ModulePath[0], d.PkPath), helper.LicenseThreshold)
 		if err != nil {
 			modules = append(modules, mod)
 			continue
 		}
-		mod.LicenseDeclared = helper.BuildLicenseDeclared(modLic.ID)
-		mod.LicenseConcluded = helper.BuildLicenseConcluded(modLic.ID)
-		mod.CommentsLicense = modLic.Comments
-		if !helper.LicenseSPDXExists(modLic.ID) {
-			mod.OtherLicense = append(mod.OtherLicense, modLic)
-		}
+		helper.SetModuleLicenses(&mod, detection)
 		modules = append(modules, mod)
 	}
 	return modules, nil
@@ -385,14 +400,8 @@ func appendNestedDependencies(deps []dependency) []dependency {
 		allDeps = append(allDeps, d)
 		if len(d.Dependencies) > 0 {
 			for _, depD := range d.Dependencies {
-				ar := strings.Split(strings.TrimSpace(depD), " ")
-				name := strings.TrimPrefix(strings.TrimSuffix(strings.TrimPrefix(ar[0], "\""), "\""), "@")
-				if name == "optionalDependencies:" {
-					continue
-				}
-
-				version := strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(ar[1]), "\""), "\"")
-				if extractVersion(version) == "*" {
+				name, version, ok := parseDependencyLine(depD)
+				if !ok {
 					continue
 				}
 				allDeps = append(allDeps, dependency{Name: name, Version: extractVersion(version)})
@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yarn
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/manifest"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// readManifestScopes returns the scope of the direct dependencies declared in the package.json at path
+func (m *yarn) readManifestScopes(path string) map[string]models.Scope {
+	return manifest.Index(manifest.PackageJSON(filepath.Join(path, m.metadata.Manifest[0])))
+}
+
+// dependencyGraph returns the names of the dependencies of every package of a classic lockfile
+func dependencyGraph(deps []dependency) map[string][]string {
+	graph := map[string][]string{}
+	for _, d := range deps {
+		for _, depD := range d.Dependencies {
+			if name, _, ok := parseDependencyLine(depD); ok {
+				graph[d.Name] = append(graph[d.Name], name)
+			}
+		}
+	}
+	return graph
+}
+
+// parseDependencyLine returns the name and range of a dependency listed by a classic lockfile entry
+func parseDependencyLine(line string) (string, string, bool) {
+	ar := strings.Split(strings.TrimSpace(line), " ")
+	name := strings.TrimPrefix(strings.TrimSuffix(strings.TrimPrefix(ar[0], "\""), "\""), "@")
+	if name == "optionalDependencies:" || len(ar) < 2 {
+		return "", "", false
+	}
+
+	version := strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(ar[1]), "\""), "\"")
+	if extractVersion(version) == "*" {
+		return "", "", false
+	}
+	return name, version, true
+}
@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package notices renders the third-party notices of the packages of a project,
+// the attribution file listing their licenses, copyrights and license texts
+package notices
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/licenses"
+)
+
+const noAssertion = "NOASSERTION"
+
+// Notices are the third-party notices of a project, its packages grouped by license
+type Notices struct {
+	// Title names the project the notices are for
+	Title    string
+	Licenses []License
+}
+
+// License groups the packages concluding the same license expression with its texts
+type License struct {
+	// Expression is the license expression of the packages, NOASSERTION if not found
+	Expression string
+	// Name is the name of the license on the SPDX license list if the expression is a
+	// single license of the list, the expression otherwise
+	Name     string
+	Packages []Package
+	Texts    []Text
+}
+
+// Package is a third-party package of the project
+type Package struct {
+	Name             string
+	Version          string
+	HomePage         string
+	DownloadLocation string
+	License          string
+	Copyrights       []string
+	// Texts are the texts of the licenses of the package, from its license files
+	// or the SPDX license list
+	Texts []Text
+}
+
+// Text is the text of one of the licenses of an expression
+type Text struct {
+	License string
+	// Source is where the text comes from, the license file of a package or the SPDX license list
+	Source  string
+	Content string
+}
+
+// Build groups the packages by license expression, the packages whose license wasn't
+// found last, and merges the texts of the packages of every license
+func Build(title string, packages []Package) Notices {
+	groups := map[string]*License{}
+	for _, pkg := range packages {
+		expression := pkg.License
+		if expression == "" || expression == "NONE" {
+			expression = noAssertion
+		}
+		group, ok := groups[expression]
+		if !ok {
+			group = &License{Expression: expression, Name: licenseName(expression)}
+			groups[expression] = group
+		}
+		group.Packages = append(group.Packages, pkg)
+		group.Texts = mergeTexts(group.Texts, pkg.Texts)
+	}
+
+	notices := Notices{Title: title, Licenses: make([]License, 0, len(groups))}
+	for _, group := range groups {
+		sort.Slice(group.Packages, func(i, j int) bool {
+			if group.Packages[i].Name != group.Packages[j].Name {
+				return group.Packages[i].Name < group.Packages[j].Name
+			}
+			return group.Packages[i].Version < group.Packages[j].Version
+		})
+		notices.Licenses = append(notices.Licenses, *group)
+	}
+	sort.Slice(notices.Licenses, func(i, j int) bool {
+		a, b := notices.Licenses[i], notices.Licenses[j]
+		if (a.Expression == noAssertion) != (b.Expression == noAssertion) {
+			return b.Expression == noAssertion
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+	return notices
+}
+
+// licenseName names the license the expression is made of, the expression itself if several
+func licenseName(expression string) string {
+	if expression == noAssertion {
+		return "Unknown license"
+	}
+	if license, ok := licenses.Get(expression); ok {
+		return license.Name
+	}
+	return expression
+}
+
+// mergeTexts adds the texts to merged, once whatever the number of packages holding them
+func mergeTexts(merged, texts []Text) []Text {
+	for _, text := range texts {
+		found := false
+		for _, other := range merged {
+			if helper.SameLicenseText(other.Content, text.Content) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, text)
+		}
+	}
+	return merged
+}
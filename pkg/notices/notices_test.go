@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package notices
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testPackages = []Package{
+	{
+		Name:       "github.com/b/lib",
+		Version:    "v1.0.0",
+		HomePage:   "https://github.com/b/lib",
+		License:    "MIT",
+		Copyrights: []string{"Copyright (c) 2020 B | Co"},
+		Texts:      []Text{{License: "MIT", Source: "LICENSE of github.com/b/lib", Content: "MIT license\ntext"}},
+	},
+	{
+		Name:    "github.com/a/lib",
+		Version: "v2.0.0",
+		License: "MIT",
+		Texts:   []Text{{License: "MIT", Source: "LICENSE of github.com/a/lib", Content: "MIT license text\n"}},
+	},
+	{
+		Name:    "github.com/c/lib",
+		License: "NOASSERTION",
+	},
+	{
+		Name:    "github.com/d/lib",
+		License: "Apache-2.0 OR MIT",
+	},
+}
+
+func TestBuild(t *testing.T) {
+	notices := Build("project", testPackages)
+	require.Len(t, notices.Licenses, 3)
+
+	assert.Equal(t, "Apache-2.0 OR MIT", notices.Licenses[0].Name)
+	assert.Equal(t, "MIT License", notices.Licenses[1].Name)
+	assert.Equal(t, "Unknown license", notices.Licenses[2].Name)
+
+	mit := notices.Licenses[1]
+	require.Len(t, mit.Packages, 2)
+	assert.Equal(t, "github.com/a/lib", mit.Packages[0].Name)
+	// the texts differing only by their line wrapping are merged
+	assert.Len(t, mit.Texts, 1)
+}
+
+func TestRender(t *testing.T) {
+	notices := Build("project", testPackages)
+	for _, format := range []Format{FormatText, FormatMarkdown, FormatHTML} {
+		buffer := &bytes.Buffer{}
+		require.NoError(t, Render(buffer, notices, format, ""))
+		assert.Contains(t, buffer.String(), "github.com/b/lib")
+		assert.Contains(t, buffer.String(), "MIT license")
+	}
+
+	buffer := &bytes.Buffer{}
+	require.NoError(t, Render(buffer, notices, FormatMarkdown, ""))
+	assert.Contains(t, buffer.String(), `| [github.com/b/lib](https://github.com/b/lib) | v1.0.0 | Copyright (c) 2020 B \| Co |`)
+	assert.Contains(t, buffer.String(), "- [MIT License](#mit) (2)")
+
+	buffer.Reset()
+	require.NoError(t, Render(buffer, notices, FormatHTML, ""))
+	assert.Contains(t, buffer.String(), `<section id="apache-2-0-or-mit">`)
+}
+
+func TestRenderTemplateFile(t *testing.T) {
+	templateFile := filepath.Join(t.TempDir(), "notices.tmpl")
+	require.NoError(t, os.WriteFile(templateFile, []byte(`{{ range .Licenses }}{{ .Expression }}={{ len .Packages }};{{ end }}`), 0o644))
+
+	buffer := &bytes.Buffer{}
+	require.NoError(t, Render(buffer, Build("project", testPackages), FormatText, templateFile))
+	assert.Equal(t, "Apache-2.0 OR MIT=1;MIT=2;NOASSERTION=1;", buffer.String())
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("md")
+	require.NoError(t, err)
+	assert.Equal(t, FormatMarkdown, format)
+	assert.Equal(t, "THIRD_PARTY_NOTICES.md", format.Filename())
+	_, err = ParseFormat("pdf")
+	assert.Error(t, err)
+}
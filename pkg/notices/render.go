@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package notices
+
+import (
+	"embed"
+	"fmt"
+	htmlTemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates
+var templates embed.FS
+
+// Format is the format the notices are rendered in
+type Format int
+
+const (
+	FormatText Format = iota
+	FormatMarkdown
+	FormatHTML
+)
+
+// ParseFormat returns the format named text, markdown (md) or html
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "text", "txt":
+		return FormatText, nil
+	case "markdown", "md":
+		return FormatMarkdown, nil
+	case "html":
+		return FormatHTML, nil
+	default:
+		return FormatText, fmt.Errorf("unknown notices format %q, expected text, markdown or html", name)
+	}
+}
+
+// Filename returns the name of the notices file of the format
+func (f Format) Filename() string {
+	switch f {
+	case FormatMarkdown:
+		return "THIRD_PARTY_NOTICES.md"
+	case FormatHTML:
+		return "THIRD_PARTY_NOTICES.html"
+	default:
+		return "THIRD_PARTY_NOTICES"
+	}
+}
+
+// the default template of every format
+func (f Format) template() string {
+	switch f {
+	case FormatMarkdown:
+		return "templates/notices.md.tmpl"
+	case FormatHTML:
+		return "templates/notices.html.tmpl"
+	default:
+		return "templates/notices.txt.tmpl"
+	}
+}
+
+// the characters replaced in the anchors of the licenses
+var anchorReplacer = regexp.MustCompile(`[^a-z0-9]+`)
+
+var funcs = map[string]interface{}{
+	// anchor returns an identifier of the license for the links of the Markdown and HTML notices
+	"anchor": func(s string) string {
+		return strings.Trim(anchorReplacer.ReplaceAllString(strings.ToLower(s), "-"), "-")
+	},
+	// cell escapes the pipes of the cells of the Markdown tables
+	"cell": func(s string) string {
+		return strings.ReplaceAll(s, "|", `\|`)
+	},
+}
+
+// Render writes the notices in the format, with the template of the file templateFile
+// if any, the template embedded for the format otherwise
+func Render(w io.Writer, notices Notices, format Format, templateFile string) error {
+	name := format.template()
+	var content []byte
+	var err error
+	if templateFile != "" {
+		name = templateFile
+		content, err = os.ReadFile(templateFile)
+	} else {
+		content, err = templates.ReadFile(name)
+	}
+	if err != nil {
+		return fmt.Errorf("reading the notices template: %w", err)
+	}
+
+	// the HTML notices are escaped, unlike the others
+	if format == FormatHTML {
+		tmpl, err := htmlTemplate.New(filepath.Base(name)).Funcs(funcs).Parse(string(content))
+		if err != nil {
+			return fmt.Errorf("parsing the notices template %s: %w", name, err)
+		}
+		return tmpl.Execute(w, notices)
+	}
+	tmpl, err := template.New(filepath.Base(name)).Funcs(funcs).Parse(string(content))
+	if err != nil {
+		return fmt.Errorf("parsing the notices template %s: %w", name, err)
+	}
+	return tmpl.Execute(w, notices)
+}
@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package runner
+
+import (
+	"fmt"
+
+	"github.com/opensbom-generator/parsers/meta"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// limitDepth prunes the dependency graph to depth levels from the root
+// packages, depth 1 keeping the direct dependencies only. Packages the root
+// packages don't reach at all are left alone, and the packages kept lose their
+// dependencies on the pruned ones only. It reports whether anything was pruned
+func limitDepth(packages []meta.Package, depth int) ([]meta.Package, bool) {
+	if depth <= 0 {
+		return packages, false
+	}
+
+	find := packageFinder(packages)
+	kept := models.WithinDepth(packageRoots(packages), packageDependencies(packages, find), depth)
+
+	truncated := false
+	pruned := make([]meta.Package, 0, len(packages))
+	for i := range packages {
+		if !kept[i] {
+			truncated = true
+			continue
+		}
+		pkg := packages[i]
+		deps := make(map[string]*meta.Package, len(pkg.Packages))
+		for key, dep := range pkg.Packages {
+			if j, ok := find(dep); ok && !kept[j] {
+				continue
+			}
+			deps[key] = dep
+		}
+		if len(deps) != len(pkg.Packages) {
+			pkg.Packages = deps
+		}
+		pruned = append(pruned, pkg)
+	}
+	return pruned, truncated
+}
+
+// rootDistances returns the distance of each package from the closest root package, -1 when unreachable
+func rootDistances(packages []meta.Package) []int {
+	return models.RootDistances(packageRoots(packages), packageDependencies(packages, packageFinder(packages)))
+}
+
+func packageRoots(packages []meta.Package) []bool {
+	roots := make([]bool, len(packages))
+	for i := range packages {
+		roots[i] = packages[i].Root
+	}
+	return roots
+}
+
+// packageDependencies returns a function listing the indexes of the dependencies of a package
+func packageDependencies(packages []meta.Package, find func(*meta.Package) (int, bool)) func(int) []int {
+	return func(i int) []int {
+		deps := []int{}
+		for _, dep := range packages[i].Packages {
+			if j, ok := find(dep); ok {
+				deps = append(deps, j)
+			}
+		}
+		return deps
+	}
+}
+
+// packageFinder returns a function finding the index of a dependency in packages, by name and version,
+// or by name only when the version differs
+func packageFinder(packages []meta.Package) func(*meta.Package) (int, bool) {
+	index := map[string]int{}
+	for i := range packages {
+		index[packageKey(packages[i].Name, packages[i].Version)] = i
+		if _, ok := index[packages[i].Name]; !ok {
+			index[packages[i].Name] = i
+		}
+	}
+	return func(pkg *meta.Package) (int, bool) {
+		if i, ok := index[packageKey(pkg.Name, pkg.Version)]; ok {
+			return i, true
+		}
+		i, ok := index[pkg.Name]
+		return i, ok
+	}
+}
+
+// depthComment is the comment of the annotation recording that the document is truncated
+func depthComment(depth int) string {
+	return fmt.Sprintf("The dependencies of this document are truncated to %d level(s) from the root packages, it doesn't list the full dependency graph", depth)
+}
+
+func packageKey(name, version string) string {
+	return fmt.Sprintf("%s@%s", name, version)
+}
@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package runner
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/opensbom-generator/parsers/meta"
+	"github.com/stretchr/testify/assert"
+)
+
+// diamondPackages is a diamond, root depending on a and b which both depend on c, with a
+// depending on b too and c on d. u depends on d without any root reaching it
+func diamondPackages() []meta.Package {
+	d := &meta.Package{Name: "d", Version: "1.0.0"}
+	c := &meta.Package{Name: "c", Version: "1.0.0", Packages: map[string]*meta.Package{"d": d}}
+	b := &meta.Package{Name: "b", Version: "1.0.0", Packages: map[string]*meta.Package{"c": c}}
+	a := &meta.Package{Name: "a", Version: "1.0.0", Packages: map[string]*meta.Package{"b": b, "c": c}}
+	u := &meta.Package{Name: "u", Version: "1.0.0", Packages: map[string]*meta.Package{"d": d}}
+	root := meta.Package{Name: "root", Version: "1.0.0", Root: true, Packages: map[string]*meta.Package{"a": a, "b": b}}
+	return []meta.Package{root, *a, *b, *c, *d, *u}
+}
+
+func TestLimitDepth(t *testing.T) {
+	full := map[string][]string{
+		"root": {"a", "b"}, "a": {"b", "c"}, "b": {"c"}, "c": {"d"}, "d": {}, "u": {"d"},
+	}
+	for _, test := range []struct {
+		name      string
+		depth     int
+		expected  map[string][]string
+		truncated bool
+	}{
+		{"no limit", 0, full, false},
+		// a keeps its dependency on b, at the same level, and u loses the one on the pruned d
+		{"direct dependencies", 1, map[string][]string{
+			"root": {"a", "b"}, "a": {"b"}, "b": {}, "u": {},
+		}, true},
+		{"two levels", 2, map[string][]string{
+			"root": {"a", "b"}, "a": {"b", "c"}, "b": {"c"}, "c": {}, "u": {},
+		}, true},
+		{"graph height", 3, full, false},
+		{"beyond the graph height", 10, full, false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			pruned, truncated := limitDepth(diamondPackages(), test.depth)
+			assert.Equal(t, test.truncated, truncated)
+			graph := map[string][]string{}
+			for _, pkg := range pruned {
+				deps := []string{}
+				for _, dep := range pkg.Packages {
+					deps = append(deps, dep.Name)
+				}
+				sort.Strings(deps)
+				graph[pkg.Name] = deps
+			}
+			assert.Equal(t, test.expected, graph)
+		})
+	}
+}
@@ -8,7 +8,14 @@ import (
 	"github.com/go-git/go-git/v5"
 	"github.com/google/uuid"
 	"github.com/opensbom-generator/parsers/meta"
+	log "github.com/sirupsen/logrus"
 	"github.com/spdx/tools-golang/spdx/v2/common"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/copyright"
+	"github.com/spdx/spdx-sbom-generator/pkg/files"
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/runner/options"
 )
 
 const (
@@ -68,13 +75,39 @@ func SetPkgSPDXIdentifier(s, v string, root bool) common.ElementID {
 	return common.ElementID(fmt.Sprintf("%s-%s", replacer.Replace(s), v))
 }
 
-func BuildNamespace(name, version string) string {
+// BuildNamespace returns a unique namespace for the document of the package under base,
+// https://spdx.org/spdxdocs unless set
+func BuildNamespace(base, name, version string) string {
+	if base == "" {
+		base = fmt.Sprintf("%s://spdx.org/spdxdocs", HTTPSPrefix)
+	}
+	base = strings.TrimSuffix(base, "/")
+
 	uuidStr := uuid.New().String()
 	if version == "" {
-		return fmt.Sprintf("%s://spdx.org/spdxdocs/%s-%s", HTTPSPrefix, name, uuidStr)
+		return fmt.Sprintf("%s/%s-%s", base, name, uuidStr)
 	}
 
-	return fmt.Sprintf("%s://spdx.org/spdxdocs/%s-%s-%s", HTTPSPrefix, name, version, uuidStr)
+	return fmt.Sprintf("%s/%s-%s-%s", base, name, version, uuidStr)
+}
+
+// BuildCreators returns the creators of the document, the tool followed by the creators of the options
+func BuildCreators(opts *options.Options) ([]common.Creator, error) {
+	creators := []common.Creator{{
+		Creator:     fmt.Sprintf("spdx-sbom-generator-%s", opts.Version),
+		CreatorType: "Tool",
+	}}
+	for _, creator := range opts.Creators {
+		creatorType, name, email, err := helper.ParseActor(creator, "Person", "Organization", "Tool")
+		if err != nil {
+			return nil, fmt.Errorf("invalid creator: %w", err)
+		}
+		if email != "" {
+			name = fmt.Sprintf("%s (%s)", name, email)
+		}
+		creators = append(creators, common.Creator{Creator: name, CreatorType: creatorType})
+	}
+	return creators, nil
 }
 
 func BuildName(name, version string) string {
@@ -85,3 +118,115 @@ func BuildName(name, version string) string {
 
 	return fmt.Sprintf("%s-%s", name, version)
 }
+
+// BuildLicenseInfo returns the licenses found in files, NOASSERTION if none
+func BuildLicenseInfo(licenses []string) []string {
+	if len(licenses) == 0 {
+		return []string{NoAssertion}
+	}
+
+	return licenses
+}
+
+// AnalyzeFiles analyzes the files of the source tree of a root package,
+// leaving out the document being written
+func AnalyzeFiles(opts *options.Options, p meta.Package) (*files.Analysis, error) {
+	dir := p.LocalPath
+	if dir == "" {
+		dir = opts.Path
+	}
+
+	excludes := []string{}
+	if outputFile := OutputFile(opts); outputFile != "" {
+		excludes = append(excludes, outputFile)
+	}
+
+	return files.Analyze(dir, excludes, opts.ExcludePaths)
+}
+
+// BuildCopyrightText aggregates the copyright of the package metadata with the
+// statements found in the package sources, NOASSERTION if none
+func BuildCopyrightText(p meta.Package) string {
+	statements, err := copyright.Collect(p.Copyright, p.LocalPath)
+	if err != nil {
+		log.Debugf("scanning the copyrights of %s: %v", p.Name, err)
+	}
+	if len(statements) == 0 {
+		return NoAssertion
+	}
+
+	return copyright.Text(statements)
+}
+
+// PackageLicenses are the licenses of an SPDX package
+type PackageLicenses struct {
+	Concluded string
+	Declared  string
+	Comments  string
+	// Others are the licenses of the package the document holds the text of: those which
+	// aren't on the SPDX license list and, with opts.License, the canonical texts of the others
+	Others []*models.License
+	// Files are the licenses concluded from the license files of the package, with their text
+	Files []*models.License
+}
+
+// BuildLicenses concludes the licenses of the package from the license files of its
+// sources, recording the evidence in the comments, and falls back on the licenses the
+// parser found
+func BuildLicenses(opts *options.Options, p meta.Package) PackageLicenses {
+	licenses := buildLicenses(opts, p)
+	for _, other := range p.OtherLicense {
+		license := models.License(other)
+		licenses.Others = append(licenses.Others, &license)
+	}
+	if opts.License {
+		licenses.Others = append(licenses.Others, helper.CanonicalLicenses(licenses.Concluded)...)
+		licenses.Others = append(licenses.Others, helper.CanonicalLicenses(licenses.Declared)...)
+	}
+	return licenses
+}
+
+func buildLicenses(opts *options.Options, p meta.Package) PackageLicenses {
+	concluded, concludedNotes := buildLicense(p.LicenseConcluded)
+	declared, declaredNotes := buildLicense(p.LicenseDeclared)
+	notes := append(concludedNotes, declaredNotes...)
+	licenses := PackageLicenses{
+		Concluded: concluded,
+		Declared:  declared,
+		Comments:  helper.AppendLicenseNotes(p.CommentsLicense, notes...),
+	}
+	if p.LocalPath == "" {
+		return licenses
+	}
+
+	threshold := opts.LicenseThreshold
+	if threshold <= 0 {
+		threshold = helper.DefaultLicenseThreshold
+	}
+	detection, err := helper.DetectLicenses(p.LocalPath, threshold)
+	if err != nil {
+		log.Debugf("detecting the licenses of %s: %v", p.Name, err)
+		return licenses
+	}
+
+	licenses.Comments = helper.AppendLicenseNotes(detection.Comments(), declaredNotes...)
+	if detection.Expression != "" {
+		licenses.Concluded = detection.Expression
+		licenses.Others = detection.OtherLicenses()
+		licenses.Files = detection.Licenses()
+	}
+	return licenses
+}
+
+// buildLicense returns the license expression of a parser, with the case of its identifiers
+// corrected, and notes on its deprecated identifiers. It returns NOASSERTION if none or not valid
+func buildLicense(license string) (string, []string) {
+	if license == NoAssertion || license == "NONE" {
+		return license, nil
+	}
+	if normalized, notes, ok := helper.NormalizeLicenseExpression(license); ok {
+		return normalized, notes
+	}
+
+	return NoAssertion, nil
+}
@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: Apache-2.0
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spdx/tools-golang/spdx/v2/common"
+)
+
+// ActorValue returns a supplier or originator as a curation has it, as "Organization: Acme Inc.",
+// NOASSERTION if it has no type
+func ActorValue(actorType, name string) string {
+	if actorType == "" {
+		if name == "" {
+			return NoAssertion
+		}
+		return name
+	}
+	return fmt.Sprintf("%s: %s", actorType, name)
+}
+
+// SupplierValue returns the supplier of a package as a curation has it
+func SupplierValue(supplier *common.Supplier) string {
+	if supplier == nil {
+		return NoAssertion
+	}
+	return ActorValue(supplier.SupplierType, supplier.Supplier)
+}
+
+// OriginatorValue returns the originator of a package as a curation has it, empty if the package has none
+func OriginatorValue(originator *common.Originator) string {
+	if originator == nil {
+		return ""
+	}
+	return ActorValue(originator.OriginatorType, originator.Originator)
+}
+
+// ParseActorValue parses a supplier or originator of a curation into its type and name
+func ParseActorValue(value string) (string, string) {
+	actorType, name, found := strings.Cut(value, ":")
+	if !found {
+		return "", value
+	}
+	return strings.TrimSpace(actorType), strings.TrimSpace(name)
+}
+
+// BuildSupplier returns the supplier of a curation
+func BuildSupplier(value string) *common.Supplier {
+	supplierType, name := ParseActorValue(value)
+	return &common.Supplier{Supplier: name, SupplierType: supplierType}
+}
+
+// BuildOriginator returns the originator of a curation, nil if empty
+func BuildOriginator(value string) *common.Originator {
+	if value == "" {
+		return nil
+	}
+	originatorType, name := ParseActorValue(value)
+	return &common.Originator{Originator: name, OriginatorType: originatorType}
+}
@@ -0,0 +1,37 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"github.com/opensbom-generator/parsers/meta"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// DependencyScopes are the scopes of the dependencies which aren't runtime ones, by the SPDX
+// identifiers of the package and of its dependency
+type DependencyScopes map[string]models.Scope
+
+// Set records the scope of the dependency of the package
+func (s DependencyScopes) Set(pkg, dep meta.Package, scope models.Scope) {
+	if scope == models.ScopeRuntime {
+		delete(s, scopeKey(pkg, dep))
+		return
+	}
+	s[scopeKey(pkg, dep)] = scope
+}
+
+// Get returns the scope of the dependency of the package, runtime unless recorded otherwise
+func (s DependencyScopes) Get(pkg, dep meta.Package) models.Scope {
+	return s[scopeKey(pkg, dep)]
+}
+
+// Relationship returns the relationship of the package with its dependency, the *_DEPENDENCY_OF
+// ones going from the dependency to the package
+func (s DependencyScopes) Relationship(pkg, dep meta.Package) models.RelationshipType {
+	return models.ScopeRelationship(s.Get(pkg, dep))
+}
+
+func scopeKey(pkg, dep meta.Package) string {
+	return string(SetPkgSPDXIdentifier(pkg.Name, pkg.Version, pkg.Root)) + " " + string(SetPkgSPDXIdentifier(dep.Name, dep.Version, dep.Root))
+}
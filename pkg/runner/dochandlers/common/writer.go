@@ -3,28 +3,47 @@ package common
 
 import (
 	"bufio"
+	stdjson "encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	"github.com/spdx/spdx-sbom-generator/pkg/format"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
 	"github.com/spdx/spdx-sbom-generator/pkg/runner/options"
 	"github.com/spdx/tools-golang/json"
 	"github.com/spdx/tools-golang/spdx/common"
+	v22 "github.com/spdx/tools-golang/spdx/v2/v2_2"
+	v23 "github.com/spdx/tools-golang/spdx/v2/v2_3"
 	"github.com/spdx/tools-golang/tagvalue"
+	"github.com/spdx/tools-golang/yaml"
 )
 
-// WriteDocument serializes the document and writes it to the w writer
-func WriteDocument(opts *options.Options, document common.AnyDocument) error {
+const documentRefPrefix = "DocumentRef-"
+
+// OutputFile returns the path of the file the document is written to, empty when written to stdout
+func OutputFile(opts *options.Options) string {
+	if opts.OutputDir == "" {
+		return ""
+	}
+
+	filename := fmt.Sprintf("bom-%s.%s", opts.Slug, opts.Format.String())
+	return filepath.Join(opts.OutputDir, filename)
+}
+
+// WriteDocument serializes the document and writes it to the w writer, ecosystems mapping
+// the SPDX identifiers of the packages to the ecosystem listed in the csv and tsv formats
+func WriteDocument(opts *options.Options, document common.AnyDocument, ecosystems map[string]string) error {
 	var err error
 	var f *os.File
 
 	// if an output directory is specified then write to file in that directory else write to stdout
 	if opts.OutputDir != "" {
-		filename := fmt.Sprintf("bom-%s.%s", opts.Slug, opts.Format.String())
-		outputFile := filepath.Join(opts.OutputDir, filename)
-		f, err = os.OpenFile(outputFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		f, err = os.OpenFile(OutputFile(opts), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 		if err != nil {
 			return errors.Wrap(err, "error opening file")
 		}
@@ -41,7 +60,22 @@ func WriteDocument(opts *options.Options, document common.AnyDocument) error {
 			return err
 		}
 	case options.OutputFormatJson:
-		err = json.Write(document, w, json.EscapeHTML(true), json.Indent("\t"))
+		err = json.Write(withDocumentRefPrefix(document), w, json.EscapeHTML(true), json.Indent("\t"))
+		if err != nil {
+			return err
+		}
+	case options.OutputFormatYaml:
+		err = yaml.Write(withDocumentRefPrefix(document), w)
+		if err != nil {
+			return err
+		}
+	case options.OutputFormatRdf:
+		err = writeRDF(document, w)
+		if err != nil {
+			return err
+		}
+	case options.OutputFormatCsv, options.OutputFormatTsv:
+		err = writePackageList(opts, document, ecosystems, w)
 		if err != nil {
 			return err
 		}
@@ -55,3 +89,123 @@ func WriteDocument(opts *options.Options, document common.AnyDocument) error {
 	log.Infof("SBOM written to %s", f.Name())
 	return nil
 }
+
+// WriteReports writes the human-readable reports of the document to the output directory,
+// ecosystems mapping the SPDX identifiers of the packages to the ecosystem of their parser
+func WriteReports(opts *options.Options, document common.AnyDocument, ecosystems map[string]string) error {
+	if len(opts.Reports) == 0 {
+		return nil
+	}
+	if opts.OutputDir == "" {
+		return errors.New("the reports need an output directory")
+	}
+
+	reportDocument, err := convertDocument(document)
+	if err != nil {
+		return err
+	}
+	report := format.BuildReport("", []format.ReportSource{{Document: reportDocument, Ecosystems: ecosystems}})
+
+	for _, reportFormat := range opts.Reports {
+		outputFile := filepath.Join(opts.OutputDir, reportFormat.Filename())
+		f, err := os.Create(outputFile)
+		if err != nil {
+			return errors.Wrap(err, "error opening file")
+		}
+		err = format.RenderReport(f, report, reportFormat)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("writing the report %s: %w", outputFile, err)
+		}
+		log.Infof("Report written to %s", outputFile)
+	}
+	return nil
+}
+
+// writePackageList writes the packages of the document, one row per package, as CSV or TSV
+func writePackageList(opts *options.Options, document common.AnyDocument, ecosystems map[string]string, w io.Writer) error {
+	packageDocument, err := convertDocument(document)
+	if err != nil {
+		return err
+	}
+
+	renderer := format.CSVSPDXRenderer{Columns: opts.Columns, Ecosystems: ecosystems}
+	if opts.Format == options.OutputFormatTsv {
+		renderer.Comma = '\t'
+	}
+	content, err := renderer.RenderDocument(packageDocument)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(content)
+	return err
+}
+
+// writeRDF writes the document as RDF/XML, which tools-golang only reads
+func writeRDF(document common.AnyDocument, w io.Writer) error {
+	rdfDocument, err := convertDocument(document)
+	if err != nil {
+		return err
+	}
+
+	content, err := format.RDFSPDXRenderer{}.RenderDocument(rdfDocument)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(content)
+	return err
+}
+
+// convertDocument converts the document to the document of the legacy formats through
+// its SPDX JSON, shared by all the schema versions
+func convertDocument(document common.AnyDocument) (models.Document, error) {
+	var converted models.Document
+	content, err := stdjson.Marshal(withDocumentRefPrefix(document))
+	if err != nil {
+		return converted, errors.Wrap(err, "error converting document")
+	}
+	if err := stdjson.Unmarshal(content, &converted); err != nil {
+		return converted, errors.Wrap(err, "error converting document")
+	}
+	return converted, nil
+}
+
+// withDocumentRefPrefix returns a copy of the document whose external document references have
+// their DocumentRef- prefix, which tools-golang leaves out of the identifiers but the tag-value
+// writer only adds
+func withDocumentRefPrefix(document common.AnyDocument) common.AnyDocument {
+	prefix := func(id string) string {
+		if strings.HasPrefix(id, documentRefPrefix) {
+			return id
+		}
+		return documentRefPrefix + id
+	}
+
+	switch doc := document.(type) {
+	case *v22.Document:
+		if len(doc.ExternalDocumentReferences) == 0 {
+			return document
+		}
+		copied := *doc
+		copied.ExternalDocumentReferences = make([]v22.ExternalDocumentRef, len(doc.ExternalDocumentReferences))
+		for i, ref := range doc.ExternalDocumentReferences {
+			ref.DocumentRefID = prefix(ref.DocumentRefID)
+			copied.ExternalDocumentReferences[i] = ref
+		}
+		return &copied
+	case *v23.Document:
+		if len(doc.ExternalDocumentReferences) == 0 {
+			return document
+		}
+		copied := *doc
+		copied.ExternalDocumentReferences = make([]v23.ExternalDocumentRef, len(doc.ExternalDocumentReferences))
+		for i, ref := range doc.ExternalDocumentReferences {
+			ref.DocumentRefID = prefix(ref.DocumentRefID)
+			copied.ExternalDocumentReferences[i] = ref
+		}
+		return &copied
+	}
+	return document
+}
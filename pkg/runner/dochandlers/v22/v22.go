@@ -4,9 +4,16 @@ package v22
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/opensbom-generator/parsers/meta"
+	"github.com/spdx/spdx-sbom-generator/pkg/curation"
+	"github.com/spdx/spdx-sbom-generator/pkg/externaldocs"
+	"github.com/spdx/spdx-sbom-generator/pkg/files"
+	"github.com/spdx/spdx-sbom-generator/pkg/format"
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
 	"github.com/spdx/spdx-sbom-generator/pkg/runner/dochandlers/common"
 	"github.com/spdx/spdx-sbom-generator/pkg/runner/options"
 	spdxCommon "github.com/spdx/tools-golang/spdx/common"
@@ -27,20 +34,22 @@ func (h *Handler) CreateDocument(opts *options.Options, rootPackages []meta.Pack
 	// fetch the top level package
 	topLevelPkg := tov22Package(rootPackages[0])
 
+	creators, err := common.BuildCreators(opts)
+	if err != nil {
+		return nil, err
+	}
+
 	doc := &v22.Document{
 		SPDXVersion:                v22.Version,
 		DataLicense:                v22.DataLicense,
 		SPDXIdentifier:             spdxDocumentIdentifier,
 		DocumentName:               common.BuildName(topLevelPkg.PackageName, topLevelPkg.PackageVersion),
-		DocumentNamespace:          common.BuildNamespace(topLevelPkg.PackageName, topLevelPkg.PackageVersion),
+		DocumentNamespace:          common.BuildNamespace(opts.Namespace, topLevelPkg.PackageName, topLevelPkg.PackageVersion),
 		ExternalDocumentReferences: nil,
-		DocumentComment:            "",
+		DocumentComment:            opts.DocumentComment,
 		CreationInfo: &v22.CreationInfo{
-			Creators: []v2Common.Creator{{
-				Creator:     fmt.Sprintf("spdx-sbom-generator-%s", opts.Version),
-				CreatorType: "Tool",
-			}},
-			Created: time.Now().UTC().Format(time.RFC3339),
+			Creators: creators,
+			Created:  time.Now().UTC().Format(time.RFC3339),
 		},
 		Packages:      nil,
 		Files:         nil,
@@ -70,11 +79,15 @@ func (h *Handler) CreateDocument(opts *options.Options, rootPackages []meta.Pack
 		})
 	}
 
+	if opts.DocumentName != "" {
+		doc.DocumentName = opts.DocumentName
+	}
+
 	return doc, nil
 }
 
 // AddDocumentPackages links the parsed packages to the passed document.
-func (h *Handler) AddDocumentPackages(_ *options.Options, document spdxCommon.AnyDocument, metaPackages []meta.Package) error {
+func (h *Handler) AddDocumentPackages(opts *options.Options, document spdxCommon.AnyDocument, metaPackages []meta.Package, scopes common.DependencyScopes) error {
 	// TODO: https://github.com/spdx/tools-golang/blob/main/convert/chain.go#L38 use for conversion?
 	// type cast to v2.2 document
 	v22Doc, ok := document.(*v22.Document)
@@ -89,38 +102,204 @@ func (h *Handler) AddDocumentPackages(_ *options.Options, document spdxCommon.An
 	*/
 	for _, pkg := range metaPackages {
 		v22Pkg := tov22Package(pkg)
+		v22Pkg.PackageCopyrightText = common.BuildCopyrightText(pkg)
+		licenses := common.BuildLicenses(opts, pkg)
+		renamed := addOtherLicenses(v22Doc, licenses.Others)
+		v22Pkg.PackageLicenseConcluded = helper.RenameLicenseRefs(licenses.Concluded, renamed)
+		v22Pkg.PackageLicenseDeclared = helper.RenameLicenseRefs(licenses.Declared, renamed)
+		v22Pkg.PackageLicenseComments = licenses.Comments
+
+		// analyze the files of our own source tree
+		if opts.AnalyzeFiles && pkg.Root {
+			if err := addPackageFiles(opts, v22Doc, v22Pkg, pkg); err != nil {
+				return fmt.Errorf("analyzing the files of %s: %w", pkg.Name, err)
+			}
+		}
 		v22Doc.Packages = append(v22Doc.Packages, v22Pkg)
 
 		// traverse through sub packages of a meta package
 		for _, subMod := range pkg.Packages {
 			subV22Pkg := tov22Package(*subMod)
 
+			// the *_DEPENDENCY_OF relationships of the scoped dependencies go from the dependency
+			relationship := scopes.Relationship(pkg, *subMod)
+			refA, refB := v22Pkg.PackageSPDXIdentifier, subV22Pkg.PackageSPDXIdentifier
+			if relationship.Reversed() {
+				refA, refB = refB, refA
+			}
+
 			v22Doc.Relationships = append(v22Doc.Relationships, &v22.Relationship{
 				RefA: v2Common.DocElementID{
 					DocumentRefID: "",
-					ElementRefID:  v22Pkg.PackageSPDXIdentifier,
+					ElementRefID:  refA,
 					SpecialID:     "",
 				},
 				RefB: v2Common.DocElementID{
 					DocumentRefID: "",
-					ElementRefID:  subV22Pkg.PackageSPDXIdentifier,
+					ElementRefID:  refB,
 					SpecialID:     "",
 				},
-				Relationship:        "DEPENDS_ON",
+				Relationship:        string(relationship),
 				RelationshipComment: "",
 			})
 		}
+	}
 
-		// append meta package licenses
-		for licence := range pkg.OtherLicense {
-			v22Doc.OtherLicenses = append(v22Doc.OtherLicenses, &v22.OtherLicense{
-				LicenseIdentifier: pkg.OtherLicense[licence].ID,
-				ExtractedText:     pkg.OtherLicense[licence].ExtractedText,
-				LicenseName:       pkg.OtherLicense[licence].Name,
-				LicenseComment:    pkg.OtherLicense[licence].Comments,
-			})
+	return nil
+}
+
+// AddDocumentAnnotation annotates the document itself with the comment of the generator.
+func (h *Handler) AddDocumentAnnotation(opts *options.Options, document spdxCommon.AnyDocument, comment string) error {
+	v22Doc, ok := document.(*v22.Document)
+	if !ok {
+		return errors.New("error converting document")
+	}
+
+	v22Doc.Annotations = append(v22Doc.Annotations, &v22.Annotation{
+		Annotator: v2Common.Annotator{
+			Annotator:     fmt.Sprintf("spdx-sbom-generator-%s", opts.Version),
+			AnnotatorType: "Tool",
+		},
+		AnnotationDate: time.Now().UTC().Format(time.RFC3339),
+		AnnotationType: "OTHER",
+		AnnotationSPDXIdentifier: v2Common.DocElementID{
+			DocumentRefID: "",
+			ElementRefID:  v22Doc.SPDXIdentifier,
+			SpecialID:     "",
+		},
+		AnnotationComment: comment,
+	})
+
+	return nil
+}
+
+// ApplyCurations applies the curations of the options to the packages of the document, ecosystems
+// mapping their SPDX identifier to the slug of their plugin, and annotates each package curated
+func (h *Handler) ApplyCurations(opts *options.Options, document spdxCommon.AnyDocument, ecosystems map[string]string) error {
+	v22Doc, ok := document.(*v22.Document)
+	if !ok {
+		return errors.New("error converting document")
+	}
+
+	for _, pkg := range v22Doc.Packages {
+		ecosystem := ecosystems["SPDXRef-"+string(pkg.PackageSPDXIdentifier)]
+		curated := curation.Package{
+			Ecosystem:        ecosystem,
+			Name:             pkg.PackageName,
+			Version:          pkg.PackageVersion,
+			Purl:             format.PackageURL(ecosystem, pkg.PackageName, pkg.PackageVersion),
+			LicenseConcluded: pkg.PackageLicenseConcluded,
+			LicenseDeclared:  pkg.PackageLicenseDeclared,
+			Supplier:         common.SupplierValue(pkg.PackageSupplier),
+			Originator:       common.OriginatorValue(pkg.PackageOriginator),
+			Homepage:         pkg.PackageHomePage,
+			DownloadLocation: pkg.PackageDownloadLocation,
+			Comment:          pkg.PackageComment,
+			LicenseComments:  pkg.PackageLicenseComments,
+		}
+		comment := opts.Curations.Apply(&curated)
+		if comment == "" {
+			continue
 		}
 
+		pkg.PackageLicenseConcluded = curated.LicenseConcluded
+		pkg.PackageLicenseDeclared = curated.LicenseDeclared
+		pkg.PackageSupplier = common.BuildSupplier(curated.Supplier)
+		pkg.PackageOriginator = common.BuildOriginator(curated.Originator)
+		pkg.PackageHomePage = curated.Homepage
+		pkg.PackageDownloadLocation = curated.DownloadLocation
+		pkg.PackageComment = curated.Comment
+		pkg.PackageLicenseComments = curated.LicenseComments
+		v22Doc.Annotations = append(v22Doc.Annotations, &v22.Annotation{
+			Annotator: v2Common.Annotator{
+				Annotator:     fmt.Sprintf("spdx-sbom-generator-%s", opts.Version),
+				AnnotatorType: "Tool",
+			},
+			AnnotationDate: time.Now().UTC().Format(time.RFC3339),
+			AnnotationType: "OTHER",
+			AnnotationSPDXIdentifier: v2Common.DocElementID{
+				ElementRefID: pkg.PackageSPDXIdentifier,
+			},
+			AnnotationComment: comment,
+		})
+	}
+
+	return nil
+}
+
+// AddExternalDocumentRefs references the external documents of the packages of refs, by SPDX
+// identifier, and points the relationships to these packages to the ones of the external documents
+func (h *Handler) AddExternalDocumentRefs(opts *options.Options, document spdxCommon.AnyDocument, refs map[string]externaldocs.Ref) error {
+	v22Doc, ok := document.(*v22.Document)
+	if !ok {
+		return errors.New("error converting document")
+	}
+
+	referenced := map[string]bool{}
+	for _, relationship := range v22Doc.Relationships {
+		ref, ok := refs[string(relationship.RefB.ElementRefID)]
+		if !ok || relationship.RefB.DocumentRefID != "" {
+			continue
+		}
+		relationship.RefB = v2Common.DocElementID{
+			DocumentRefID: ref.Document.ID,
+			ElementRefID:  v2Common.ElementID(ref.Element),
+		}
+		if referenced[ref.Document.ID] {
+			continue
+		}
+		referenced[ref.Document.ID] = true
+		v22Doc.ExternalDocumentReferences = append(v22Doc.ExternalDocumentReferences, v22.ExternalDocumentRef{
+			DocumentRefID: ref.Document.ID,
+			URI:           ref.Document.Namespace,
+			Checksum:      v2Common.Checksum{Algorithm: v2Common.SHA1, Value: ref.Document.SHA1},
+		})
+	}
+
+	return nil
+}
+
+// addPackageFiles adds the files of the source tree of the package to the document,
+// the package containing them
+func addPackageFiles(opts *options.Options, doc *v22.Document, v22Pkg *v22.Package, pkg meta.Package) error {
+	analysis, err := common.AnalyzeFiles(opts, pkg)
+	if err != nil {
+		return err
+	}
+
+	v22Pkg.FilesAnalyzed = true
+	v22Pkg.IsFilesAnalyzedTagPresent = true
+	v22Pkg.PackageVerificationCode = v2Common.PackageVerificationCode{Value: analysis.VerificationCode}
+	v22Pkg.PackageLicenseInfoFromFiles = common.BuildLicenseInfo(analysis.Licenses)
+
+	for _, file := range analysis.Files {
+		v22File := &v22.File{
+			FileName:           file.Path,
+			FileSPDXIdentifier: v2Common.ElementID(files.ID(pkg.Name, file)),
+			FileTypes:          file.Types,
+			Checksums: []v2Common.Checksum{
+				{Algorithm: v2Common.SHA1, Value: file.SHA1},
+				{Algorithm: v2Common.SHA256, Value: file.SHA256},
+			},
+			LicenseConcluded:   common.NoAssertion,
+			LicenseInfoInFiles: common.BuildLicenseInfo(file.Licenses),
+			FileCopyrightText:  common.NoAssertion,
+		}
+		doc.Files = append(doc.Files, v22File)
+		doc.Relationships = append(doc.Relationships, &v22.Relationship{
+			RefA: v2Common.DocElementID{
+				DocumentRefID: "",
+				ElementRefID:  v22Pkg.PackageSPDXIdentifier,
+				SpecialID:     "",
+			},
+			RefB: v2Common.DocElementID{
+				DocumentRefID: "",
+				ElementRefID:  v22File.FileSPDXIdentifier,
+				SpecialID:     "",
+			},
+			Relationship:        "CONTAINS",
+			RelationshipComment: "",
+		})
 	}
 
 	return nil
@@ -153,3 +332,34 @@ func tov22Package(p meta.Package) *v22.Package {
 	}
 
 }
+
+// addOtherLicenses adds the licenses the document holds the text of, once whatever the
+// number of packages concluding them. A LicenseRef- license whose text the document
+// already holds is renamed after it, the identifiers renamed being returned
+func addOtherLicenses(doc *v22.Document, licenses []*models.License) map[string]string {
+	renamed := map[string]string{}
+	for _, license := range licenses {
+		found := false
+		for _, other := range doc.OtherLicenses {
+			if other.LicenseIdentifier == license.ID {
+				found = true
+				break
+			}
+			if strings.HasPrefix(license.ID, "LicenseRef-") && license.ExtractedText != "" && helper.SameLicenseText(other.ExtractedText, license.ExtractedText) {
+				renamed[license.ID] = other.LicenseIdentifier
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+		doc.OtherLicenses = append(doc.OtherLicenses, &v22.OtherLicense{
+			LicenseIdentifier: license.ID,
+			ExtractedText:     license.ExtractedText,
+			LicenseName:       license.Name,
+			LicenseComment:    license.Comments,
+		})
+	}
+	return renamed
+}
@@ -4,9 +4,16 @@ package v23
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/opensbom-generator/parsers/meta"
+	"github.com/spdx/spdx-sbom-generator/pkg/curation"
+	"github.com/spdx/spdx-sbom-generator/pkg/externaldocs"
+	"github.com/spdx/spdx-sbom-generator/pkg/files"
+	"github.com/spdx/spdx-sbom-generator/pkg/format"
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
 	"github.com/spdx/spdx-sbom-generator/pkg/runner/dochandlers/common"
 	"github.com/spdx/spdx-sbom-generator/pkg/runner/options"
 	spdxCommon "github.com/spdx/tools-golang/spdx/common"
@@ -27,20 +34,22 @@ func (h *Handler) CreateDocument(opts *options.Options, rootPackages []meta.Pack
 	// fetch the top level package
 	topLevelPkg := tov23Package(rootPackages[0])
 
+	creators, err := common.BuildCreators(opts)
+	if err != nil {
+		return nil, err
+	}
+
 	doc := &v23.Document{
 		SPDXVersion:                v23.Version,
 		DataLicense:                v23.DataLicense,
 		SPDXIdentifier:             spdxDocumentIdentifier,
 		DocumentName:               common.BuildName(topLevelPkg.PackageName, topLevelPkg.PackageVersion),
-		DocumentNamespace:          common.BuildNamespace(topLevelPkg.PackageName, topLevelPkg.PackageVersion),
+		DocumentNamespace:          common.BuildNamespace(opts.Namespace, topLevelPkg.PackageName, topLevelPkg.PackageVersion),
 		ExternalDocumentReferences: nil,
-		DocumentComment:            "",
+		DocumentComment:            opts.DocumentComment,
 		CreationInfo: &v23.CreationInfo{
-			Creators: []v2Common.Creator{{
-				Creator:     fmt.Sprintf("spdx-sbom-generator-%s", opts.Version),
-				CreatorType: "Tool",
-			}},
-			Created: time.Now().UTC().Format(time.RFC3339),
+			Creators: creators,
+			Created:  time.Now().UTC().Format(time.RFC3339),
 		},
 		Packages:      nil,
 		Files:         nil,
@@ -70,11 +79,15 @@ func (h *Handler) CreateDocument(opts *options.Options, rootPackages []meta.Pack
 		})
 	}
 
+	if opts.DocumentName != "" {
+		doc.DocumentName = opts.DocumentName
+	}
+
 	return doc, nil
 }
 
 // AddDocumentPackages links the parsed packages to the passed document.
-func (h *Handler) AddDocumentPackages(_ *options.Options, document spdxCommon.AnyDocument, metaPackages []meta.Package) error {
+func (h *Handler) AddDocumentPackages(opts *options.Options, document spdxCommon.AnyDocument, metaPackages []meta.Package, scopes common.DependencyScopes) error {
 	// TODO: https://github.com/spdx/tools-golang/blob/main/convert/chain.go#L38 use for conversion?
 	// type cast to v2.3 document
 	v23Doc, ok := document.(*v23.Document)
@@ -89,42 +102,210 @@ func (h *Handler) AddDocumentPackages(_ *options.Options, document spdxCommon.An
 	*/
 	for _, pkg := range metaPackages {
 		v23Pkg := tov23Package(pkg)
+		v23Pkg.PackageCopyrightText = common.BuildCopyrightText(pkg)
+		licenses := common.BuildLicenses(opts, pkg)
+		renamed := addOtherLicenses(v23Doc, licenses.Others)
+		v23Pkg.PackageLicenseConcluded = helper.RenameLicenseRefs(licenses.Concluded, renamed)
+		v23Pkg.PackageLicenseDeclared = helper.RenameLicenseRefs(licenses.Declared, renamed)
+		v23Pkg.PackageLicenseComments = licenses.Comments
+
+		// analyze the files of our own source tree
+		if opts.AnalyzeFiles && pkg.Root {
+			if err := addPackageFiles(opts, v23Doc, v23Pkg, pkg); err != nil {
+				return fmt.Errorf("analyzing the files of %s: %w", pkg.Name, err)
+			}
+		}
 
 		// traverse through sub packages of a meta package
 		for _, subMod := range pkg.Packages {
 			subV23Pkg := tov23Package(*subMod)
 
+			// the *_DEPENDENCY_OF relationships of the scoped dependencies go from the dependency
+			relationship := scopes.Relationship(pkg, *subMod)
+			refA, refB := v23Pkg.PackageSPDXIdentifier, subV23Pkg.PackageSPDXIdentifier
+			if relationship.Reversed() {
+				refA, refB = refB, refA
+			}
+
 			v23Doc.Relationships = append(v23Doc.Relationships, &v23.Relationship{
 				RefA: v2Common.DocElementID{
 					DocumentRefID: "",
-					ElementRefID:  v23Pkg.PackageSPDXIdentifier,
+					ElementRefID:  refA,
 					SpecialID:     "",
 				},
 				RefB: v2Common.DocElementID{
 					DocumentRefID: "",
-					ElementRefID:  subV23Pkg.PackageSPDXIdentifier,
+					ElementRefID:  refB,
 					SpecialID:     "",
 				},
-				Relationship:        "DEPENDS_ON",
+				Relationship:        string(relationship),
 				RelationshipComment: "",
 			})
 		}
 
-		// append meta package licenses
-		for licence := range pkg.OtherLicense {
-			v23Doc.OtherLicenses = append(v23Doc.OtherLicenses, &v23.OtherLicense{
-				LicenseIdentifier: pkg.OtherLicense[licence].ID,
-				ExtractedText:     pkg.OtherLicense[licence].ExtractedText,
-				LicenseName:       pkg.OtherLicense[licence].Name,
-				LicenseComment:    pkg.OtherLicense[licence].Comments,
-			})
-		}
 		v23Doc.Packages = append(v23Doc.Packages, v23Pkg)
 	}
 
 	return nil
 }
 
+// AddDocumentAnnotation annotates the document itself with the comment of the generator.
+func (h *Handler) AddDocumentAnnotation(opts *options.Options, document spdxCommon.AnyDocument, comment string) error {
+	v23Doc, ok := document.(*v23.Document)
+	if !ok {
+		return errors.New("error converting document")
+	}
+
+	v23Doc.Annotations = append(v23Doc.Annotations, &v23.Annotation{
+		Annotator: v2Common.Annotator{
+			Annotator:     fmt.Sprintf("spdx-sbom-generator-%s", opts.Version),
+			AnnotatorType: "Tool",
+		},
+		AnnotationDate: time.Now().UTC().Format(time.RFC3339),
+		AnnotationType: "OTHER",
+		AnnotationSPDXIdentifier: v2Common.DocElementID{
+			DocumentRefID: "",
+			ElementRefID:  v23Doc.SPDXIdentifier,
+			SpecialID:     "",
+		},
+		AnnotationComment: comment,
+	})
+
+	return nil
+}
+
+// ApplyCurations applies the curations of the options to the packages of the document, ecosystems
+// mapping their SPDX identifier to the slug of their plugin, and annotates each package curated
+func (h *Handler) ApplyCurations(opts *options.Options, document spdxCommon.AnyDocument, ecosystems map[string]string) error {
+	v23Doc, ok := document.(*v23.Document)
+	if !ok {
+		return errors.New("error converting document")
+	}
+
+	for _, pkg := range v23Doc.Packages {
+		ecosystem := ecosystems["SPDXRef-"+string(pkg.PackageSPDXIdentifier)]
+		curated := curation.Package{
+			Ecosystem:        ecosystem,
+			Name:             pkg.PackageName,
+			Version:          pkg.PackageVersion,
+			Purl:             format.PackageURL(ecosystem, pkg.PackageName, pkg.PackageVersion),
+			LicenseConcluded: pkg.PackageLicenseConcluded,
+			LicenseDeclared:  pkg.PackageLicenseDeclared,
+			Supplier:         common.SupplierValue(pkg.PackageSupplier),
+			Originator:       common.OriginatorValue(pkg.PackageOriginator),
+			Homepage:         pkg.PackageHomePage,
+			DownloadLocation: pkg.PackageDownloadLocation,
+			Comment:          pkg.PackageComment,
+			LicenseComments:  pkg.PackageLicenseComments,
+		}
+		comment := opts.Curations.Apply(&curated)
+		if comment == "" {
+			continue
+		}
+
+		pkg.PackageLicenseConcluded = curated.LicenseConcluded
+		pkg.PackageLicenseDeclared = curated.LicenseDeclared
+		pkg.PackageSupplier = common.BuildSupplier(curated.Supplier)
+		pkg.PackageOriginator = common.BuildOriginator(curated.Originator)
+		pkg.PackageHomePage = curated.Homepage
+		pkg.PackageDownloadLocation = curated.DownloadLocation
+		pkg.PackageComment = curated.Comment
+		pkg.PackageLicenseComments = curated.LicenseComments
+		v23Doc.Annotations = append(v23Doc.Annotations, &v23.Annotation{
+			Annotator: v2Common.Annotator{
+				Annotator:     fmt.Sprintf("spdx-sbom-generator-%s", opts.Version),
+				AnnotatorType: "Tool",
+			},
+			AnnotationDate: time.Now().UTC().Format(time.RFC3339),
+			AnnotationType: "OTHER",
+			AnnotationSPDXIdentifier: v2Common.DocElementID{
+				ElementRefID: pkg.PackageSPDXIdentifier,
+			},
+			AnnotationComment: comment,
+		})
+	}
+
+	return nil
+}
+
+// AddExternalDocumentRefs references the external documents of the packages of refs, by SPDX
+// identifier, and points the relationships to these packages to the ones of the external documents
+func (h *Handler) AddExternalDocumentRefs(opts *options.Options, document spdxCommon.AnyDocument, refs map[string]externaldocs.Ref) error {
+	v23Doc, ok := document.(*v23.Document)
+	if !ok {
+		return errors.New("error converting document")
+	}
+
+	referenced := map[string]bool{}
+	for _, relationship := range v23Doc.Relationships {
+		ref, ok := refs[string(relationship.RefB.ElementRefID)]
+		if !ok || relationship.RefB.DocumentRefID != "" {
+			continue
+		}
+		relationship.RefB = v2Common.DocElementID{
+			DocumentRefID: ref.Document.ID,
+			ElementRefID:  v2Common.ElementID(ref.Element),
+		}
+		if referenced[ref.Document.ID] {
+			continue
+		}
+		referenced[ref.Document.ID] = true
+		v23Doc.ExternalDocumentReferences = append(v23Doc.ExternalDocumentReferences, v23.ExternalDocumentRef{
+			DocumentRefID: ref.Document.ID,
+			URI:           ref.Document.Namespace,
+			Checksum:      v2Common.Checksum{Algorithm: v2Common.SHA1, Value: ref.Document.SHA1},
+		})
+	}
+
+	return nil
+}
+
+// addPackageFiles adds the files of the source tree of the package to the document,
+// the package containing them
+func addPackageFiles(opts *options.Options, doc *v23.Document, v23Pkg *v23.Package, pkg meta.Package) error {
+	analysis, err := common.AnalyzeFiles(opts, pkg)
+	if err != nil {
+		return err
+	}
+
+	v23Pkg.FilesAnalyzed = true
+	v23Pkg.IsFilesAnalyzedTagPresent = true
+	v23Pkg.PackageVerificationCode = &v2Common.PackageVerificationCode{Value: analysis.VerificationCode}
+	v23Pkg.PackageLicenseInfoFromFiles = common.BuildLicenseInfo(analysis.Licenses)
+
+	for _, file := range analysis.Files {
+		v23File := &v23.File{
+			FileName:           file.Path,
+			FileSPDXIdentifier: v2Common.ElementID(files.ID(pkg.Name, file)),
+			FileTypes:          file.Types,
+			Checksums: []v2Common.Checksum{
+				{Algorithm: v2Common.SHA1, Value: file.SHA1},
+				{Algorithm: v2Common.SHA256, Value: file.SHA256},
+			},
+			LicenseConcluded:   common.NoAssertion,
+			LicenseInfoInFiles: common.BuildLicenseInfo(file.Licenses),
+			FileCopyrightText:  common.NoAssertion,
+		}
+		doc.Files = append(doc.Files, v23File)
+		doc.Relationships = append(doc.Relationships, &v23.Relationship{
+			RefA: v2Common.DocElementID{
+				DocumentRefID: "",
+				ElementRefID:  v23Pkg.PackageSPDXIdentifier,
+				SpecialID:     "",
+			},
+			RefB: v2Common.DocElementID{
+				DocumentRefID: "",
+				ElementRefID:  v23File.FileSPDXIdentifier,
+				SpecialID:     "",
+			},
+			Relationship:        "CONTAINS",
+			RelationshipComment: "",
+		})
+	}
+
+	return nil
+}
+
 // tov23Package converts the package returned from the parsers to the spdx format
 // https://spdx.github.io/spdx-spec/v2.3/package-information/
 func tov23Package(p meta.Package) *v23.Package {
@@ -151,3 +332,34 @@ func tov23Package(p meta.Package) *v23.Package {
 		IsUnpackaged:            p.Root,
 	}
 }
+
+// addOtherLicenses adds the licenses the document holds the text of, once whatever the
+// number of packages concluding them. A LicenseRef- license whose text the document
+// already holds is renamed after it, the identifiers renamed being returned
+func addOtherLicenses(doc *v23.Document, licenses []*models.License) map[string]string {
+	renamed := map[string]string{}
+	for _, license := range licenses {
+		found := false
+		for _, other := range doc.OtherLicenses {
+			if other.LicenseIdentifier == license.ID {
+				found = true
+				break
+			}
+			if strings.HasPrefix(license.ID, "LicenseRef-") && license.ExtractedText != "" && helper.SameLicenseText(other.ExtractedText, license.ExtractedText) {
+				renamed[license.ID] = other.LicenseIdentifier
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+		doc.OtherLicenses = append(doc.OtherLicenses, &v23.OtherLicense{
+			LicenseIdentifier: license.ID,
+			ExtractedText:     license.ExtractedText,
+			LicenseName:       license.Name,
+			LicenseComment:    license.Comments,
+		})
+	}
+	return renamed
+}
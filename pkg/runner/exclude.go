@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package runner
+
+import (
+	"path"
+
+	"github.com/opensbom-generator/parsers/meta"
+)
+
+// excludePackages leaves out the packages whose name, or name@version, matches one of
+// the patterns, as path.Match does, along with the dependencies only they lead to. The
+// root packages are always kept. It returns the number of packages left out
+func excludePackages(packages []meta.Package, patterns []string) ([]meta.Package, int) {
+	if len(patterns) == 0 {
+		return packages, 0
+	}
+
+	excluded := func(pkg *meta.Package) bool {
+		for _, pattern := range patterns {
+			for _, name := range []string{pkg.Name, packageKey(pkg.Name, pkg.Version)} {
+				if matched, _ := path.Match(pattern, name); matched {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	return prunePackages(packages, excluded, false)
+}
+
+// prunePackages leaves out the packages drop returns true for, but the root packages, along with
+// the dependencies only they lead to. The dependencies on the packages left out are dropped too,
+// unless keepReferences is set. It returns the number of packages left out
+func prunePackages(packages []meta.Package, drop func(*meta.Package) bool, keepReferences bool) ([]meta.Package, int) {
+	kept := make([]meta.Package, 0, len(packages))
+	for i := range packages {
+		pkg := packages[i]
+		if !pkg.Root && drop(&pkg) {
+			continue
+		}
+		if !keepReferences {
+			deps := make(map[string]*meta.Package, len(pkg.Packages))
+			for key, dep := range pkg.Packages {
+				if !drop(dep) {
+					deps[key] = dep
+				}
+			}
+			pkg.Packages = deps
+		}
+		kept = append(kept, pkg)
+	}
+
+	pruned := dropUnreached(packages, kept)
+	return pruned, len(packages) - len(pruned)
+}
+
+// dropUnreached leaves out of kept the packages the root packages reached in packages, before
+// they were pruned, but no longer reach. The packages that were never reached are left alone
+func dropUnreached(packages, kept []meta.Package) []meta.Package {
+	before := rootDistances(packages)
+	reached := map[string]bool{}
+	for i := range packages {
+		if before[i] != -1 {
+			reached[packageKey(packages[i].Name, packages[i].Version)] = true
+		}
+	}
+	after := rootDistances(kept)
+	pruned := make([]meta.Package, 0, len(kept))
+	for i := range kept {
+		if after[i] == -1 && reached[packageKey(kept[i].Name, kept[i].Version)] {
+			continue
+		}
+		pruned = append(pruned, kept[i])
+	}
+	return pruned
+}
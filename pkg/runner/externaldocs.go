@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package runner
+
+import (
+	"github.com/opensbom-generator/parsers/meta"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/externaldocs"
+	"github.com/spdx/spdx-sbom-generator/pkg/format"
+	"github.com/spdx/spdx-sbom-generator/pkg/runner/dochandlers/common"
+	"github.com/spdx/spdx-sbom-generator/pkg/runner/options"
+)
+
+// referExternalDocuments leaves out the packages the external documents of the options describe,
+// along with the dependencies only they lead to, the dependencies on them being kept. It returns
+// the packages of the external documents the packages left out refer to, by SPDX identifier,
+// ecosystems mapping the SPDX identifiers to the slug of the parser of each package
+func referExternalDocuments(opts *options.Options, packages []meta.Package, ecosystems map[string]string) ([]meta.Package, map[string]externaldocs.Ref) {
+	refs := map[string]externaldocs.Ref{}
+	if opts.ExternalDocuments.Empty() {
+		return packages, refs
+	}
+
+	lookup := func(pkg *meta.Package) (string, externaldocs.Ref, bool) {
+		id := string(common.SetPkgSPDXIdentifier(pkg.Name, pkg.Version, false))
+		ecosystem := ecosystems["SPDXRef-"+id]
+		ref, ok := opts.ExternalDocuments.Lookup(ecosystem, pkg.Name, pkg.Version, format.PackageURL(ecosystem, pkg.Name, pkg.Version))
+		return id, ref, ok
+	}
+	for i := range packages {
+		candidates := []*meta.Package{}
+		if !packages[i].Root {
+			candidates = append(candidates, &packages[i])
+		}
+		for _, dep := range packages[i].Packages {
+			candidates = append(candidates, dep)
+		}
+		for _, pkg := range candidates {
+			if id, ref, ok := lookup(pkg); ok {
+				refs[id] = ref
+			}
+		}
+	}
+
+	packages, _ = prunePackages(packages, func(pkg *meta.Package) bool {
+		_, _, ok := lookup(pkg)
+		return ok
+	}, true)
+	return packages, refs
+}
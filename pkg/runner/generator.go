@@ -8,6 +8,9 @@ import (
 	"github.com/opensbom-generator/parsers/meta"
 	"github.com/opensbom-generator/parsers/plugin"
 	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spdx/spdx-sbom-generator/pkg/externaldocs"
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
 	"github.com/spdx/spdx-sbom-generator/pkg/runner/dochandlers/common"
 	spdxCommon "github.com/spdx/tools-golang/spdx/common"
 
@@ -22,7 +25,10 @@ type Generator struct {
 
 type DocumentFormatHandler interface {
 	CreateDocument(opts *options.Options, rootPackages []meta.Package) (spdxCommon.AnyDocument, error)
-	AddDocumentPackages(opts *options.Options, doc spdxCommon.AnyDocument, metaPackages []meta.Package) error
+	AddDocumentPackages(opts *options.Options, doc spdxCommon.AnyDocument, metaPackages []meta.Package, scopes common.DependencyScopes) error
+	AddDocumentAnnotation(opts *options.Options, doc spdxCommon.AnyDocument, comment string) error
+	ApplyCurations(opts *options.Options, doc spdxCommon.AnyDocument, ecosystems map[string]string) error
+	AddExternalDocumentRefs(opts *options.Options, doc spdxCommon.AnyDocument, refs map[string]externaldocs.Ref) error
 }
 
 type GeneratorImplementation interface {
@@ -64,34 +70,30 @@ func (g *Generator) CreateSBOM() error {
 
 	g.docHandler = newDocHandler
 
-	// Check the codebase and return the applicable parsers
-	parsers, err := g.implementation.GetCodeParsers(&g.Options)
+	metaPackages, ecosystems, scopes, err := g.parsePackages()
 	if err != nil {
-		return errors.Wrap(err, "error getting applicable parsers")
+		return err
 	}
+	rootPackages := make([]meta.Package, 0)
 
-	var (
-		metaPackages, rootPackages = make([]meta.Package, 0), make([]meta.Package, 0)
-	)
-
-	// Cycle all the applicable parsers and collect the dependency data
-	for _, p := range parsers {
-		// Each parser is passed to the runner implementation who takes
-		// care of running it and returning the results
-		parserPackages, err := g.implementation.RunParser(&g.Options, p)
-		if err != nil {
-			return errors.Wrap(err, "error running parser")
-		}
-
-		metaPackages = append(metaPackages, parserPackages...)
+	// Refer to the packages of the external documents rather than list them and their dependencies
+	metaPackages, externalRefs := referExternalDocuments(&g.Options, metaPackages, ecosystems)
+	if len(externalRefs) > 0 {
+		log.Infof("Referred to %d package(s) of external documents", len(externalRefs))
 	}
 
+	// Prune the dependencies deeper than the requested depth
+	metaPackages, truncated := limitDepth(metaPackages, g.Options.Depth)
+
 	// cycle through all packages found and collect all top-level(root) packages
 	for _, m := range metaPackages {
 		if m.Root {
 			rootPackages = append(rootPackages, m)
 		}
 	}
+	if len(rootPackages) == 0 {
+		return errors.New("no root package found, no plugin matching the project runs")
+	}
 
 	// Get a new empty document from the document handler
 	document, err := g.docHandler.CreateDocument(&g.Options, rootPackages)
@@ -101,14 +103,121 @@ func (g *Generator) CreateSBOM() error {
 
 	// Pass the packages to the doc handler to create the packages. The document
 	// handler knows how to turn the meta packages to native packages (ie SPDX 2.2/2.3)
-	if err = g.docHandler.AddDocumentPackages(&g.Options, document, metaPackages); err != nil {
+	if err = g.docHandler.AddDocumentPackages(&g.Options, document, metaPackages, scopes); err != nil {
 		return fmt.Errorf("adding dependency packages: %w", err)
 	}
 
+	// Point the dependencies on the packages of the external documents to these
+	if len(externalRefs) > 0 {
+		if err = g.docHandler.AddExternalDocumentRefs(&g.Options, document, externalRefs); err != nil {
+			return fmt.Errorf("adding external document references: %w", err)
+		}
+	}
+
+	// Override the metadata of the packages the curations match
+	if len(g.Options.Curations) > 0 {
+		if err = g.docHandler.ApplyCurations(&g.Options, document, ecosystems); err != nil {
+			return fmt.Errorf("applying curations: %w", err)
+		}
+	}
+
+	// Record in the document that it doesn't list the full dependency graph
+	if truncated {
+		if err = g.docHandler.AddDocumentAnnotation(&g.Options, document, depthComment(g.Options.Depth)); err != nil {
+			return fmt.Errorf("adding depth annotation: %w", err)
+		}
+	}
+
 	// Ask the doc handler to write the rendered document to the io writer.
-	if err = common.WriteDocument(&g.Options, document); err != nil {
+	if err = common.WriteDocument(&g.Options, document, ecosystems); err != nil {
 		return fmt.Errorf("writing serialized document: %w", err)
 	}
 
+	// Write the human-readable reports of the document along it
+	if err = common.WriteReports(&g.Options, document, ecosystems); err != nil {
+		return fmt.Errorf("writing reports: %w", err)
+	}
+
+	return nil
+}
+
+// parsePackages runs the parsers applicable to the codebase and returns the packages
+// they found, with the slug of the parser of each, by SPDX identifier, and the scopes
+// of their dependencies
+func (g *Generator) parsePackages() ([]meta.Package, map[string]string, common.DependencyScopes, error) {
+	// Check the codebase and return the applicable parsers
+	parsers, err := g.implementation.GetCodeParsers(&g.Options)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "error getting applicable parsers")
+	}
+
+	metaPackages := make([]meta.Package, 0)
+	ecosystems := map[string]string{}
+
+	// Cycle all the applicable parsers and collect the dependency data
+	for _, p := range parsers {
+		// Each parser is passed to the runner implementation who takes
+		// care of running it and returning the results
+		parserPackages, err := g.implementation.RunParser(&g.Options, p)
+		if err != nil {
+			return nil, nil, nil, errors.Wrap(err, "error running parser")
+		}
+
+		for _, pkg := range parserPackages {
+			ecosystems["SPDXRef-"+string(common.SetPkgSPDXIdentifier(pkg.Name, pkg.Version, pkg.Root))] = p.GetMetadata().Slug
+		}
+		metaPackages = append(metaPackages, parserPackages...)
+	}
+
+	// Leave out the packages excluded by the options
+	metaPackages, excluded := excludePackages(metaPackages, g.Options.ExcludePackages)
+	if excluded > 0 {
+		log.Infof("Excluded %d package(s)", excluded)
+	}
+
+	// Leave out the dependencies of the excluded scopes
+	scopes := dependencyScopes(&g.Options, metaPackages, ecosystems)
+	metaPackages, excluded = excludeScopes(metaPackages, scopes, g.Options.ExcludeScopes)
+	if excluded > 0 {
+		log.Infof("Excluded %d package(s) of the excluded scopes", excluded)
+	}
+
+	if err := setSuppliers(&g.Options, metaPackages); err != nil {
+		return nil, nil, nil, err
+	}
+
+	return metaPackages, ecosystems, scopes, nil
+}
+
+// setSuppliers sets the supplier of the root packages, and of the packages the parsers
+// found none for, to the ones of the options
+func setSuppliers(opts *options.Options, packages []meta.Package) error {
+	parse := func(supplier string) (*meta.Supplier, error) {
+		if supplier == "" {
+			return nil, nil
+		}
+		supplierType, name, email, err := helper.ParseActor(supplier, string(meta.Person), string(meta.Organization))
+		if err != nil {
+			return nil, fmt.Errorf("invalid supplier: %w", err)
+		}
+		return &meta.Supplier{Type: meta.SupplierType(supplierType), Name: name, Email: email}, nil
+	}
+	root, err := parse(opts.RootSupplier)
+	if err != nil {
+		return err
+	}
+	defaultSupplier, err := parse(opts.DefaultSupplier)
+	if err != nil {
+		return err
+	}
+
+	for i := range packages {
+		switch {
+		case packages[i].Root && root != nil:
+			packages[i].Supplier = *root
+		case packages[i].Supplier.Name == "" && defaultSupplier != nil:
+			packages[i].Supplier = *defaultSupplier
+		}
+	}
 	return nil
 }
@@ -33,6 +33,10 @@ func (di *defaultGeneratorImplementation) GetCodeParsers(opts *options.Options)
 	for _, p := range opts.Plugins {
 		path := opts.Path
 		if p.IsValid(path) {
+			if slug := p.GetMetadata().Slug; !opts.PluginEnabled(slug) {
+				log.Infof("Skipping the %s plugin, disabled by the options", slug)
+				continue
+			}
 			if err := p.SetRootModule(path); err != nil {
 				return nil, err
 			}
@@ -60,14 +64,15 @@ func (di *defaultGeneratorImplementation) RunParser(opts *options.Options, plugi
 	opts.SetSlug(plugin.GetMetadata().Slug)
 
 	log.Infof("Current Language Version %s", version)
-	log.Infof("Global Setting File path %s", opts.GlobalSettingFile)
+	globalSettingFile := opts.GlobalSettingFileFor(plugin.GetMetadata().Slug)
+	log.Infof("Global Setting File path %s", globalSettingFile)
 	log.Infof("Parsing %s for packages", opts.Path)
 
 	if moduleErr := plugin.HasModulesInstalled(modulePath); moduleErr != nil {
 		return nil, moduleErr
 	}
 
-	metaPackages, err := plugin.ListModulesWithDeps(modulePath, opts.GlobalSettingFile)
+	metaPackages, err := plugin.ListModulesWithDeps(modulePath, globalSettingFile)
 	if err != nil {
 		return nil, errors.Wrap(err, "error parsing packages")
 	}
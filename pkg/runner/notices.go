@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package runner
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/opensbom-generator/parsers/meta"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/copyright"
+	"github.com/spdx/spdx-sbom-generator/pkg/files"
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/licenses"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/notices"
+	"github.com/spdx/spdx-sbom-generator/pkg/runner/dochandlers/common"
+	"github.com/spdx/spdx-sbom-generator/pkg/runner/options"
+)
+
+// CreateNotices runs the language parsers like CreateSBOM and writes the third-party
+// notices of the packages found, rendered in format with the template of templateFile
+// if any. The root packages, the project itself, are left out
+func (g *Generator) CreateNotices(format notices.Format, templateFile string) error {
+	metaPackages, _, _, err := g.parsePackages()
+	if err != nil {
+		return err
+	}
+
+	title := ""
+	seen := map[string]bool{}
+	packages := []notices.Package{}
+	for _, p := range metaPackages {
+		if p.Root {
+			if title == "" {
+				title = p.Name
+			}
+			continue
+		}
+		if key := packageKey(p.Name, p.Version); !seen[key] {
+			seen[key] = true
+			packages = append(packages, buildNoticesPackage(&g.Options, p))
+		}
+	}
+
+	var f *os.File
+	if g.Options.OutputDir != "" {
+		f, err = os.Create(filepath.Join(g.Options.OutputDir, format.Filename()))
+		if err != nil {
+			return errors.Wrap(err, "error opening file")
+		}
+		defer f.Close()
+	} else {
+		f = os.Stdout
+	}
+
+	w := bufio.NewWriter(f)
+	if err := notices.Render(w, notices.Build(title, packages), format, templateFile); err != nil {
+		return fmt.Errorf("rendering notices: %w", err)
+	}
+	if err := w.Flush(); err != nil {
+		return errors.Wrap(err, "error writing file")
+	}
+
+	log.Infof("Notices written to %s", f.Name())
+	return nil
+}
+
+// buildNoticesPackage collects the license, copyrights and license texts of the package
+func buildNoticesPackage(opts *options.Options, p meta.Package) notices.Package {
+	pkgLicenses := common.BuildLicenses(opts, p)
+	license := pkgLicenses.Concluded
+	if license == common.NoAssertion {
+		license = pkgLicenses.Declared
+	}
+
+	pkg := notices.Package{
+		Name:             p.Name,
+		Version:          p.Version,
+		DownloadLocation: p.PackageDownloadLocation,
+		License:          license,
+		Texts:            buildNoticesTexts(p, license, pkgLicenses),
+	}
+	if homePage := common.BuildHomepageURL(p.PackageURL); homePage != common.NoAssertion {
+		pkg.HomePage = homePage
+	}
+
+	statements, err := copyright.Collect(p.Copyright, p.LocalPath)
+	if err != nil {
+		log.Debugf("scanning the copyrights of %s: %v", p.Name, err)
+	}
+	for _, statement := range statements {
+		pkg.Copyrights = append(pkg.Copyrights, statement.String())
+	}
+	return pkg
+}
+
+// buildNoticesTexts returns the texts of the licenses of the expression, read from the
+// license files of the package, or the other licenses of the parser for LicenseRef-
+// licenses, falling back to the texts of the SPDX license list
+func buildNoticesTexts(p meta.Package, expression string, pkgLicenses common.PackageLicenses) []notices.Text {
+	texts := []notices.Text{}
+	for _, id := range files.ParseLicenseExpression(expression) {
+		id = strings.TrimSuffix(id, "+")
+		if text, ok := findLicenseText(id, pkgLicenses.Files); ok {
+			texts = append(texts, notices.Text{
+				License: id,
+				Source:  fmt.Sprintf("%s of %s", text.File, p.Name),
+				Content: text.ExtractedText,
+			})
+			continue
+		}
+		if text, ok := findLicenseText(id, pkgLicenses.Others); ok {
+			texts = append(texts, notices.Text{License: id, Source: p.Name, Content: text.ExtractedText})
+			continue
+		}
+		if content, ok := licenses.Text(id); ok {
+			texts = append(texts, notices.Text{
+				License: id,
+				Source:  fmt.Sprintf("SPDX License List %s", licenses.Version()),
+				Content: content,
+			})
+		}
+	}
+	return texts
+}
+
+// findLicenseText returns the license of the identifier among those whose text is known,
+// whether they are identified by their SPDX or LicenseRef- identifier
+func findLicenseText(id string, known []*models.License) (*models.License, bool) {
+	for _, license := range known {
+		if license.ExtractedText != "" && (license.ID == id || helper.BuildLicenseConcluded(license.ID) == id) {
+			return license, true
+		}
+	}
+	return nil, false
+}
@@ -3,6 +3,9 @@
 package options
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/opensbom-generator/parsers/cargo"
 	"github.com/opensbom-generator/parsers/composer"
 	"github.com/opensbom-generator/parsers/gem"
@@ -12,14 +15,27 @@ import (
 	"github.com/opensbom-generator/parsers/npm"
 	"github.com/opensbom-generator/parsers/nuget"
 	"github.com/opensbom-generator/parsers/pip"
+	"github.com/opensbom-generator/parsers/pip/pipenv"
+	"github.com/opensbom-generator/parsers/pip/poetry"
+	"github.com/opensbom-generator/parsers/pip/pyenv"
 	"github.com/opensbom-generator/parsers/plugin"
 	"github.com/opensbom-generator/parsers/swift"
 	"github.com/opensbom-generator/parsers/yarn"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/curation"
+	"github.com/spdx/spdx-sbom-generator/pkg/externaldocs"
+	"github.com/spdx/spdx-sbom-generator/pkg/format"
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
 )
 
 const (
 	OutputFormatSpdx OutputFormat = iota
 	OutputFormatJson
+	OutputFormatCsv
+	OutputFormatTsv
+	OutputFormatYaml
+	OutputFormatRdf
 )
 
 var DefaultPlugins = []plugin.Plugin{cargo.New(),
@@ -34,19 +50,57 @@ var DefaultPlugins = []plugin.Plugin{cargo.New(),
 	pip.New(),
 	swift.New()}
 
+// PluginSlugs are the slugs of the plugins of DefaultPlugins, the pip plugin being the one of pipenv, poetry or pyenv
+// depending on the project
+var PluginSlugs = []string{"cargo", "composer", "go-mod", "bundler", "npm", "Java-Gradle", "Java-Maven", "nuget", "yarn", "pipenv", "poetry", "pyenv", "swift"}
+
 type Options struct {
 	SchemaVersion     string // SPDX Version
 	Indent            int
 	Version           string
 	License           bool
-	Depth             string
+	Depth             int // levels of dependencies listed from the root packages, 0 lists them all
 	Slug              string
 	OutputDir         string
 	Schema            string
 	Format            OutputFormat
 	GlobalSettingFile string
 	Path              string
+	AnalyzeFiles      bool    // analyze the files of the source tree of the root packages
+	LicenseThreshold  float32 // confidence a detected license needs to be concluded, 0 for the default
 	Plugins           []plugin.Plugin
+	Reports           []format.ReportFormat // human-readable reports written along the document
+	Columns           []string              // columns of the csv and tsv package lists, all of them if empty
+	ExcludePackages   []string              // patterns of the name, or name@version, of the packages left out
+	ExcludeScopes     []models.Scope        // scopes of the dependencies left out, as dev
+	ExcludePaths      []string              // gitignore patterns of the files left out of the analysis of the files
+	Creators          []string              // creators of the documents besides the tool, as "Person: Jane Doe (jane@example.com)"
+	Namespace         string                // base URI of the document namespaces, https://spdx.org/spdxdocs if empty
+	DocumentName      string                // name of the documents, the one of the root package and its version if empty
+	DocumentComment   string                // comment of the documents
+	RootSupplier      string                // supplier of the root packages, as "Organization: Acme Inc."
+	DefaultSupplier   string                // supplier of the packages the parsers find none for
+	EnablePlugins     []string              // slugs of the only plugins run, all of them if empty
+	DisablePlugins    []string              // slugs of the plugins not run
+	PluginSettings    map[string]PluginSettings
+	Curations         curation.Curations  // curations of the metadata of the packages
+	ExternalDocuments *externaldocs.Index // SPDX documents of the packages referred to rather than listed
+}
+
+// PluginSettings are the settings of a plugin, by slug in Options
+type PluginSettings struct {
+	GlobalSettingFile string
+}
+
+// GlobalSettingFileFor returns the global settings file of the plugin, GlobalSettingFile
+// unless the plugin has its own, the slugs being compared regardless of the case
+func (o *Options) GlobalSettingFileFor(slug string) string {
+	for name, settings := range o.PluginSettings {
+		if helper.SameSlug(name, slug) && settings.GlobalSettingFile != "" {
+			return settings.GlobalSettingFile
+		}
+	}
+	return o.GlobalSettingFile
 }
 
 // SetSlug sets the slug in options.
@@ -62,11 +116,80 @@ func (o OutputFormat) String() string {
 		return "spdx"
 	case 1:
 		return "json"
+	case 2:
+		return "csv"
+	case 3:
+		return "tsv"
+	case 4:
+		return "yaml"
+	case 5:
+		return "rdf"
 	default:
 		return ""
 	}
 }
 
+// ListedPlugins returns the plugins of DefaultPlugins, the pip plugin being the pipenv, poetry and pyenv
+// plugins it runs the one of, so that each has its metadata whatever the project
+func ListedPlugins() []plugin.Plugin {
+	plugins := make([]plugin.Plugin, 0, len(DefaultPlugins)+2)
+	for _, p := range DefaultPlugins {
+		if _, ok := p.(*pip.PIP); ok {
+			plugins = append(plugins, pipenv.New(), poetry.New(), pyenv.New())
+			continue
+		}
+		plugins = append(plugins, p)
+	}
+	return plugins
+}
+
+// AddExternalPlugin adds the plugin run out of process to DefaultPlugins, and its slug to PluginSlugs,
+// unless a plugin has the slug already
+func AddExternalPlugin(p plugin.Plugin) error {
+	slug := p.GetMetadata().Slug
+	if taken := pluginSlug(slug); taken != "" {
+		return fmt.Errorf("a plugin has the slug %s already", taken)
+	}
+	DefaultPlugins = append(DefaultPlugins, p)
+	PluginSlugs = append(PluginSlugs, slug)
+	Default.Plugins = DefaultPlugins
+	return nil
+}
+
+// CheckPluginSlugs checks that the names are slugs of PluginSlugs, regardless of the case and the dashes
+func CheckPluginSlugs(names []string) error {
+	for _, name := range names {
+		if pluginSlug(name) == "" {
+			return fmt.Errorf("unknown plugin %q, expected one of %s", name, strings.Join(PluginSlugs, ", "))
+		}
+	}
+	return nil
+}
+
+// PluginEnabled tells whether the plugin of the slug runs, EnablePlugins listing the only plugins run, if any,
+// and DisablePlugins the plugins that don't
+func (o *Options) PluginEnabled(slug string) bool {
+	contains := func(names []string) bool {
+		for _, name := range names {
+			if pluginSlug(name) == slug {
+				return true
+			}
+		}
+		return false
+	}
+	return (len(o.EnablePlugins) == 0 || contains(o.EnablePlugins)) && !contains(o.DisablePlugins)
+}
+
+// pluginSlug returns the slug of PluginSlugs the name is, empty if none
+func pluginSlug(name string) string {
+	for _, slug := range PluginSlugs {
+		if helper.SameSlug(name, slug) {
+			return slug
+		}
+	}
+	return ""
+}
+
 var Default = Options{
 	Plugins: DefaultPlugins,
 }
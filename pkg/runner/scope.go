@@ -0,0 +1,147 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package runner
+
+import (
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/opensbom-generator/parsers/meta"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/manifest"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/runner/dochandlers/common"
+	"github.com/spdx/spdx-sbom-generator/pkg/runner/options"
+)
+
+// dependencyScopes returns the scopes of the dependencies of the packages, read from the
+// manifests of the ecosystem of the root packages since the parsers don't record them. The
+// scopes the roots declare their direct dependencies with are propagated to the packages only
+// these lead to, the packages also reachable through runtime dependencies staying runtime ones
+func dependencyScopes(opts *options.Options, packages []meta.Package, ecosystems map[string]string) common.DependencyScopes {
+	scopes := common.DependencyScopes{}
+	find := packageFinder(packages)
+	// the key of the package a dependency stands for
+	keyOf := func(dep *meta.Package) string {
+		if i, ok := find(dep); ok {
+			return packageKey(packages[i].Name, packages[i].Version)
+		}
+		return packageKey(dep.Name, dep.Version)
+	}
+
+	direct := map[string]models.Scope{}
+	graph := map[string][]string{}
+	for _, pkg := range packages {
+		if !pkg.Root {
+			deps := make([]string, 0, len(pkg.Packages))
+			for _, dep := range pkg.Packages {
+				deps = append(deps, keyOf(dep))
+			}
+			graph[packageKey(pkg.Name, pkg.Version)] = deps
+			continue
+		}
+		ecosystem := ecosystems["SPDXRef-"+string(common.SetPkgSPDXIdentifier(pkg.Name, pkg.Version, pkg.Root))]
+		dir := pkg.LocalPath
+		if info, err := os.Stat(dir); dir == "" || err != nil || !info.IsDir() {
+			dir = opts.Path
+		}
+
+		declared := indexScopes(ecosystem, manifest.Read(ecosystem, dir, opts.GlobalSettingFileFor(ecosystem)))
+		for _, dep := range pkg.Packages {
+			scope, ok := declared[scopeName(ecosystem, dep.Name)]
+			if ok {
+				scopes.Set(pkg, *dep, scope)
+			}
+			key := keyOf(dep)
+			if previous, ok := direct[key]; !ok || precedes(scope, previous) {
+				direct[key] = scope
+			}
+		}
+	}
+
+	propagated := manifest.Propagate(direct, graph)
+	for _, pkg := range packages {
+		if pkg.Root {
+			continue
+		}
+		for _, dep := range pkg.Packages {
+			if scope := propagated[keyOf(dep)]; scope != models.ScopeRuntime {
+				scopes.Set(pkg, *dep, scope)
+			}
+		}
+	}
+	return scopes
+}
+
+// precedes tells whether the scope comes before the other in manifest.Precedence
+func precedes(scope, other models.Scope) bool {
+	for _, s := range manifest.Precedence {
+		if s == other {
+			return false
+		}
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// indexScopes returns the scope of the dependencies by name, a dependency declared several times
+// keeping the first scope it is declared with
+func indexScopes(ecosystem string, declared []manifest.Dependency) map[string]models.Scope {
+	index := map[string]models.Scope{}
+	for _, d := range declared {
+		names := []string{d.Name}
+		// the parsers name the Java packages after their artifact
+		if i := strings.LastIndex(d.Name, ":"); i != -1 {
+			names = append(names, d.Name[i+1:])
+		}
+		for _, name := range names {
+			if _, ok := index[scopeName(ecosystem, name)]; !ok {
+				index[scopeName(ecosystem, name)] = d.Scope
+			}
+		}
+	}
+	return index
+}
+
+var pythonNameSeparators = regexp.MustCompile(`[-_.]+`)
+
+// scopeName normalizes the name of a package of the ecosystem as its manifests and its parsers
+// name it: without the @ of the npm scopes, and as PEP 503 does for Python
+func scopeName(ecosystem, name string) string {
+	switch ecosystem {
+	case "pipenv", "poetry", "pyenv":
+		return pythonNameSeparators.ReplaceAllString(strings.ToLower(name), "-")
+	case "npm", "yarn":
+		return strings.TrimPrefix(name, "@")
+	}
+	return name
+}
+
+// excludeScopes leaves out the dependencies of the root packages of the excluded scopes, along
+// with the packages only they lead to. It returns the number of packages left out
+func excludeScopes(packages []meta.Package, scopes common.DependencyScopes, excluded []models.Scope) ([]meta.Package, int) {
+	if len(excluded) == 0 || len(scopes) == 0 {
+		return packages, 0
+	}
+	isExcluded := map[models.Scope]bool{}
+	for _, scope := range excluded {
+		isExcluded[scope] = true
+	}
+
+	kept := make([]meta.Package, 0, len(packages))
+	for _, pkg := range packages {
+		deps := make(map[string]*meta.Package, len(pkg.Packages))
+		for key, dep := range pkg.Packages {
+			if !isExcluded[scopes.Get(pkg, *dep)] {
+				deps[key] = dep
+			}
+		}
+		pkg.Packages = deps
+		kept = append(kept, pkg)
+	}
+	pruned := dropUnreached(packages, kept)
+	return pruned, len(packages) - len(pruned)
+}
@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opensbom-generator/parsers/meta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/manifest"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/runner/options"
+)
+
+func writeManifest(t *testing.T, dir, name, contents string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(contents), 0644))
+}
+
+// scopedProject is an app depending on express at runtime and on jest, which depends on
+// chalk and jest-cli, for development. Express depends on chalk too
+func scopedProject(dir string) []meta.Package {
+	chalk := &meta.Package{Name: "chalk", Version: "4.1.2"}
+	cli := &meta.Package{Name: "jest-cli", Version: "29.7.0", Packages: map[string]*meta.Package{"chalk": chalk}}
+	express := &meta.Package{Name: "express", Version: "4.18.2", Packages: map[string]*meta.Package{"chalk": chalk}}
+	jest := &meta.Package{Name: "jest", Version: "29.7.0", Packages: map[string]*meta.Package{"chalk": chalk, "jest-cli": cli}}
+	types := &meta.Package{Name: "types/node", Version: "20.0.0"}
+	return []meta.Package{
+		{Name: "app", Version: "1.0.0", Root: true, LocalPath: dir, Packages: map[string]*meta.Package{
+			"express": express, "jest": jest, "types/node": types,
+		}},
+		*express, *jest, *types, *chalk, *cli,
+	}
+}
+
+func TestDependencyScopes(t *testing.T) {
+	dir := t.TempDir()
+	writeManifest(t, dir, "package.json", `{
+  "dependencies": {"express": "^4.18.2"},
+  "devDependencies": {"jest": "^29.7.0", "@types/node": "^20.0.0", "express": "^4.18.2"}
+}`)
+	packages := scopedProject(dir)
+	ecosystems := map[string]string{"SPDXRef-app": "npm"}
+
+	scopes := dependencyScopes(&options.Options{Path: dir}, packages, ecosystems)
+	root := packages[0]
+	assert.Equal(t, models.ScopeDev, scopes.Get(root, packages[2]))
+	assert.Equal(t, models.ScopeDev, scopes.Get(root, packages[3]))
+	// the dependencies declared at runtime too are runtime ones
+	assert.Equal(t, models.ScopeRuntime, scopes.Get(root, packages[1]))
+	assert.Equal(t, models.DevDependencyOf, scopes.Relationship(root, packages[2]))
+	assert.Equal(t, models.DependsOn, scopes.Relationship(packages[2], packages[4]))
+	// jest-cli is only reachable through jest, chalk through express too
+	assert.Equal(t, models.ScopeDev, scopes.Get(packages[2], packages[5]))
+	assert.Equal(t, models.DevDependencyOf, scopes.Relationship(packages[2], packages[5]))
+	assert.Equal(t, models.ScopeRuntime, scopes.Get(packages[5], packages[4]))
+
+	// leaving out the dev dependencies keeps chalk, which express depends on
+	pruned, excluded := excludeScopes(packages, scopes, []models.Scope{models.ScopeDev})
+	assert.Equal(t, 3, excluded)
+	names := []string{}
+	for _, pkg := range pruned {
+		names = append(names, pkg.Name)
+	}
+	assert.Equal(t, []string{"app", "express", "chalk"}, names)
+	assert.Len(t, pruned[0].Packages, 1)
+
+	pruned, excluded = excludeScopes(packages, scopes, []models.Scope{models.ScopeTest})
+	assert.Equal(t, 0, excluded)
+	assert.Len(t, pruned, 6)
+}
+
+func TestIndexScopes(t *testing.T) {
+	declared := []manifest.Dependency{
+		{Name: "junit:junit", Scope: models.ScopeTest},
+		{Name: "javax.servlet:servlet-api", Scope: models.ScopeProvided},
+	}
+	index := indexScopes("Java-Maven", declared)
+	// the parsers name the Java packages after their artifact
+	assert.Equal(t, models.ScopeTest, index["junit"])
+	assert.Equal(t, models.ScopeTest, index["junit:junit"])
+	assert.Equal(t, models.ScopeProvided, index["servlet-api"])
+
+	declared = []manifest.Dependency{
+		{Name: "requests", Scope: models.ScopeRuntime},
+		{Name: "Typing_Extensions", Scope: models.ScopeDev},
+	}
+	index = indexScopes("poetry", declared)
+	assert.Equal(t, models.ScopeDev, index[scopeName("poetry", "typing-extensions")])
+	_, ok := index["requests"]
+	assert.True(t, ok)
+}